@@ -7,21 +7,57 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/0xalexb/hjarta-di/logging"
-
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
 )
 
 var errAppNotInitialized = errors.New("app not initialized")
 
+// ErrAppAlreadyBuilt is returned by Populate when the underlying Fx application
+// has already been built by a previous Populate, Start, or Run call.
+var ErrAppAlreadyBuilt = errors.New("app already built")
+
+// ErrAlreadyStarted is returned by Start when the App is not idle, i.e. it has
+// already been started (or run), is running, or has been stopped.
+var ErrAlreadyStarted = errors.New("app already started")
+
+// ErrNotStarted is returned by Stop when the App is not running.
+var ErrNotStarted = errors.New("app not started")
+
+// App lifecycle states. Transitions only move forward:
+// stateIdle -> stateStarting -> stateRunning -> stateStopping -> stateStopped.
+const (
+	stateIdle int32 = iota
+	stateStarting
+	stateRunning
+	stateStopping
+	stateStopped
+)
+
 // App is a configured starting point for application using Fx.
+// The underlying fx.App is built lazily on the first Populate, Start, or Run call.
 type App struct {
+	options Options
+	logger  *slog.Logger
+
+	mu  sync.Mutex
 	app *fx.App
+
+	state atomic.Int32
 }
 
 // NewApp creates a new instance of App with Fx configured.
+//
+// Behavior change: NewApp no longer builds the Fx application. fx.New, and with it every
+// fx.Invoke function and the providers they depend on, runs on the first Populate,
+// CheckDependencies, Start, or Run call instead, so that Populate can add its targets.
+// Code that relied on invokes running inside NewApp, or on dependency errors being
+// logged there, should call CheckDependencies right after NewApp to get the former
+// eager behavior, with the error returned rather than logged.
 func NewApp(opts ...Option) *App {
 	var options Options
 
@@ -29,66 +65,202 @@ func NewApp(opts ...Option) *App {
 		apply(&options)
 	}
 
+	options.applyEnvOptions()
+
+	logger := createLogger(options.LogLevel, os.Stderr, options.LogHandlers...)
+	slog.SetDefault(logger)
+
 	return &App{
-		app: configure(&options),
+		options: options,
+		logger:  logger,
 	}
 }
 
-func configure(options *Options) *fx.App {
-	logger := createLogger(options.LogLevel, os.Stderr)
-	slog.SetDefault(logger)
-
+func configure(options *Options, logger *slog.Logger, extra ...fx.Option) *fx.App {
 	return fx.New(
 		fx.WithLogger(func() fxevent.Logger {
-			return &fxevent.SlogLogger{Logger: logger}
+			return newFxEventLogger(logger, options.FxEventLogLevels)
 		}),
 		fx.Supply(logging.LoggerConfig{Level: options.LogLevel}),
 		fx.Supply(logger),
 		fx.Options(options.Modules...),
+		fx.Options(extra...),
 	)
 }
 
-func createLogger(level string, w io.Writer) *slog.Logger {
+func createLogger(level string, w io.Writer, additional ...slog.Handler) *slog.Logger {
 	config := logging.LoggerConfig{Level: level}
 
-	return logging.NewLogger(config, w)
+	return logging.NewLogger(config, w, additional...)
 }
 
-// Start starts the Fx application.
+// initialized reports whether the App was created via NewApp.
+func (app *App) initialized() bool {
+	return app != nil && app.logger != nil
+}
+
+// fxApp returns the underlying fx.App, building it on first use.
+func (app *App) fxApp() *fx.App {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if app.app == nil {
+		app.app = configure(&app.options, app.logger)
+	}
+
+	return app.app
+}
+
+// Populate builds the underlying Fx application and fills the given pointer targets
+// with values from the DI container, as fx.Populate does. It is intended for tests
+// that need to extract a specific dependency without fx.Invoke boilerplate.
+// Because Fx resolves populate targets at construction time, Populate must be called
+// once, before Start or Run; otherwise it returns ErrAppAlreadyBuilt.
+func (app *App) Populate(targets ...any) error {
+	if !app.initialized() {
+		return errAppNotInitialized
+	}
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if app.app != nil {
+		return ErrAppAlreadyBuilt
+	}
+
+	app.app = configure(&app.options, app.logger, fx.Populate(targets...))
+
+	err := app.app.Err()
+	if err != nil {
+		return fmt.Errorf("failed to populate targets: %w", err)
+	}
+
+	return nil
+}
+
+// CheckDependencies builds the underlying Fx application and returns the error Fx
+// recorded while resolving the dependency graph, such as a cycle or a missing
+// provider, without running any lifecycle hooks. It lets build pipelines validate
+// the DI graph without starting the application. Like Populate, it builds the
+// application, so Populate cannot be called afterwards; Start and Run still can.
+func (app *App) CheckDependencies() error {
+	if !app.initialized() {
+		return errAppNotInitialized
+	}
+
+	err := app.fxApp().Err()
+	if err != nil {
+		return fmt.Errorf("invalid dependency graph: %w", err)
+	}
+
+	return nil
+}
+
+// OptionsJSON returns the options the App was created with, after WithEnvOptions were
+// applied, serialized by Options.MarshalJSON. It is meant for debugging and bug reports
+// and does not build the application.
+func (app *App) OptionsJSON() ([]byte, error) {
+	if !app.initialized() {
+		return nil, errAppNotInitialized
+	}
+
+	return app.options.MarshalJSON()
+}
+
+// Start starts the Fx application. It is StartContext with context.Background().
+// An App can be started only once: further calls return ErrAlreadyStarted.
+// Dependency errors reported by CheckDependencies fail Start before any hook runs.
+// If starting fails, the App is considered stopped.
 func (app *App) Start() error {
-	if app != nil && app.app != nil {
-		err := app.app.Start(context.Background())
-		if err != nil {
-			return fmt.Errorf("failed to start app: %w", err)
-		}
+	return app.StartContext(context.Background())
+}
+
+// StartContext is Start with ctx passed to the OnStart hooks, so a caller can bound or
+// cancel startup. If ctx is already done, it returns its error immediately and the App
+// stays idle.
+func (app *App) StartContext(ctx context.Context) error {
+	if !app.initialized() {
+		return errAppNotInitialized
+	}
+
+	err := ctx.Err()
+	if err != nil {
+		return fmt.Errorf("failed to start app: %w", err)
+	}
+
+	if !app.state.CompareAndSwap(stateIdle, stateStarting) {
+		return ErrAlreadyStarted
+	}
+
+	err = app.CheckDependencies()
+	if err != nil {
+		app.state.Store(stateStopped)
+
+		return err
+	}
+
+	err = app.fxApp().Start(ctx)
+	if err != nil {
+		app.state.Store(stateStopped)
 
-		return nil
+		return fmt.Errorf("failed to start app: %w", err)
 	}
 
-	return errAppNotInitialized
+	app.state.Store(stateRunning)
+
+	return nil
 }
 
 // Run starts the application and blocks until an OS signal is received, then shuts down gracefully.
+// Like Start, it can be used only on an idle App.
 func (app *App) Run() {
-	if app == nil || app.app == nil {
+	if !app.initialized() {
 		slog.Error("attempted to run an uninitialized app")
 
 		return
 	}
 
-	app.app.Run()
+	if !app.state.CompareAndSwap(stateIdle, stateRunning) {
+		slog.Error("attempted to run an app that was already started")
+
+		return
+	}
+
+	defer app.state.Store(stateStopped)
+
+	app.fxApp().Run()
 }
 
-// Stop stops the Fx application gracefully.
+// Stop stops the Fx application gracefully. It is StopContext with context.Background().
+// It returns ErrNotStarted unless the App is running, so stopping an App that was
+// never started, or stopping it twice, is reported rather than passed to Fx.
 func (app *App) Stop() error {
-	if app != nil && app.app != nil {
-		err := app.app.Stop(context.Background())
-		if err != nil {
-			return fmt.Errorf("failed to stop app: %w", err)
-		}
+	return app.StopContext(context.Background())
+}
+
+// StopContext is Stop with ctx passed to the OnStop hooks, so a caller can bound
+// shutdown. If ctx is already done, it returns its error immediately and the App
+// keeps running.
+func (app *App) StopContext(ctx context.Context) error {
+	if !app.initialized() {
+		return errAppNotInitialized
+	}
+
+	err := ctx.Err()
+	if err != nil {
+		return fmt.Errorf("failed to stop app: %w", err)
+	}
+
+	if !app.state.CompareAndSwap(stateRunning, stateStopping) {
+		return ErrNotStarted
+	}
+
+	defer app.state.Store(stateStopped)
 
-		return nil
+	err = app.fxApp().Stop(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to stop app: %w", err)
 	}
 
-	return errAppNotInitialized
+	return nil
 }
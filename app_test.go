@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"testing"
 
@@ -121,6 +122,38 @@ func TestNewApp_LoggerRespectsLogLevel(t *testing.T) {
 	require.NotEmpty(t, buf.String(), "error log should be written when level is error")
 }
 
+func TestNewApp_WithAdditionalLogHandler(t *testing.T) {
+	t.Parallel()
+
+	var first, second bytes.Buffer
+
+	app := di.NewApp(
+		di.WithLogLevel("warn"),
+		di.WithAdditionalLogHandler(slog.NewJSONHandler(&first, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		di.WithAdditionalLogHandler(slog.NewJSONHandler(&second, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		di.WithAdditionalLogHandler(nil),
+	)
+
+	var logger *slog.Logger
+
+	require.NoError(t, app.Populate(&logger))
+
+	// Fx events are logged at info level, below the configured warn level.
+	require.Empty(t, first.String())
+
+	logger.Info("filtered by the app level")
+	logger.Warn("fan out", slog.String("key", "value"))
+
+	for _, buf := range []*bytes.Buffer{&first, &second} {
+		var logEntry map[string]any
+
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logEntry), "handler should receive exactly one record")
+		require.Equal(t, "fan out", logEntry["msg"])
+		require.Equal(t, "value", logEntry["key"])
+		require.Equal(t, "WARN", logEntry["level"])
+	}
+}
+
 func TestNewApp_LoggerConfigIsSupplied(t *testing.T) {
 	t.Parallel()
 
@@ -284,3 +317,271 @@ func TestApp_Run(t *testing.T) {
 		app.Run()
 	})
 }
+
+func TestApp_Populate(t *testing.T) {
+	t.Parallel()
+
+	var injected *slog.Logger
+
+	module := fx.Module("test",
+		fx.Invoke(func(logger *slog.Logger) {
+			injected = logger
+		}),
+	)
+
+	app := di.NewApp(di.WithModules(module))
+
+	var logger *slog.Logger
+
+	err := app.Populate(&logger)
+	require.NoError(t, err)
+	require.NotNil(t, logger)
+	require.Same(t, injected, logger, "populated logger should be the injected instance")
+
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+}
+
+func TestApp_PopulateAfterStart(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp()
+
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+
+	var logger *slog.Logger
+
+	err := app.Populate(&logger)
+	require.ErrorIs(t, err, di.ErrAppAlreadyBuilt)
+	require.Nil(t, logger)
+}
+
+func TestApp_PopulateMissingType(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp()
+
+	var missing *bytes.Buffer
+
+	err := app.Populate(&missing)
+	require.Error(t, err)
+}
+
+func TestApp_PopulateOnNilApp(t *testing.T) {
+	t.Parallel()
+
+	var app *di.App
+
+	var logger *slog.Logger
+
+	err := app.Populate(&logger)
+	require.Error(t, err)
+}
+
+func TestApp_StartTwice(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp()
+
+	require.NoError(t, app.Start())
+
+	t.Cleanup(func() { _ = app.Stop() })
+
+	err := app.Start()
+	require.ErrorIs(t, err, di.ErrAlreadyStarted)
+}
+
+func TestApp_StopBeforeStart(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp()
+
+	err := app.Stop()
+	require.ErrorIs(t, err, di.ErrNotStarted)
+}
+
+func TestApp_StopTwice(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp()
+
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+
+	err := app.Stop()
+	require.ErrorIs(t, err, di.ErrNotStarted)
+}
+
+func TestApp_StartAfterStop(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp()
+
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+
+	err := app.Start()
+	require.ErrorIs(t, err, di.ErrAlreadyStarted)
+}
+
+func TestApp_StartFailureAllowsNoStop(t *testing.T) {
+	t.Parallel()
+
+	module := fx.Module("test",
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.Hook{
+				OnStart: func(_ context.Context) error {
+					return errors.New("boom")
+				},
+			})
+		}),
+	)
+
+	app := di.NewApp(di.WithModules(module))
+
+	require.Error(t, app.Start())
+	require.ErrorIs(t, app.Stop(), di.ErrNotStarted)
+	require.ErrorIs(t, app.Start(), di.ErrAlreadyStarted)
+}
+
+func TestApp_StartContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	started := false
+
+	module := fx.Module("test",
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					started = true
+
+					return nil
+				},
+			})
+		}),
+	)
+
+	app := di.NewApp(di.WithModules(module))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, app.StartContext(ctx), context.Canceled)
+	require.False(t, started, "no hook should run with a cancelled context")
+
+	require.NoError(t, app.Start(), "a cancelled StartContext should leave the App idle")
+	require.True(t, started)
+	require.NoError(t, app.Stop())
+}
+
+func TestApp_StartContextPassedToHooks(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	var hookValue any
+
+	module := fx.Module("test",
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					hookValue = ctx.Value(ctxKey{})
+
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					return ctx.Err()
+				},
+			})
+		}),
+	)
+
+	app := di.NewApp(di.WithModules(module))
+
+	require.NoError(t, app.StartContext(context.WithValue(context.Background(), ctxKey{}, "start")))
+	require.Equal(t, "start", hookValue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, app.StopContext(ctx), context.Canceled)
+	require.NoError(t, app.StopContext(context.Background()), "a cancelled StopContext should leave the App running")
+}
+
+type cycleA struct{}
+
+type cycleB struct{}
+
+// cyclicModule provides cycleA and cycleB depending on each other.
+func cyclicModule() fx.Option {
+	return fx.Module("cycle",
+		fx.Provide(
+			func(*cycleB) *cycleA { return &cycleA{} },
+			func(*cycleA) *cycleB { return &cycleB{} },
+		),
+		fx.Invoke(func(*cycleA) {}),
+	)
+}
+
+func TestApp_CheckDependenciesDetectsCycle(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp(di.WithModules(cyclicModule()))
+
+	err := app.CheckDependencies()
+	require.Error(t, err)
+	require.ErrorContains(t, err, "cycle")
+
+	startErr := app.Start()
+	require.Error(t, startErr)
+	require.Equal(t, err.Error(), startErr.Error(), "Start should fail fast with the dependency error")
+	require.ErrorIs(t, app.Stop(), di.ErrNotStarted)
+}
+
+func TestApp_CheckDependenciesMissingProvider(t *testing.T) {
+	t.Parallel()
+
+	started := false
+
+	module := fx.Module("test",
+		fx.Invoke(func(lc fx.Lifecycle) {
+			lc.Append(fx.Hook{
+				OnStart: func(context.Context) error {
+					started = true
+
+					return nil
+				},
+			})
+		}),
+		fx.Invoke(func(*bytes.Buffer) {}),
+	)
+
+	app := di.NewApp(di.WithModules(module))
+
+	require.Error(t, app.CheckDependencies())
+	require.Error(t, app.Start())
+	require.False(t, started, "no hook should run when the graph is invalid")
+}
+
+func TestApp_CheckDependenciesValidGraph(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp()
+
+	require.NoError(t, app.CheckDependencies())
+
+	var logger *slog.Logger
+	require.ErrorIs(t, app.Populate(&logger), di.ErrAppAlreadyBuilt)
+
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+}
+
+func TestApp_CheckDependenciesOnNilApp(t *testing.T) {
+	t.Parallel()
+
+	var app *di.App
+
+	require.Error(t, app.CheckDependencies())
+}
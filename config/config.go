@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
 )
 
 // Parser defines an interface for parsing configuration data into a target structure.
@@ -24,7 +26,17 @@ type DataFetcher interface {
 	Fetch() ([]byte, error)
 }
 
+// IdentifiedDataFetcher is a DataFetcher that can name its source, e.g. a file path.
+// Provider and FirstAvailable include the name in fetch errors so the failing source
+// can be identified when several are in use.
+type IdentifiedDataFetcher interface {
+	DataFetcher
+	SourceName() string
+}
+
 // Validator defines an interface for validating configuration structures.
+// Validate may return *FieldError values (see NewFieldError), alone or joined with
+// errors.Join, to report which config key is invalid.
 type Validator interface {
 	Validate() error
 }
@@ -34,35 +46,123 @@ type Defaulter interface {
 	SetDefaults() (changed bool)
 }
 
+// Redactor defines an interface for configuration structures that can describe themselves
+// with secrets masked, e.g. by returning a copy with the password replaced by "***".
+// Redact must not return the receiver itself unless it holds no secrets.
+type Redactor interface {
+	Redact() any
+}
+
 // Provider returns a function that reads, parses, sets defaults, and validates configuration data.
+// Targets implementing Validator are validated by Validate; otherwise `validate` struct tags,
+// if any, are checked with ValidateStruct. Once valid, targets implementing Redactor are
+// logged at info level as the "effective config" using the result of Redact; other
+// targets are never logged, so their secrets cannot leak into logs.
 func Provider[T any](target *T, path string) func(Parser, DataFetcher) (*T, error) {
 	return func(parser Parser, dataSourcer DataFetcher) (*T, error) {
-		data, err := dataSourcer.Fetch()
-		if err != nil {
-			return nil, fmt.Errorf("reading data error: %w", err)
+		return load(target, path, parser, dataSourcer)
+	}
+}
+
+// ProviderFresh is like Provider but allocates a new T on every invocation instead of
+// writing into a caller-supplied target. Repeated calls never share state, which makes
+// it safe to reuse the same provider across Fx modules or re-provisioning.
+func ProviderFresh[T any](path string) func(Parser, DataFetcher) (*T, error) {
+	return func(parser Parser, dataSourcer DataFetcher) (*T, error) {
+		return load(new(T), path, parser, dataSourcer)
+	}
+}
+
+// ProviderWithValidators is like Provider but also runs validators, for rules kept
+// outside the config type such as a shared policy. They run after the target's own
+// validation (Validate or `validate` struct tags) even if it failed, and all failures
+// are joined into one error, e.g.
+//
+//	config.ProviderWithValidators(&cfg, "server", func(c *ServerConfig) error {
+//		if c.Port < 1024 {
+//			return config.NewFieldError("server.port", "must not be a privileged port")
+//		}
+//
+//		return nil
+//	})
+//
+// Nil validators are skipped.
+func ProviderWithValidators[T any](target *T, path string, validators ...func(*T) error) func(Parser, DataFetcher) (*T, error) {
+	return func(parser Parser, dataSourcer DataFetcher) (*T, error) {
+		return load(target, path, parser, dataSourcer, validators...)
+	}
+}
+
+// load fetches, parses, applies defaults to, and validates configuration into target,
+// running validators after the target's own validation.
+func load[T any](target *T, path string, parser Parser, dataSourcer DataFetcher, validators ...func(*T) error) (*T, error) {
+	data, err := dataSourcer.Fetch()
+	if err != nil {
+		if identified, ok := dataSourcer.(IdentifiedDataFetcher); ok {
+			return nil, fmt.Errorf("reading data error from %s: %w", identified.SourceName(), err)
 		}
 
-		err = parser.Parse(data, target, path)
+		return nil, fmt.Errorf("reading data error: %w", err)
+	}
+
+	err = parser.Parse(data, target, path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing error: %w", err)
+	}
+
+	targetDefaulter, isDefaulter := any(target).(Defaulter)
+	if isDefaulter {
+		changed := targetDefaulter.SetDefaults()
+		if changed {
+			slog.Info("defaults applied", slog.String("path", path))
+		}
+	}
+
+	err = validate(target, path, validators)
+	if err != nil {
+		return nil, fmt.Errorf("validating error: %w", err)
+	}
+
+	if redactor, ok := any(target).(Redactor); ok {
+		slog.Info("effective config", slog.String("path", path), slog.Any("config", redactor.Redact()))
+	}
+
+	return target, nil
+}
+
+// validate checks target with its Validate method, or its `validate` struct tags when
+// it has none, then with each of validators, and joins the failures. Tag failures get
+// path, the config path target was decoded from, prefixed to their FieldError.Path.
+func validate[T any](target *T, path string, validators []func(*T) error) error {
+	var errs []error
+
+	targetValidatable, isValidatable := any(target).(Validator)
+	if isValidatable {
+		err := targetValidatable.Validate()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	} else if hasValidateTags(reflect.TypeFor[T]()) {
+		err := validateStruct(target, path)
 		if err != nil {
-			return nil, fmt.Errorf("parsing error: %w", err)
+			errs = append(errs, err)
 		}
+	}
 
-		targetDefaulter, isDefaulter := any(target).(Defaulter)
-		if isDefaulter {
-			changed := targetDefaulter.SetDefaults()
-			if changed {
-				slog.Info("defaults applied", slog.String("path", path))
-			}
+	for _, validator := range validators {
+		if validator == nil {
+			continue
 		}
 
-		targetValidatable, isValidatable := any(target).(Validator)
-		if isValidatable {
-			err := targetValidatable.Validate()
-			if err != nil {
-				return nil, fmt.Errorf("validating error: %w", err)
-			}
+		err := validator(target)
+		if err != nil {
+			errs = append(errs, err)
 		}
+	}
 
-		return target, nil
+	if len(errs) == 1 {
+		return errs[0]
 	}
+
+	return errors.Join(errs...)
 }
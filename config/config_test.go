@@ -1,7 +1,11 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"log/slog"
+	"strings"
 	"testing"
 )
 
@@ -226,6 +230,38 @@ func TestProvider_Errors(t *testing.T) {
 	}
 }
 
+// identifiedFetcher is a mockDataFetcher that names its source.
+type identifiedFetcher struct {
+	mockDataFetcher
+
+	name string
+}
+
+func (f *identifiedFetcher) SourceName() string {
+	return f.name
+}
+
+func TestProvider_FetchErrorIncludesSourceName(t *testing.T) {
+	t.Parallel()
+
+	fetchErr := errors.New("permission denied")
+	parser := &mockParser{parseFunc: func([]byte, any, string) error { return nil }}
+	fetcher := &identifiedFetcher{
+		mockDataFetcher: mockDataFetcher{fetchFunc: func() ([]byte, error) { return nil, fetchErr }},
+		name:            "/etc/app/config.yaml",
+	}
+
+	_, err := Provider(&simpleConfig{}, "")(parser, fetcher)
+	if !errors.Is(err, fetchErr) {
+		t.Fatalf("expected error to wrap %v, got %v", fetchErr, err)
+	}
+
+	want := "reading data error from /etc/app/config.yaml: permission denied"
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
 func TestProvider_Defaults(t *testing.T) {
 	t.Parallel()
 
@@ -272,3 +308,189 @@ func TestProvider_Defaults(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderFresh_AllocatesNewTargetPerInvocation(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	parser := &mockParser{
+		parseFunc: func(data []byte, target any, _ string) error {
+			cfg, ok := target.(*simpleConfig)
+			if !ok {
+				return errors.New("invalid target type")
+			}
+
+			calls++
+			cfg.Name += string(data)
+
+			return nil
+		},
+	}
+
+	provider := ProviderFresh[simpleConfig]("test/path")
+
+	first, err := provider(parser, &mockDataFetcher{fetchFunc: func() ([]byte, error) {
+		return []byte("first"), nil
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := provider(parser, &mockDataFetcher{fetchFunc: func() ([]byte, error) {
+		return []byte("second"), nil
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first == second {
+		t.Fatal("expected distinct pointers for each invocation")
+	}
+
+	if first.Name != "first" {
+		t.Errorf("expected first Name to be 'first', got %q", first.Name)
+	}
+
+	if second.Name != "second" {
+		t.Errorf("expected second Name to be 'second', got %q (state leaked between invocations)", second.Name)
+	}
+
+	if calls != 2 {
+		t.Errorf("expected parser to be called twice, got %d", calls)
+	}
+}
+
+func TestProviderFresh_RunsValidation(t *testing.T) {
+	t.Parallel()
+
+	parser := &mockParser{
+		parseFunc: func(_ []byte, target any, _ string) error {
+			cfg, ok := target.(*configWithValidator)
+			if !ok {
+				return errors.New("invalid target type")
+			}
+
+			cfg.err = errors.New("invalid")
+
+			return nil
+		},
+	}
+	fetcher := &mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return []byte("data"), nil
+		},
+	}
+
+	result, err := ProviderFresh[configWithValidator]("")(parser, fetcher)
+	if err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+
+	if result != nil {
+		t.Error("expected result to be nil")
+	}
+}
+
+type configWithSecret struct {
+	User     string
+	Password string
+}
+
+func (c *configWithSecret) Redact() any {
+	redacted := *c
+	if redacted.Password != "" {
+		redacted.Password = "***"
+	}
+
+	return redacted
+}
+
+// captureDefaultLogs redirects the default slog logger to a JSON buffer for the test.
+func captureDefaultLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var logs bytes.Buffer
+
+	original := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	t.Cleanup(func() { slog.SetDefault(original) })
+
+	return &logs
+}
+
+func secretParser() *mockParser {
+	return &mockParser{
+		parseFunc: func(_ []byte, target any, _ string) error {
+			switch cfg := target.(type) {
+			case *configWithSecret:
+				cfg.User, cfg.Password = "admin", "hunter2"
+			case *simpleConfig:
+				cfg.Name = "hunter2"
+			default:
+				return errors.New("invalid target type")
+			}
+
+			return nil
+		},
+	}
+}
+
+func TestProvider_LogsRedactedConfig(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	logs := captureDefaultLogs(t)
+	fetcher := &mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return []byte("data"), nil
+		},
+	}
+
+	result, err := Provider(&configWithSecret{}, "db")(secretParser(), fetcher)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Password != "hunter2" {
+		t.Errorf("expected the target to keep the real password, got %q", result.Password)
+	}
+
+	var entry struct {
+		Msg    string
+		Path   string
+		Config configWithSecret
+	}
+
+	err = json.Unmarshal(logs.Bytes(), &entry)
+	if err != nil {
+		t.Fatalf("expected one JSON log line, got %q: %v", logs.String(), err)
+	}
+
+	if entry.Msg != "effective config" || entry.Path != "db" {
+		t.Errorf("unexpected log entry: %+v", entry)
+	}
+
+	if entry.Config.User != "admin" || entry.Config.Password != "***" {
+		t.Errorf("expected the redacted config to be logged, got %+v", entry.Config)
+	}
+
+	if strings.Contains(logs.String(), "hunter2") {
+		t.Errorf("password leaked into logs: %s", logs.String())
+	}
+}
+
+func TestProvider_DoesNotLogConfigWithoutRedactor(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	logs := captureDefaultLogs(t)
+	fetcher := &mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return []byte("data"), nil
+		},
+	}
+
+	_, err := Provider(&simpleConfig{}, "")(secretParser(), fetcher)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if logs.Len() != 0 {
+		t.Errorf("expected no logs for a target without Redact, got %s", logs.String())
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDecryptFailed is returned by a DecryptingFetcher when the fetched data cannot be decrypted.
+var ErrDecryptFailed = errors.New("config decryption failed")
+
+var errNilDecryptor = errors.New("nil decryptor")
+
+// decryptingFetcher decrypts the data of an inner DataFetcher.
+type decryptingFetcher struct {
+	inner     DataFetcher
+	decryptor func([]byte) ([]byte, error)
+}
+
+// DecryptingFetcher returns a DataFetcher that passes the data fetched by inner through
+// decryptor, so the parser only sees plaintext. It is meant for configuration encrypted
+// at rest, e.g. SOPS or age files committed alongside the code. The cryptography is
+// left to decryptor to keep this package free of crypto dependencies.
+// Fetch errors from inner are returned unchanged; decryption errors, and a nil
+// decryptor, are wrapped with ErrDecryptFailed.
+func DecryptingFetcher(inner DataFetcher, decryptor func([]byte) ([]byte, error)) DataFetcher {
+	return &decryptingFetcher{inner: inner, decryptor: decryptor}
+}
+
+// Fetch fetches the data from the inner fetcher and decrypts it.
+func (f *decryptingFetcher) Fetch() ([]byte, error) {
+	if f.decryptor == nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptFailed, errNilDecryptor)
+	}
+
+	data, err := f.inner.Fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := f.decryptor(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptFailed, err)
+	}
+
+	return plain, nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+// xorDecryptor is a stand-in for real decryption: it XORs every byte with key.
+func xorDecryptor(key byte) func([]byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		out := make([]byte, len(data))
+		for i, b := range data {
+			out[i] = b ^ key
+		}
+
+		return out, nil
+	}
+}
+
+func TestDecryptingFetcher_Decrypts(t *testing.T) {
+	t.Parallel()
+
+	const key = 0x5a
+
+	encrypt := xorDecryptor(key)
+
+	ciphertext, _ := encrypt([]byte("name: secret-service"))
+
+	fetcher := DecryptingFetcher(&mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return ciphertext, nil
+		},
+	}, xorDecryptor(key))
+
+	data, err := fetcher.Fetch()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(data) != "name: secret-service" {
+		t.Errorf("expected plaintext, got %q", data)
+	}
+}
+
+func TestDecryptingFetcher_PlaintextReachesParser(t *testing.T) {
+	t.Parallel()
+
+	encrypt := xorDecryptor(0x21)
+	ciphertext, _ := encrypt([]byte("configured"))
+
+	var parsed string
+
+	parser := &mockParser{
+		parseFunc: func(data []byte, target any, _ string) error {
+			parsed = string(data)
+			target.(*simpleConfig).Name = parsed //nolint:forcetypeassert // test target type is fixed
+
+			return nil
+		},
+	}
+	fetcher := DecryptingFetcher(&mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return ciphertext, nil
+		},
+	}, xorDecryptor(0x21))
+
+	result, err := Provider(&simpleConfig{}, "")(parser, fetcher)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Name != "configured" {
+		t.Errorf("expected parser to receive plaintext, got %q", parsed)
+	}
+}
+
+func TestDecryptingFetcher_DecryptError(t *testing.T) {
+	t.Parallel()
+
+	badKey := errors.New("no matching age identity")
+
+	fetcher := DecryptingFetcher(&mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return []byte("ENC[...]"), nil
+		},
+	}, func([]byte) ([]byte, error) {
+		return nil, badKey
+	})
+
+	data, err := fetcher.Fetch()
+	if !errors.Is(err, ErrDecryptFailed) {
+		t.Fatalf("expected ErrDecryptFailed, got %v", err)
+	}
+
+	if !errors.Is(err, badKey) {
+		t.Errorf("expected decryptor error to be wrapped, got %v", err)
+	}
+
+	if data != nil {
+		t.Errorf("expected no data, got %q", data)
+	}
+}
+
+func TestDecryptingFetcher_FetchError(t *testing.T) {
+	t.Parallel()
+
+	fetchErr := errors.New("fetch failed")
+	called := false
+
+	fetcher := DecryptingFetcher(&mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return nil, fetchErr
+		},
+	}, func(data []byte) ([]byte, error) {
+		called = true
+
+		return data, nil
+	})
+
+	_, err := fetcher.Fetch()
+	if !errors.Is(err, fetchErr) {
+		t.Fatalf("expected fetch error, got %v", err)
+	}
+
+	if errors.Is(err, ErrDecryptFailed) {
+		t.Error("fetch errors should not be reported as decryption failures")
+	}
+
+	if called {
+		t.Error("decryptor should not run when fetching fails")
+	}
+}
+
+func TestDecryptingFetcher_NilDecryptor(t *testing.T) {
+	t.Parallel()
+
+	fetcher := DecryptingFetcher(&mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return []byte("data"), nil
+		},
+	}, nil)
+
+	_, err := fetcher.Fetch()
+	if !errors.Is(err, ErrDecryptFailed) {
+		t.Fatalf("expected ErrDecryptFailed, got %v", err)
+	}
+}
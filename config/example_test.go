@@ -241,3 +241,74 @@ func ExampleProvider_fileDataFetcher() {
 	fmt.Printf("Host: %s, Port: %d\n", result.Host, result.Port)
 	// Output: Host: production.example.com, Port: 443
 }
+
+// errPrivilegedPort is returned by the noPrivilegedPorts policy validator.
+var errPrivilegedPort = errors.New("port must not be privileged")
+
+// noPrivilegedPorts is a shared policy kept outside AppConfig.
+func noPrivilegedPorts(c *AppConfig) error {
+	if c.Port < 1024 {
+		return fmt.Errorf("%w: %d", errPrivilegedPort, c.Port)
+	}
+
+	return nil
+}
+
+func TestProviderWithValidators_RejectsWhatValidateAllows(t *testing.T) {
+	t.Parallel()
+
+	fetcher := &StaticDataFetcher{Data: []byte("host: example.com\nport: 443\n")}
+
+	_, err := config.Provider(new(AppConfig), "")(yamlparser.NewParser(), fetcher)
+	require.NoError(t, err, "AppConfig.Validate accepts port 443")
+
+	result, err := config.ProviderWithValidators(new(AppConfig), "", noPrivilegedPorts)(yamlparser.NewParser(), fetcher)
+	require.ErrorIs(t, err, errPrivilegedPort)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "validating error")
+}
+
+func TestProviderWithValidators_JoinsAllErrors(t *testing.T) {
+	t.Parallel()
+
+	errNoHost := errors.New("host must be a FQDN")
+
+	var calls []string
+
+	provider := config.ProviderWithValidators(new(AppConfig), "",
+		func(c *AppConfig) error {
+			calls = append(calls, "fqdn")
+
+			if c.Host == "localhost" {
+				return errNoHost
+			}
+
+			return nil
+		},
+		nil,
+		func(c *AppConfig) error {
+			calls = append(calls, "ports")
+
+			return noPrivilegedPorts(c)
+		},
+	)
+
+	_, err := provider(yamlparser.NewParser(), &StaticDataFetcher{Data: []byte("port: 70000\n")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "port must be between 1 and 65535", "the struct's own Validate should run first")
+	require.ErrorIs(t, err, errNoHost)
+	assert.NotErrorIs(t, err, errPrivilegedPort)
+	assert.Equal(t, []string{"fqdn", "ports"}, calls, "validators should run in order after a failing Validate")
+}
+
+func TestProviderWithValidators_Success(t *testing.T) {
+	t.Parallel()
+
+	cfg := &AppConfig{}
+	provider := config.ProviderWithValidators(cfg, "", noPrivilegedPorts)
+
+	result, err := provider(yamlparser.NewParser(), &StaticDataFetcher{Data: []byte("host: example.com\nport: 8443\n")})
+	require.NoError(t, err)
+	assert.Same(t, cfg, result)
+	assert.Equal(t, 8443, result.Port)
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoFetcherAvailable is returned by a FirstAvailable fetcher when every source fails.
+var ErrNoFetcherAvailable = errors.New("no config source available")
+
+// firstAvailableFetcher returns the data of the first fetcher that succeeds.
+type firstAvailableFetcher struct {
+	fetchers []DataFetcher
+}
+
+// FirstAvailable returns a DataFetcher that tries fetchers in order and returns the
+// data of the first one that succeeds, e.g. a remote config service with a local file
+// as fallback. The sources are alternatives: only one result is used and nothing is
+// merged, unlike combining sources so that later ones override keys of earlier ones.
+// Fetchers after the first success are not called. If all fail, the returned error
+// wraps ErrNoFetcherAvailable and every source's error, each prefixed with its
+// position and, for an IdentifiedDataFetcher, its source name. Nil fetchers are skipped.
+func FirstAvailable(fetchers ...DataFetcher) DataFetcher {
+	sources := make([]DataFetcher, 0, len(fetchers))
+
+	for _, fetcher := range fetchers {
+		if fetcher != nil {
+			sources = append(sources, fetcher)
+		}
+	}
+
+	return &firstAvailableFetcher{fetchers: sources}
+}
+
+// Fetch returns the data of the first fetcher that succeeds.
+func (f *firstAvailableFetcher) Fetch() ([]byte, error) {
+	errs := make([]error, 0, len(f.fetchers)+1)
+	errs = append(errs, ErrNoFetcherAvailable)
+
+	for i, fetcher := range f.fetchers {
+		data, err := fetcher.Fetch()
+		if err == nil {
+			return data, nil
+		}
+
+		if identified, ok := fetcher.(IdentifiedDataFetcher); ok {
+			errs = append(errs, fmt.Errorf("source %d (%s): %w", i, identified.SourceName(), err))
+
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("source %d: %w", i, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
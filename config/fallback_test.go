@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func staticFetcher(data string, err error) *mockDataFetcher {
+	return &mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			if err != nil {
+				return nil, err
+			}
+
+			return []byte(data), nil
+		},
+	}
+}
+
+func TestFirstAvailable_FallsBack(t *testing.T) {
+	t.Parallel()
+
+	remoteErr := errors.New("remote config service unavailable")
+	calledThird := false
+
+	fetcher := FirstAvailable(
+		staticFetcher("", remoteErr),
+		nil,
+		staticFetcher("local: true", nil),
+		&mockDataFetcher{fetchFunc: func() ([]byte, error) {
+			calledThird = true
+
+			return []byte("unused"), nil
+		}},
+	)
+
+	data, err := fetcher.Fetch()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(data) != "local: true" {
+		t.Errorf("expected data of the first successful fetcher, got %q", data)
+	}
+
+	if calledThird {
+		t.Error("fetchers after the first success should not be called")
+	}
+}
+
+func TestFirstAvailable_FirstSucceeds(t *testing.T) {
+	t.Parallel()
+
+	data, err := FirstAvailable(staticFetcher("remote", nil), staticFetcher("local", nil)).Fetch()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(data) != "remote" {
+		t.Errorf("expected remote data, got %q", data)
+	}
+}
+
+func TestFirstAvailable_AllFail(t *testing.T) {
+	t.Parallel()
+
+	remoteErr := errors.New("connection refused")
+	fileErr := errors.New("file not found")
+
+	data, err := FirstAvailable(staticFetcher("", remoteErr), staticFetcher("", fileErr)).Fetch()
+	if data != nil {
+		t.Errorf("expected no data, got %q", data)
+	}
+
+	if !errors.Is(err, ErrNoFetcherAvailable) {
+		t.Fatalf("expected ErrNoFetcherAvailable, got %v", err)
+	}
+
+	if !errors.Is(err, remoteErr) || !errors.Is(err, fileErr) {
+		t.Errorf("expected every source error to be wrapped, got %v", err)
+	}
+
+	for _, want := range []string{"source 0: connection refused", "source 1: file not found"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %q", want, err.Error())
+		}
+	}
+}
+
+func TestFirstAvailable_AllFailWithSourceNames(t *testing.T) {
+	t.Parallel()
+
+	named := &identifiedFetcher{
+		mockDataFetcher: *staticFetcher("", errors.New("file not found")),
+		name:            "/etc/app/config.yaml",
+	}
+
+	_, err := FirstAvailable(staticFetcher("", errors.New("connection refused")), named).Fetch()
+
+	for _, want := range []string{"source 0: connection refused", "source 1 (/etc/app/config.yaml): file not found"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got %q", want, err.Error())
+		}
+	}
+}
+
+func TestFirstAvailable_NoFetchers(t *testing.T) {
+	t.Parallel()
+
+	_, err := FirstAvailable().Fetch()
+	if !errors.Is(err, ErrNoFetcherAvailable) {
+		t.Fatalf("expected ErrNoFetcherAvailable, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package consul
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is the default duration a fetched KV value is cached before being re-read.
+const DefaultCacheTTL = time.Minute
+
+const defaultRequestTimeout = 10 * time.Second
+
+// ErrEmptyArgument is returned when the address or key is empty.
+var ErrEmptyArgument = errors.New("address and key must not be empty")
+
+// ErrConsulKeyNotFound is returned when the KV key does not exist.
+var ErrConsulKeyNotFound = errors.New("consul key not found")
+
+// ErrUnexpectedStatus is returned when Consul responds with an unexpected HTTP status.
+var ErrUnexpectedStatus = errors.New("unexpected consul response status")
+
+// ConsulOption configures the Consul KV fetcher.
+//
+//nolint:revive // name requested by the public API; reads well at call sites without the package name.
+type ConsulOption func(*options)
+
+type options struct {
+	token      string
+	tlsConfig  *tls.Config
+	datacenter string
+	ttl        time.Duration
+}
+
+// WithConsulToken sets the ACL token sent in the X-Consul-Token header.
+func WithConsulToken(token string) ConsulOption {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// WithConsulTLS sets the TLS configuration used to talk to Consul. When set, an address
+// without a scheme is contacted over https. The config is cloned, so later changes by the
+// caller have no effect.
+func WithConsulTLS(tlsConfig *tls.Config) ConsulOption {
+	return func(o *options) {
+		if tlsConfig != nil {
+			o.tlsConfig = tlsConfig.Clone()
+		}
+	}
+}
+
+// WithConsulDatacenter reads the key from the given datacenter instead of the agent's own.
+func WithConsulDatacenter(datacenter string) ConsulOption {
+	return func(o *options) {
+		o.datacenter = datacenter
+	}
+}
+
+// WithConsulCacheTTL sets how long the fetched value is cached before Fetch re-reads the key.
+// A non-positive ttl caches the value read at construction time forever.
+// Default is DefaultCacheTTL.
+func WithConsulCacheTTL(ttl time.Duration) ConsulOption {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// Fetcher implements config.DataFetcher interface for a Consul KV key.
+// It reads the key at construction time and caches its value for the configured TTL.
+type Fetcher struct {
+	client   *http.Client
+	endpoint string
+	key      string
+	token    string
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	data      []byte
+	fetchedAt time.Time
+}
+
+// NewFetcher returns a constructor function that creates a new Fetcher for the given
+// Consul agent address and KV key. The address may be "host:port" or a full URL such
+// as "https://consul.example.com:8501". The key is read at construction time.
+// This pattern is Fx-friendly, allowing the DI container to control when instantiation happens.
+// Returns an error if the address is invalid or the key cannot be read.
+func NewFetcher(address, key string, opts ...ConsulOption) func() (*Fetcher, error) {
+	return func() (*Fetcher, error) {
+		key = strings.TrimPrefix(key, "/")
+
+		if address == "" || key == "" {
+			return nil, ErrEmptyArgument
+		}
+
+		cfg := options{ttl: DefaultCacheTTL}
+
+		for _, opt := range opts {
+			if opt != nil {
+				opt(&cfg)
+			}
+		}
+
+		endpoint, err := kvEndpoint(address, key, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		fetcher := &Fetcher{
+			client:   newHTTPClient(cfg.tlsConfig),
+			endpoint: endpoint,
+			key:      key,
+			token:    cfg.token,
+			ttl:      cfg.ttl,
+			now:      time.Now,
+		}
+
+		fetcher.data, err = fetcher.read()
+		if err != nil {
+			return nil, err
+		}
+
+		fetcher.fetchedAt = fetcher.now()
+
+		return fetcher, nil
+	}
+}
+
+// Fetch returns a copy of the cached KV value. When the cache TTL has expired,
+// the key is re-read first. A copy is returned to prevent callers from mutating the cache.
+func (f *Fetcher) Fetch() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.ttl > 0 && f.now().Sub(f.fetchedAt) >= f.ttl {
+		data, err := f.read()
+		if err != nil {
+			return nil, err
+		}
+
+		f.data = data
+		f.fetchedAt = f.now()
+	}
+
+	result := make([]byte, len(f.data))
+	copy(result, f.data)
+
+	return result, nil
+}
+
+// kvEndpoint builds the URL of the raw KV read for key.
+func kvEndpoint(address, key string, cfg options) (string, error) {
+	if !strings.Contains(address, "://") {
+		scheme := "http://"
+		if cfg.tlsConfig != nil {
+			scheme = "https://"
+		}
+
+		address = scheme + address
+	}
+
+	base, err := url.Parse(address)
+	if err != nil {
+		return "", fmt.Errorf("parsing consul address %q: %w", address, err)
+	}
+
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	query := url.Values{"raw": {""}}
+	if cfg.datacenter != "" {
+		query.Set("dc", cfg.datacenter)
+	}
+
+	return fmt.Sprintf("%s/v1/kv/%s?%s",
+		strings.TrimSuffix(base.String(), "/"), strings.Join(segments, "/"), query.Encode()), nil
+}
+
+func (f *Fetcher) read() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for consul key %q: %w", f.key, err)
+	}
+
+	if f.token != "" {
+		req.Header.Set("X-Consul-Token", f.token)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("reading consul key %q: %w", f.key, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %q", ErrConsulKeyNotFound, f.key)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: %q: %s", ErrUnexpectedStatus, f.key, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading consul key %q body: %w", f.key, err)
+	}
+
+	return body, nil
+}
+
+func newHTTPClient(tlsCfg *tls.Config) *http.Client {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+	}
+
+	clone := transport.Clone()
+	clone.TLSClientConfig = tlsCfg
+
+	return &http.Client{Transport: clone}
+}
@@ -0,0 +1,178 @@
+package consul
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testValue = "host: api.example.com\nport: 9000\n"
+
+// newConsulHandler stubs the Consul KV endpoint, serving service/app/config.yaml in
+// datacenter dc1 to requests carrying the test token, and counts requests.
+func newConsulHandler(requests *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if r.Header.Get("X-Consul-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		if _, raw := r.URL.Query()["raw"]; !raw {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if dc := r.URL.Query().Get("dc"); dc != "" && dc != "dc1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, "No path to datacenter")
+
+			return
+		}
+
+		if r.URL.Path != "/v1/kv/service/app/config.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = fmt.Fprint(w, testValue)
+	})
+}
+
+// newConsulServer starts a plain HTTP Consul stub and returns it with a request counter.
+func newConsulServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(newConsulHandler(&requests))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func TestFetcher_Fetch_ReturnsKeyValue(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newConsulServer(t)
+	address := strings.TrimPrefix(srv.URL, "http://")
+
+	fetcher, err := NewFetcher(address, "service/app/config.yaml", WithConsulToken("test-token"))()
+	require.NoError(t, err)
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, testValue, string(data))
+}
+
+func TestFetcher_Fetch_Datacenter(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newConsulServer(t)
+
+	fetcher, err := NewFetcher(srv.URL, "service/app/config.yaml",
+		WithConsulToken("test-token"), WithConsulDatacenter("dc1"))()
+	require.NoError(t, err)
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, testValue, string(data))
+
+	_, err = NewFetcher(srv.URL, "service/app/config.yaml",
+		WithConsulToken("test-token"), WithConsulDatacenter("dc2"))()
+	require.ErrorIs(t, err, ErrUnexpectedStatus)
+}
+
+func TestFetcher_Fetch_TLS(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	srv := httptest.NewTLSServer(newConsulHandler(&requests))
+	t.Cleanup(srv.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	address := strings.TrimPrefix(srv.URL, "https://")
+
+	fetcher, err := NewFetcher(address, "service/app/config.yaml", WithConsulToken("test-token"),
+		WithConsulTLS(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}))()
+	require.NoError(t, err)
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, testValue, string(data))
+}
+
+func TestNewFetcher_KeyNotFound(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newConsulServer(t)
+
+	fetcher, err := NewFetcher(srv.URL, "service/app/missing.yaml", WithConsulToken("test-token"))()
+	require.ErrorIs(t, err, ErrConsulKeyNotFound)
+	assert.Nil(t, fetcher)
+	assert.Contains(t, err.Error(), "service/app/missing.yaml")
+}
+
+func TestNewFetcher_Forbidden(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newConsulServer(t)
+
+	fetcher, err := NewFetcher(srv.URL, "service/app/config.yaml")()
+	require.ErrorIs(t, err, ErrUnexpectedStatus)
+	assert.Nil(t, fetcher)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestFetcher_Fetch_CachesUntilTTLExpires(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newConsulServer(t)
+
+	fetcher, err := NewFetcher(srv.URL, "service/app/config.yaml",
+		WithConsulToken("test-token"), WithConsulCacheTTL(time.Minute))()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load(), "key should be read at construction")
+
+	now := time.Now()
+	fetcher.now = func() time.Time { return now }
+	fetcher.fetchedAt = now
+
+	_, err = fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "Fetch within TTL should use the cache")
+
+	now = now.Add(time.Minute)
+
+	_, err = fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load(), "Fetch after TTL should re-read the key")
+}
+
+func TestNewFetcher_EmptyArguments(t *testing.T) {
+	t.Parallel()
+
+	fetcher, err := NewFetcher("127.0.0.1:8500", "")()
+	require.ErrorIs(t, err, ErrEmptyArgument)
+	assert.Nil(t, fetcher)
+
+	fetcher, err = NewFetcher("", "service/app/config.yaml")()
+	require.ErrorIs(t, err, ErrEmptyArgument)
+	assert.Nil(t, fetcher)
+}
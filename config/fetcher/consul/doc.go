@@ -0,0 +1,24 @@
+// Package consul provides a Consul KV DataFetcher implementation for the config package.
+//
+// This package reads a single key from the Consul KV store using the Consul HTTP API.
+// It talks to the agent directly using only the standard library, so applications do
+// not need to pull in github.com/hashicorp/consul/api just to load configuration.
+//
+// The key is fetched at construction time and cached. Fetch() returns the cached
+// bytes until the cache TTL expires, after which the next call re-reads the key.
+//
+// Usage:
+//
+//	fetcher, err := consul.NewFetcher("127.0.0.1:8500", "service/app/config.yaml",
+//	    consul.WithConsulToken(os.Getenv("CONSUL_HTTP_TOKEN")))()
+//	if err != nil {
+//	    // Handle error: agent unreachable, key not found, permission denied, etc.
+//	}
+//	data, err := fetcher.Fetch()
+//
+// Error Handling:
+//   - Use errors.Is(err, consul.ErrConsulKeyNotFound) to detect a missing key
+//   - Use errors.Is(err, consul.ErrUnexpectedStatus) for other non-200 responses,
+//     such as 403 for a token without read access
+//   - Errors include the key for easier debugging
+package consul
@@ -16,8 +16,19 @@
 //	}
 //	data, err := fetcher.Fetch()
 //
+// For hot reload, NewWatchingFetcher returns a WatchingFetcher that polls the file
+// and implements config.WatchingFetcher; pair it with config.NewReloadableProvider:
+//
+//	watcher, err := file.NewWatchingFetcher("/path/to/config.yaml", time.Second)()
+//	defer watcher.Close()
+//
+// WatchModule wraps both in an Fx module providing an *atomic.Pointer[T] that tracks
+// the file.
+//
 // Error Handling:
 //   - Construction returns error if file cannot be read or path is a directory
 //   - Errors include the filepath for easier debugging
 //   - Use errors.Is(err, file.ErrPathIsDirectory) to check for directory errors
+//   - WithRetry(maxAttempts, delay) retries transient read errors (EIO, ESTALE);
+//     errors.Is(err, file.ErrMaxRetriesExceeded) reports exhausted attempts
 package file
@@ -1,15 +1,74 @@
 package file
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"syscall"
+	"time"
 )
 
 // ErrPathIsDirectory is returned when the path provided to the Fetcher points to a directory instead of a file.
 var ErrPathIsDirectory = errors.New("path is a directory, not a file")
 
+// ErrMaxRetriesExceeded is returned when reading the file still fails with a transient
+// error after all attempts configured via WithRetry. It wraps the last error.
+var ErrMaxRetriesExceeded = errors.New("max retries exceeded")
+
+// fetcherOptions holds the optional settings of a Fetcher.
+type fetcherOptions struct {
+	maxAttempts int
+	delay       time.Duration
+	ctx         context.Context //nolint:containedctx // bounds construction-time retries
+	readFile    func(name string) ([]byte, error)
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+// FetcherOption configures a Fetcher created by NewFetcher.
+type FetcherOption func(*fetcherOptions)
+
+// WithRetry retries reading the file up to maxAttempts times in total, waiting delay
+// between attempts, when the read fails with a transient error (syscall.EIO or
+// syscall.ESTALE, as seen on network-mounted filesystems). Other errors, such as a
+// missing file or denied permission, fail immediately. A maxAttempts below 1 is
+// treated as 1 (no retry).
+func WithRetry(maxAttempts int, delay time.Duration) FetcherOption {
+	return func(o *fetcherOptions) {
+		o.maxAttempts = max(maxAttempts, 1)
+		o.delay = delay
+	}
+}
+
+// WithContext makes retries configured via WithRetry stop as soon as ctx is done, e.g. a
+// signal.NotifyContext cancelled on Ctrl-C during startup. A cancellation during the
+// delay between attempts returns immediately with an error wrapping ctx.Err().
+func WithContext(ctx context.Context) FetcherOption {
+	return func(o *fetcherOptions) {
+		if ctx != nil {
+			o.ctx = ctx
+		}
+	}
+}
+
+func newFetcherOptions(opts ...FetcherOption) fetcherOptions {
+	o := fetcherOptions{
+		maxAttempts: 1,
+		ctx:         context.Background(),
+		readFile:    os.ReadFile,
+		sleep:       sleepContext,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	return o
+}
+
 // Fetcher implements config.DataFetcher interface for file-based configuration.
 // It reads configuration data from a file at construction time and caches the contents.
 type Fetcher struct {
@@ -21,31 +80,91 @@ type Fetcher struct {
 // with the specified filepath. The file is read at construction time and cached.
 // This pattern is Fx-friendly, allowing the DI container to control when instantiation happens.
 // Returns an error if the file cannot be read or if the path points to a directory.
-func NewFetcher(fpath string) func() (*Fetcher, error) {
+// Use WithRetry to retry transient read errors.
+func NewFetcher(fpath string, opts ...FetcherOption) func() (*Fetcher, error) {
+	o := newFetcherOptions(opts...)
+
 	return func() (*Fetcher, error) {
 		cleanPath := filepath.Clean(fpath)
 
-		stat, err := os.Stat(cleanPath)
+		data, err := readFile(cleanPath, &o)
 		if err != nil {
-			return nil, fmt.Errorf("stat file %q: %w", cleanPath, err)
+			return nil, err
 		}
 
-		if stat.IsDir() {
-			return nil, fmt.Errorf("path %q: %w", cleanPath, ErrPathIsDirectory)
+		return &Fetcher{
+			filepath: cleanPath,
+			data:     data,
+		}, nil
+	}
+}
+
+// readFile reads the file at cleanPath, rejecting directories and retrying
+// transient read errors as configured in o.
+func readFile(cleanPath string, o *fetcherOptions) ([]byte, error) {
+	stat, err := os.Stat(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("stat file %q: %w", cleanPath, err)
+	}
+
+	if stat.IsDir() {
+		return nil, fmt.Errorf("path %q: %w", cleanPath, ErrPathIsDirectory)
+	}
+
+	var lastErr error
+
+	for attempt := 1; attempt <= o.maxAttempts; attempt++ {
+		if attempt > 1 {
+			err := o.sleep(o.ctx, o.delay)
+			if err != nil {
+				return nil, fmt.Errorf("reading file %q: retry aborted after %d attempts: %w (last error: %w)",
+					cleanPath, attempt-1, err, lastErr)
+			}
 		}
 
-		data, err := os.ReadFile(cleanPath) // #nosec G304 -- path is cleaned and validated
-		if err != nil {
+		data, err := o.readFile(cleanPath) // #nosec G304 -- path is cleaned and validated
+		if err == nil {
+			return data, nil
+		}
+
+		if !isTransientReadError(err) {
 			return nil, fmt.Errorf("reading file %q: %w", cleanPath, err)
 		}
 
-		return &Fetcher{
-			filepath: cleanPath,
-			data:     data,
-		}, nil
+		lastErr = err
+	}
+
+	if o.maxAttempts == 1 {
+		return nil, fmt.Errorf("reading file %q: %w", cleanPath, lastErr)
+	}
+
+	return nil, fmt.Errorf("reading file %q: %w after %d attempts: %w",
+		cleanPath, ErrMaxRetriesExceeded, o.maxAttempts, lastErr)
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	case <-timer.C:
+		return nil
 	}
 }
 
+// isTransientReadError reports whether err is worth retrying.
+func isTransientReadError(err error) bool {
+	return errors.Is(err, syscall.EIO) || errors.Is(err, syscall.ESTALE)
+}
+
+// SourceName returns the cleaned path of the file, implementing config.IdentifiedDataFetcher.
+func (f *Fetcher) SourceName() string {
+	return f.filepath
+}
+
 // Fetch returns a copy of the cached configuration data that was read at construction time.
 // A copy is returned to prevent callers from mutating the cached data.
 func (f *Fetcher) Fetch() ([]byte, error) {
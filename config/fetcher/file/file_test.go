@@ -1,10 +1,16 @@
 package file
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/0xalexb/hjarta-di/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,6 +38,22 @@ version: "1.0"
 	assert.Equal(t, content, data)
 }
 
+func TestFetcher_SourceName(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("name: test-app\n"), 0o600)
+	require.NoError(t, err)
+
+	fetcher, err := NewFetcher(filepath.Join(filepath.Dir(configPath), ".", "config.yaml"))()
+	require.NoError(t, err)
+
+	var identified config.IdentifiedDataFetcher = fetcher
+
+	assert.Equal(t, configPath, identified.SourceName(), "SourceName should return the cleaned path")
+}
+
 func TestFetcher_Fetch_FileNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -210,3 +232,155 @@ func TestFetcher_Fetch_ReturnsCopy_MutationSafe(t *testing.T) {
 	assert.Equal(t, content, data2, "Fetch should return unmodified cached data")
 }
 
+// withMockRead replaces os.ReadFile with a function that returns the given errors in order
+// and then the file contents, and records sleeps instead of waiting.
+func withMockRead(errs []error, attempts *int, sleeps *[]time.Duration) FetcherOption {
+	return func(o *fetcherOptions) {
+		o.readFile = func(name string) ([]byte, error) {
+			*attempts++
+
+			if *attempts <= len(errs) {
+				return nil, &fs.PathError{Op: "read", Path: name, Err: errs[*attempts-1]}
+			}
+
+			return os.ReadFile(name) // #nosec G304 -- test path
+		}
+		o.sleep = func(_ context.Context, d time.Duration) error {
+			*sleeps = append(*sleeps, d)
+
+			return nil
+		}
+	}
+}
+
+func writeTempConfig(t *testing.T) string {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("name: retry\n"), 0o600))
+
+	return configPath
+}
+
+func TestNewFetcher_WithRetry_SucceedsOnThirdAttempt(t *testing.T) {
+	t.Parallel()
+
+	var (
+		attempts int
+		sleeps   []time.Duration
+	)
+
+	fetcher, err := NewFetcher(writeTempConfig(t),
+		WithRetry(3, 50*time.Millisecond),
+		withMockRead([]error{syscall.EIO, syscall.ESTALE}, &attempts, &sleeps),
+	)()
+	require.NoError(t, err)
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, "name: retry\n", string(data))
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []time.Duration{50 * time.Millisecond, 50 * time.Millisecond}, sleeps)
+}
+
+func TestNewFetcher_WithRetry_MaxRetriesExceeded(t *testing.T) {
+	t.Parallel()
+
+	var (
+		attempts int
+		sleeps   []time.Duration
+	)
+
+	fetcher, err := NewFetcher(writeTempConfig(t),
+		WithRetry(2, time.Millisecond),
+		withMockRead([]error{syscall.EIO, syscall.EIO, syscall.EIO}, &attempts, &sleeps),
+	)()
+
+	require.ErrorIs(t, err, ErrMaxRetriesExceeded)
+	require.ErrorIs(t, err, syscall.EIO, "last error should be wrapped")
+	assert.Nil(t, fetcher)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestNewFetcher_WithRetry_NonTransientErrorNotRetried(t *testing.T) {
+	t.Parallel()
+
+	for _, readErr := range []error{fs.ErrNotExist, fs.ErrPermission} {
+		t.Run(readErr.Error(), func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				attempts int
+				sleeps   []time.Duration
+			)
+
+			_, err := NewFetcher(writeTempConfig(t),
+				WithRetry(5, time.Millisecond),
+				withMockRead([]error{readErr}, &attempts, &sleeps),
+			)()
+
+			require.ErrorIs(t, err, readErr)
+			assert.False(t, errors.Is(err, ErrMaxRetriesExceeded))
+			assert.Equal(t, 1, attempts)
+			assert.Empty(t, sleeps)
+		})
+	}
+}
+
+func TestNewFetcher_WithoutRetry_TransientErrorFailsImmediately(t *testing.T) {
+	t.Parallel()
+
+	var (
+		attempts int
+		sleeps   []time.Duration
+	)
+
+	_, err := NewFetcher(writeTempConfig(t), withMockRead([]error{syscall.EIO}, &attempts, &sleeps))()
+
+	require.ErrorIs(t, err, syscall.EIO)
+	assert.False(t, errors.Is(err, ErrMaxRetriesExceeded))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestNewFetcher_WithContext_CancelDuringBackoff(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	attempts := 0
+	alwaysEIO := func(o *fetcherOptions) {
+		o.readFile = func(name string) ([]byte, error) {
+			attempts++
+
+			if attempts == 1 {
+				// Cancel once the first attempt failed and the backoff is about to start.
+				time.AfterFunc(20*time.Millisecond, cancel)
+			}
+
+			return nil, &fs.PathError{Op: "read", Path: name, Err: syscall.EIO}
+		}
+	}
+
+	start := time.Now()
+
+	fetcher, err := NewFetcher(writeTempConfig(t), WithRetry(3, time.Minute), WithContext(ctx), alwaysEIO)()
+
+	require.Error(t, err)
+	assert.Nil(t, fetcher)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, syscall.EIO)
+	assert.Less(t, time.Since(start), 5*time.Second, "cancellation should interrupt the backoff")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestNewFetcher_WithContext_NilIgnored(t *testing.T) {
+	t.Parallel()
+
+	//nolint:staticcheck // nil context is deliberately passed to check it is ignored
+	fetcher, err := NewFetcher(writeTempConfig(t), WithContext(nil))()
+	require.NoError(t, err)
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, "name: retry\n", string(data))
+}
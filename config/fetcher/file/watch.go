@@ -0,0 +1,137 @@
+package file
+
+import (
+	"bytes"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is the interval used by NewWatchingFetcher when none is given.
+const DefaultPollInterval = time.Second
+
+// WatchingFetcher is a file-based Fetcher that polls the file for changes.
+// It implements config.WatchingFetcher: Fetch returns the latest successfully read
+// contents and Changes signals each time they change.
+type WatchingFetcher struct {
+	filepath string
+	interval time.Duration
+	options  fetcherOptions
+
+	mu   sync.RWMutex
+	data []byte
+
+	changes   chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWatchingFetcher returns a constructor function that reads the file at fpath and
+// then polls it every interval, picking up changed contents. A non-positive interval
+// defaults to DefaultPollInterval. Construction fails like NewFetcher; failed reads
+// while polling (e.g. during an atomic replace) keep the previous contents.
+// Call Close to stop polling.
+func NewWatchingFetcher(fpath string, interval time.Duration) func() (*WatchingFetcher, error) {
+	return func() (*WatchingFetcher, error) {
+		cleanPath := filepath.Clean(fpath)
+
+		options := newFetcherOptions()
+
+		data, err := readFile(cleanPath, &options)
+		if err != nil {
+			return nil, err
+		}
+
+		if interval <= 0 {
+			interval = DefaultPollInterval
+		}
+
+		watcher := &WatchingFetcher{
+			filepath: cleanPath,
+			interval: interval,
+			options:  options,
+			data:     data,
+			changes:  make(chan struct{}, 1),
+			done:     make(chan struct{}),
+			stopped:  make(chan struct{}),
+		}
+
+		go watcher.poll()
+
+		return watcher, nil
+	}
+}
+
+// Fetch returns a copy of the most recently read file contents.
+func (w *WatchingFetcher) Fetch() ([]byte, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return bytes.Clone(w.data), nil
+}
+
+// SourceName returns the cleaned path of the watched file, implementing config.IdentifiedDataFetcher.
+func (w *WatchingFetcher) SourceName() string {
+	return w.filepath
+}
+
+// Changes returns a channel that receives a value when the file contents change.
+// Changes that happen before the previous one is received are coalesced.
+// The channel is closed by Close.
+func (w *WatchingFetcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops polling and closes the Changes channel. It is safe to call more than once.
+func (w *WatchingFetcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		<-w.stopped
+		close(w.changes)
+	})
+
+	return nil
+}
+
+// poll re-reads the file every interval until Close is called.
+func (w *WatchingFetcher) poll() {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.refresh()
+		}
+	}
+}
+
+// refresh reads the file and signals a change if its contents differ from the cached data.
+func (w *WatchingFetcher) refresh() {
+	data, err := readFile(w.filepath, &w.options)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	changed := !bytes.Equal(data, w.data)
+
+	if changed {
+		w.data = data
+	}
+	w.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	select {
+	case w.changes <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,55 @@
+package file
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/0xalexb/hjarta-di/config"
+	"go.uber.org/fx"
+)
+
+// WatchModule returns an Fx module that provides an *atomic.Pointer[T] holding the
+// configuration parsed from the file at path with parser. The file is polled every
+// DefaultPollInterval and each valid change is stored in the pointer, as with
+// config.ReloadableProvider, so handlers can Load the latest config without locking.
+// The whole document is parsed into T. Construction fails if the file cannot be
+// read or the initial configuration is invalid. An OnStop hook stops watching.
+func WatchModule[T any](path string, parser config.Parser) fx.Option {
+	return fx.Module("config",
+		fx.Provide(func(lc fx.Lifecycle) (*atomic.Pointer[T], error) {
+			fetcher, err := NewWatchingFetcher(path, DefaultPollInterval)()
+			if err != nil {
+				return nil, err
+			}
+
+			provider, err := config.NewReloadableProvider[T]("", parser, fetcher)
+			if err != nil {
+				_ = fetcher.Close()
+
+				return nil, err
+			}
+
+			// Subscribe before reading Current so no reload falls in between.
+			updates := provider.Subscribe()
+
+			current := new(atomic.Pointer[T])
+			current.Store(provider.Current())
+
+			go func() {
+				for cfg := range updates {
+					current.Store(cfg)
+				}
+			}()
+
+			lc.Append(fx.Hook{
+				OnStop: func(context.Context) error {
+					provider.Close()
+
+					return fetcher.Close()
+				},
+			})
+
+			return current, nil
+		}),
+	)
+}
@@ -0,0 +1,63 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xalexb/hjarta-di/config/fetcher/file"
+	yamlparser "github.com/0xalexb/hjarta-di/config/parser/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// hostConfig is the configuration watched in the WatchModule tests.
+type hostConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+func TestWatchModule_ReloadsOnFileChange(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("host: a.example.com\nport: 8080\n"), 0o600))
+
+	var current *atomic.Pointer[hostConfig]
+
+	app := fxtest.New(t,
+		file.WatchModule[hostConfig](configPath, yamlparser.NewParser()),
+		fx.Populate(&current),
+	)
+
+	app.RequireStart()
+	defer app.RequireStop()
+
+	require.NotNil(t, current)
+	assert.Equal(t, "a.example.com", current.Load().Host)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("host: b.example.com\nport: 9090\n"), 0o600))
+
+	assert.Eventually(t, func() bool {
+		return current.Load().Host == "b.example.com"
+	}, 5*time.Second, 20*time.Millisecond, "pointer should reflect the changed file")
+	assert.Equal(t, 9090, current.Load().Port)
+}
+
+func TestWatchModule_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	var current *atomic.Pointer[hostConfig]
+
+	app := fx.New(
+		fx.NopLogger,
+		file.WatchModule[hostConfig](filepath.Join(t.TempDir(), "missing.yaml"), yamlparser.NewParser()),
+		fx.Populate(&current),
+	)
+
+	require.Error(t, app.Err())
+}
@@ -0,0 +1,89 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchingFetcher_DetectsChange(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("version: 1\n"), 0o600))
+
+	fetcher, err := NewWatchingFetcher(configPath, 10*time.Millisecond)()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = fetcher.Close() })
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, "version: 1\n", string(data))
+
+	require.NoError(t, os.WriteFile(configPath, []byte("version: 2\n"), 0o600))
+
+	select {
+	case <-fetcher.Changes():
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for change notification")
+	}
+
+	data, err = fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, "version: 2\n", string(data))
+}
+
+func TestWatchingFetcher_KeepsDataWhenFileMissing(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("version: 1\n"), 0o600))
+
+	fetcher, err := NewWatchingFetcher(configPath, 10*time.Millisecond)()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = fetcher.Close() })
+
+	require.NoError(t, os.Remove(configPath))
+
+	select {
+	case <-fetcher.Changes():
+		t.Fatal("a missing file must not be reported as a change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, "version: 1\n", string(data))
+}
+
+func TestWatchingFetcher_Close(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("version: 1\n"), 0o600))
+
+	fetcher, err := NewWatchingFetcher(configPath, 0)()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPollInterval, fetcher.interval)
+
+	require.NoError(t, fetcher.Close())
+	require.NoError(t, fetcher.Close())
+
+	_, ok := <-fetcher.Changes()
+	assert.False(t, ok, "Changes should be closed")
+}
+
+func TestNewWatchingFetcher_DirectoryPath(t *testing.T) {
+	t.Parallel()
+
+	fetcher, err := NewWatchingFetcher(t.TempDir(), time.Second)()
+
+	require.ErrorIs(t, err, ErrPathIsDirectory)
+	assert.Nil(t, fetcher)
+}
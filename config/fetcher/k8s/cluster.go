@@ -0,0 +1,275 @@
+package k8s
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	tokenFile         = "token"
+	caFile            = "ca.crt"
+)
+
+// ErrNotInCluster is returned when in-cluster configuration is requested but the
+// KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT environment variables are not set.
+var ErrNotInCluster = errors.New("not running in a kubernetes cluster")
+
+// ErrInvalidKubeconfig is returned when the kubeconfig file is missing required entries.
+var ErrInvalidKubeconfig = errors.New("invalid kubeconfig")
+
+var errNoCACertificates = errors.New("no valid CA certificates found")
+
+// clusterConfig holds the resolved connection settings for the API server. The
+// bearer token is either fixed in token or, for a service account, read from tokenFile
+// on every request, since the kubelet rotates projected tokens.
+type clusterConfig struct {
+	server    string
+	token     string
+	tokenFile string
+	client    *http.Client
+}
+
+// bearerToken returns the token to authenticate with, re-reading tokenFile if set.
+func (c *clusterConfig) bearerToken() (string, error) {
+	if c.tokenFile == "" {
+		return c.token, nil
+	}
+
+	token, err := os.ReadFile(c.tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("reading service account token: %w", err)
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
+
+// loadInCluster builds the connection from the service account mounted into the pod.
+func loadInCluster() (*clusterConfig, error) {
+	host := os.Getenv("KUBERNETES_SERVICE_HOST")
+	port := os.Getenv("KUBERNETES_SERVICE_PORT")
+
+	if host == "" || port == "" {
+		return nil, ErrNotInCluster
+	}
+
+	caPEM, err := os.ReadFile(filepath.Join(serviceAccountDir, caFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading service account CA: %w", err)
+	}
+
+	tlsCfg, err := newTLSConfig(caPEM, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &clusterConfig{
+		server:    "https://" + net.JoinHostPort(host, port),
+		tokenFile: filepath.Join(serviceAccountDir, tokenFile),
+		client:    newHTTPClient(tlsCfg),
+	}
+
+	// Fail early if the token is missing rather than on the first read.
+	_, err = cfg.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// kubeconfig is the subset of the kubeconfig file format used by the fetcher.
+// Exec and auth-provider plugins are not supported.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// loadKubeconfig builds the connection from the current context of a kubeconfig file.
+// Relative certificate and key paths are resolved against the kubeconfig's directory,
+// as kubectl does.
+//
+//nolint:cyclop,funlen // sequential lookups of context, cluster, and user entries.
+func loadKubeconfig(path string) (*clusterConfig, error) {
+	cleanPath := filepath.Clean(path)
+
+	raw, err := os.ReadFile(cleanPath) // #nosec G304 -- path is provided by the application
+	if err != nil {
+		return nil, fmt.Errorf("reading kubeconfig %q: %w", cleanPath, err)
+	}
+
+	dir := filepath.Dir(cleanPath)
+
+	var kcfg kubeconfig
+
+	err = yaml.Unmarshal(raw, &kcfg)
+	if err != nil {
+		return nil, fmt.Errorf("parsing kubeconfig %q: %w", cleanPath, err)
+	}
+
+	var clusterName, userName string
+
+	for _, c := range kcfg.Contexts {
+		if c.Name == kcfg.CurrentContext {
+			clusterName, userName = c.Context.Cluster, c.Context.User
+		}
+	}
+
+	if clusterName == "" {
+		return nil, fmt.Errorf("%w: current context %q not found", ErrInvalidKubeconfig, kcfg.CurrentContext)
+	}
+
+	cfg := &clusterConfig{}
+
+	var tlsCfg *tls.Config
+
+	for _, c := range kcfg.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+
+		cfg.server = strings.TrimSuffix(c.Cluster.Server, "/")
+
+		caPEM, caErr := dataOrFile(c.Cluster.CertificateAuthorityData, resolvePath(dir, c.Cluster.CertificateAuthority))
+		if caErr != nil {
+			return nil, fmt.Errorf("reading cluster CA: %w", caErr)
+		}
+
+		tlsCfg, err = newTLSConfig(caPEM, c.Cluster.InsecureSkipTLSVerify)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.server == "" {
+		return nil, fmt.Errorf("%w: cluster %q has no server", ErrInvalidKubeconfig, clusterName)
+	}
+
+	for _, u := range kcfg.Users {
+		if u.Name != userName {
+			continue
+		}
+
+		cfg.token = u.User.Token
+
+		certPEM, certErr := dataOrFile(u.User.ClientCertificateData, resolvePath(dir, u.User.ClientCertificate))
+		if certErr != nil {
+			return nil, fmt.Errorf("reading client certificate: %w", certErr)
+		}
+
+		keyPEM, keyErr := dataOrFile(u.User.ClientKeyData, resolvePath(dir, u.User.ClientKey))
+		if keyErr != nil {
+			return nil, fmt.Errorf("reading client key: %w", keyErr)
+		}
+
+		if len(certPEM) > 0 && len(keyPEM) > 0 {
+			cert, pairErr := tls.X509KeyPair(certPEM, keyPEM)
+			if pairErr != nil {
+				return nil, fmt.Errorf("loading client certificate: %w", pairErr)
+			}
+
+			tlsCfg.Certificates = []tls.Certificate{cert}
+		}
+	}
+
+	cfg.client = newHTTPClient(tlsCfg)
+
+	return cfg, nil
+}
+
+// resolvePath returns file joined to dir when it is relative, and file otherwise.
+func resolvePath(dir, file string) string {
+	if file == "" || filepath.IsAbs(file) {
+		return file
+	}
+
+	return filepath.Join(dir, file)
+}
+
+// dataOrFile returns base64-decoded inline data if set, otherwise the contents of file if set.
+func dataOrFile(data, file string) ([]byte, error) {
+	if data != "" {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding base64 data: %w", err)
+		}
+
+		return decoded, nil
+	}
+
+	if file != "" {
+		contents, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", file, err)
+		}
+
+		return contents, nil
+	}
+
+	return nil, nil
+}
+
+func newTLSConfig(caPEM []byte, insecure bool) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecure, //nolint:gosec // G402: explicitly requested via kubeconfig
+	}
+
+	if len(caPEM) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errNoCACertificates
+		}
+
+		tlsCfg.RootCAs = pool
+	}
+
+	return tlsCfg, nil
+}
+
+func newHTTPClient(tlsCfg *tls.Config) *http.Client {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+	}
+
+	clone := transport.Clone()
+	clone.TLSClientConfig = tlsCfg
+
+	return &http.Client{Transport: clone}
+}
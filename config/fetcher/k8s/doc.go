@@ -0,0 +1,30 @@
+// Package k8s provides a Kubernetes ConfigMap DataFetcher implementation for the config package.
+//
+// This package reads a single data key from a ConfigMap using the Kubernetes REST API.
+// It talks to the API server directly over HTTPS using only the standard library, so
+// applications do not need to pull in k8s.io/client-go just to load configuration.
+//
+// By default the fetcher uses in-cluster configuration: the API server address from the
+// KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT environment variables and the
+// service account token and CA certificate mounted into the pod. The token file is
+// re-read for every request, so tokens rotated by the kubelet are picked up. For
+// out-of-cluster development, WithKubeconfig loads the current context from a kubeconfig
+// file; relative certificate and key paths in it are resolved against its directory.
+//
+// The ConfigMap is fetched at construction time and cached. Fetch() returns the cached
+// bytes until the cache TTL expires, after which the next call re-reads the ConfigMap.
+//
+// Usage:
+//
+//	fetcher, err := k8s.NewFetcher("default", "app-config", "config.yaml")()
+//	if err != nil {
+//	    // Handle error: not in cluster, ConfigMap or key not found, API error, etc.
+//	}
+//	data, err := fetcher.Fetch()
+//
+// Error Handling:
+//   - Use errors.Is(err, k8s.ErrNotInCluster) to detect missing in-cluster configuration
+//   - Use errors.Is(err, k8s.ErrConfigMapNotFound) and errors.Is(err, k8s.ErrKeyNotFound)
+//     to distinguish a missing ConfigMap from a missing data key
+//   - Errors include the namespace, ConfigMap name, and key for easier debugging
+package k8s
@@ -0,0 +1,212 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is the default duration fetched ConfigMap data is cached before being re-read.
+const DefaultCacheTTL = time.Minute
+
+const defaultRequestTimeout = 10 * time.Second
+
+// ErrEmptyArgument is returned when the namespace, name, or key is empty.
+var ErrEmptyArgument = errors.New("namespace, name, and key must not be empty")
+
+// ErrConfigMapNotFound is returned when the ConfigMap does not exist.
+var ErrConfigMapNotFound = errors.New("configmap not found")
+
+// ErrKeyNotFound is returned when the ConfigMap exists but does not contain the requested key.
+var ErrKeyNotFound = errors.New("configmap key not found")
+
+// ErrUnexpectedStatus is returned when the API server responds with an unexpected HTTP status.
+var ErrUnexpectedStatus = errors.New("unexpected API server response status")
+
+// Option configures the Kubernetes ConfigMap fetcher.
+type Option func(*options)
+
+type options struct {
+	kubeconfig string
+	ttl        time.Duration
+}
+
+// WithKubeconfig loads the API server connection from the current context of the
+// kubeconfig file at path instead of using in-cluster configuration.
+// Intended for out-of-cluster development.
+func WithKubeconfig(path string) Option {
+	return func(o *options) {
+		o.kubeconfig = path
+	}
+}
+
+// WithCacheTTL sets how long fetched data is cached before Fetch re-reads the ConfigMap.
+// A non-positive ttl caches the data read at construction time forever.
+// Default is DefaultCacheTTL.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// Fetcher implements config.DataFetcher interface for a Kubernetes ConfigMap key.
+// It reads the ConfigMap at construction time and caches the key's contents for the configured TTL.
+type Fetcher struct {
+	cluster   *clusterConfig
+	namespace string
+	name      string
+	key       string
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	data      []byte
+	fetchedAt time.Time
+}
+
+// NewFetcher returns a constructor function that creates a new Fetcher for the given
+// ConfigMap namespace, name, and data key. The ConfigMap is read at construction time.
+// This pattern is Fx-friendly, allowing the DI container to control when instantiation happens.
+// Returns an error if the cluster configuration cannot be loaded or the key cannot be read.
+func NewFetcher(namespace, name, key string, opts ...Option) func() (*Fetcher, error) {
+	return func() (*Fetcher, error) {
+		if namespace == "" || name == "" || key == "" {
+			return nil, ErrEmptyArgument
+		}
+
+		cfg := options{ttl: DefaultCacheTTL}
+
+		for _, opt := range opts {
+			if opt != nil {
+				opt(&cfg)
+			}
+		}
+
+		var (
+			cluster *clusterConfig
+			err     error
+		)
+
+		if cfg.kubeconfig != "" {
+			cluster, err = loadKubeconfig(cfg.kubeconfig)
+		} else {
+			cluster, err = loadInCluster()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		fetcher := &Fetcher{
+			cluster:   cluster,
+			namespace: namespace,
+			name:      name,
+			key:       key,
+			ttl:       cfg.ttl,
+			now:       time.Now,
+		}
+
+		fetcher.data, err = fetcher.read()
+		if err != nil {
+			return nil, err
+		}
+
+		fetcher.fetchedAt = fetcher.now()
+
+		return fetcher, nil
+	}
+}
+
+// Fetch returns a copy of the cached ConfigMap data. When the cache TTL has expired,
+// the ConfigMap is re-read first. A copy is returned to prevent callers from mutating the cache.
+func (f *Fetcher) Fetch() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.ttl > 0 && f.now().Sub(f.fetchedAt) >= f.ttl {
+		data, err := f.read()
+		if err != nil {
+			return nil, err
+		}
+
+		f.data = data
+		f.fetchedAt = f.now()
+	}
+
+	result := make([]byte, len(f.data))
+	copy(result, f.data)
+
+	return result, nil
+}
+
+// configMap is the subset of the Kubernetes ConfigMap object used by the fetcher.
+type configMap struct {
+	Data       map[string]string `json:"data"`
+	BinaryData map[string][]byte `json:"binaryData"`
+}
+
+func (f *Fetcher) read() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("%s/api/v1/namespaces/%s/configmaps/%s",
+		f.cluster.server, url.PathEscape(f.namespace), url.PathEscape(f.name))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for configmap %s/%s: %w", f.namespace, f.name, err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	token, err := f.cluster.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := f.cluster.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("reading configmap %s/%s: %w", f.namespace, f.name, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s/%s", ErrConfigMapNotFound, f.namespace, f.name)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: %s/%s: %s", ErrUnexpectedStatus, f.namespace, f.name, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading configmap %s/%s body: %w", f.namespace, f.name, err)
+	}
+
+	var cm configMap
+
+	err = json.Unmarshal(body, &cm)
+	if err != nil {
+		return nil, fmt.Errorf("decoding configmap %s/%s: %w", f.namespace, f.name, err)
+	}
+
+	if value, ok := cm.Data[f.key]; ok {
+		return []byte(value), nil
+	}
+
+	if value, ok := cm.BinaryData[f.key]; ok {
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q in %s/%s", ErrKeyNotFound, f.key, f.namespace, f.name)
+}
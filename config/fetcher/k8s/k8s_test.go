@@ -0,0 +1,245 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigMap = `{
+  "kind": "ConfigMap",
+  "apiVersion": "v1",
+  "metadata": {"name": "app-config", "namespace": "prod"},
+  "data": {"config.yaml": "host: api.example.com\nport: 9000\n", "other.yaml": "unused: true\n"},
+  "binaryData": {"blob": "aGVsbG8="}
+}`
+
+// newAPIServer starts a TLS test server serving the prod/app-config ConfigMap and
+// returns it with a request counter and the path to a kubeconfig pointing at it.
+func newAPIServer(t *testing.T) (*httptest.Server, *atomic.Int32, string) {
+	t.Helper()
+
+	var requests atomic.Int32
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if r.URL.Path != "/api/v1/namespaces/prod/configmaps/app-config" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, testConfigMap)
+	}))
+	t.Cleanup(srv.Close)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: dev
+contexts:
+  - name: dev
+    context:
+      cluster: test-cluster
+      user: test-user
+clusters:
+  - name: test-cluster
+    cluster:
+      server: %s
+      certificate-authority-data: %s
+users:
+  - name: test-user
+    user:
+      token: test-token
+`, srv.URL, base64.StdEncoding.EncodeToString(caPEM))
+
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+
+	err := os.WriteFile(path, []byte(kubeconfig), 0o600)
+	require.NoError(t, err)
+
+	return srv, &requests, path
+}
+
+func TestFetcher_Fetch_ReturnsConfigMapKey(t *testing.T) {
+	t.Parallel()
+
+	_, _, kubeconfig := newAPIServer(t)
+
+	fetcher, err := NewFetcher("prod", "app-config", "config.yaml", WithKubeconfig(kubeconfig))()
+	require.NoError(t, err)
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, "host: api.example.com\nport: 9000\n", string(data))
+}
+
+func TestFetcher_Fetch_BinaryDataKey(t *testing.T) {
+	t.Parallel()
+
+	_, _, kubeconfig := newAPIServer(t)
+
+	fetcher, err := NewFetcher("prod", "app-config", "blob", WithKubeconfig(kubeconfig))()
+	require.NoError(t, err)
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestNewFetcher_KeyNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, _, kubeconfig := newAPIServer(t)
+
+	fetcher, err := NewFetcher("prod", "app-config", "missing.yaml", WithKubeconfig(kubeconfig))()
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	assert.Nil(t, fetcher)
+	assert.Contains(t, err.Error(), "missing.yaml")
+}
+
+func TestNewFetcher_ConfigMapNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, _, kubeconfig := newAPIServer(t)
+
+	fetcher, err := NewFetcher("prod", "nope", "config.yaml", WithKubeconfig(kubeconfig))()
+	require.ErrorIs(t, err, ErrConfigMapNotFound)
+	assert.Nil(t, fetcher)
+}
+
+func TestFetcher_Fetch_CachesUntilTTLExpires(t *testing.T) {
+	t.Parallel()
+
+	_, requests, kubeconfig := newAPIServer(t)
+
+	fetcher, err := NewFetcher("prod", "app-config", "config.yaml",
+		WithKubeconfig(kubeconfig), WithCacheTTL(time.Minute))()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load(), "ConfigMap should be read at construction")
+
+	now := time.Now()
+	fetcher.now = func() time.Time { return now }
+	fetcher.fetchedAt = now
+
+	_, err = fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "Fetch within TTL should use the cache")
+
+	now = now.Add(time.Minute)
+
+	_, err = fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load(), "Fetch after TTL should re-read the ConfigMap")
+}
+
+func TestNewFetcher_EmptyArguments(t *testing.T) {
+	t.Parallel()
+
+	fetcher, err := NewFetcher("prod", "app-config", "")()
+	require.ErrorIs(t, err, ErrEmptyArgument)
+	assert.Nil(t, fetcher)
+}
+
+func TestNewFetcher_NotInCluster(t *testing.T) { //nolint:paralleltest // modifies environment variables
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+
+	fetcher, err := NewFetcher("prod", "app-config", "config.yaml")()
+	require.ErrorIs(t, err, ErrNotInCluster)
+	assert.Nil(t, fetcher)
+}
+
+func TestNewFetcher_KubeconfigMissingContext(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+
+	err := os.WriteFile(path, []byte("current-context: missing\n"), 0o600)
+	require.NoError(t, err)
+
+	fetcher, err := NewFetcher("prod", "app-config", "config.yaml", WithKubeconfig(path))()
+	require.ErrorIs(t, err, ErrInvalidKubeconfig)
+	assert.Nil(t, fetcher)
+}
+
+func TestNewFetcher_KubeconfigRelativeCertificatePaths(t *testing.T) {
+	t.Parallel()
+
+	srv, _, kubeconfig := newAPIServer(t)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	err := os.WriteFile(filepath.Join(filepath.Dir(kubeconfig), "ca.crt"), caPEM, 0o600)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(kubeconfig)
+	require.NoError(t, err)
+
+	caData := base64.StdEncoding.EncodeToString(caPEM)
+	relative := strings.Replace(string(data), "certificate-authority-data: "+caData, "certificate-authority: ca.crt", 1)
+	require.NotEqual(t, string(data), relative)
+
+	err = os.WriteFile(kubeconfig, []byte(relative), 0o600)
+	require.NoError(t, err)
+
+	fetcher, err := NewFetcher("prod", "app-config", "config.yaml", WithKubeconfig(kubeconfig))()
+	require.NoError(t, err)
+
+	data, err = fetcher.Fetch()
+	require.NoError(t, err)
+	assert.Equal(t, "host: api.example.com\nport: 9000\n", string(data))
+}
+
+func TestFetcher_Fetch_RereadsTokenFile(t *testing.T) {
+	t.Parallel()
+
+	_, _, kubeconfig := newAPIServer(t)
+
+	fetcher, err := NewFetcher("prod", "app-config", "config.yaml",
+		WithKubeconfig(kubeconfig), WithCacheTTL(time.Minute))()
+	require.NoError(t, err)
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+
+	err = os.WriteFile(tokenPath, []byte("expired-token\n"), 0o600)
+	require.NoError(t, err)
+
+	fetcher.cluster.tokenFile = tokenPath
+
+	now := time.Now()
+	fetcher.now = func() time.Time { return now }
+	fetcher.fetchedAt = now
+
+	now = now.Add(time.Minute)
+
+	_, err = fetcher.Fetch()
+	require.Error(t, err, "the expired token should be rejected")
+
+	err = os.WriteFile(tokenPath, []byte("test-token\n"), 0o600)
+	require.NoError(t, err)
+
+	data, err := fetcher.Fetch()
+	require.NoError(t, err, "the rotated token should be picked up")
+	assert.Equal(t, "host: api.example.com\nport: 9000\n", string(data))
+}
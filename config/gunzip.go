@@ -0,0 +1,53 @@
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrGunzipFailed is returned by a GunzipFetcher when gzip-compressed data cannot be decompressed.
+var ErrGunzipFailed = errors.New("config gunzip failed")
+
+// gzipMagic is the header every gzip stream starts with.
+var gzipMagic = []byte{0x1f, 0x8b} //nolint:gochecknoglobals
+
+// gunzipFetcher decompresses the data of an inner DataFetcher when it is gzipped.
+type gunzipFetcher struct {
+	inner DataFetcher
+}
+
+// GunzipFetcher returns a DataFetcher that transparently decompresses the data fetched
+// by inner when it starts with the gzip magic bytes, e.g. a config bundle stored as
+// config.yaml.gz. Data that is not gzipped is returned unchanged, so the same fetcher
+// works for plain and compressed files. Fetch errors from inner are returned unchanged;
+// decompression errors are wrapped with ErrGunzipFailed.
+func GunzipFetcher(inner DataFetcher) DataFetcher {
+	return &gunzipFetcher{inner: inner}
+}
+
+// Fetch fetches the data from the inner fetcher and decompresses it if it is gzipped.
+func (f *gunzipFetcher) Fetch() ([]byte, error) {
+	data, err := f.inner.Fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGunzipFailed, err)
+	}
+
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGunzipFailed, err)
+	}
+
+	return plain, nil
+}
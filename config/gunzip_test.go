@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xalexb/hjarta-di/config"
+	"github.com/0xalexb/hjarta-di/config/fetcher/file"
+	yamlparser "github.com/0xalexb/hjarta-di/config/parser/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const serverYAML = "server:\n  host: api.example.com\n  port: 9000\n  timeout: 30\n"
+
+// gzipBytes returns data compressed with gzip.
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	writer := gzip.NewWriter(&buf)
+
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
+
+func TestGunzipFetcher_ParsesGzippedAndPlainFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		contents []byte
+	}{
+		{name: "gzipped", filename: "config.yaml.gz", contents: gzipBytes(t, []byte(serverYAML))},
+		{name: "plain", filename: "config.yaml", contents: []byte(serverYAML)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tt.filename)
+			require.NoError(t, os.WriteFile(path, tt.contents, 0o600))
+
+			fileFetcher, err := file.NewFetcher(path)()
+			require.NoError(t, err)
+
+			cfg, err := config.ProviderFresh[ServerConfig]("server")(
+				yamlparser.NewParser(), config.GunzipFetcher(fileFetcher))
+			require.NoError(t, err)
+
+			assert.Equal(t, "api.example.com", cfg.Host)
+			assert.Equal(t, 9000, cfg.Port)
+			assert.Equal(t, 30, cfg.Timeout)
+		})
+	}
+}
+
+func TestGunzipFetcher_CorruptData(t *testing.T) {
+	t.Parallel()
+
+	truncated := gzipBytes(t, []byte(serverYAML))
+	truncated = truncated[:len(truncated)/2]
+
+	data, err := config.GunzipFetcher(&StaticDataFetcher{Data: truncated}).Fetch()
+	require.ErrorIs(t, err, config.ErrGunzipFailed)
+	assert.Nil(t, data)
+}
+
+func TestGunzipFetcher_FetchErrorPassesThrough(t *testing.T) {
+	t.Parallel()
+
+	// FirstAvailable without sources always fails with ErrNoFetcherAvailable.
+	_, err := config.GunzipFetcher(config.FirstAvailable()).Fetch()
+	require.ErrorIs(t, err, config.ErrNoFetcherAvailable)
+	assert.NotErrorIs(t, err, config.ErrGunzipFailed)
+}
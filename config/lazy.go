@@ -0,0 +1,39 @@
+package config
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNilDependency is returned by LazyProvider when the parser or data fetcher is nil,
+// since the failure would otherwise only surface on first use.
+var ErrNilDependency = errors.New("nil parser or data fetcher")
+
+// LazyProvider is like Provider but defers loading: the returned constructor only
+// checks its dependencies and yields a load function. The first call to the load
+// function fetches, parses, applies defaults to, and validates the configuration into
+// target; that result, including an error, is cached and returned by every later call.
+// Loading happens at most once even under concurrent calls (guarded by sync.Once), so
+// the fetcher is called exactly once. Use it when loading is expensive or only needed
+// on some code paths, e.g. fx.Provide(config.LazyProvider(&cfg, "reports")).
+func LazyProvider[T any](target *T, path string) func(Parser, DataFetcher) (func() (*T, error), error) {
+	return func(parser Parser, dataSourcer DataFetcher) (func() (*T, error), error) {
+		if parser == nil || dataSourcer == nil {
+			return nil, ErrNilDependency
+		}
+
+		var (
+			once   sync.Once
+			result *T
+			err    error
+		)
+
+		return func() (*T, error) {
+			once.Do(func() {
+				result, err = load(target, path, parser, dataSourcer)
+			})
+
+			return result, err
+		}, nil
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLazyProvider_LoadsOnceUnderConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+
+	parser := &mockParser{
+		parseFunc: func(data []byte, target any, _ string) error {
+			cfg, ok := target.(*simpleConfig)
+			if !ok {
+				return errors.New("invalid target type")
+			}
+
+			cfg.Name = string(data)
+
+			return nil
+		},
+	}
+	fetcher := &mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			fetches.Add(1)
+
+			return []byte("lazy"), nil
+		},
+	}
+
+	load, err := LazyProvider(&simpleConfig{}, "")(parser, fetcher)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fetches.Load() != 0 {
+		t.Fatal("expected no fetch before first use")
+	}
+
+	const callers = 50
+
+	results := make([]*simpleConfig, callers)
+
+	var wg sync.WaitGroup
+
+	for i := range callers {
+		wg.Go(func() {
+			cfg, loadErr := load()
+			if loadErr != nil {
+				t.Errorf("unexpected error: %v", loadErr)
+			}
+
+			results[i] = cfg
+		})
+	}
+
+	wg.Wait()
+
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("expected exactly one fetch, got %d", got)
+	}
+
+	for _, cfg := range results {
+		if cfg != results[0] || cfg.Name != "lazy" {
+			t.Fatalf("expected every caller to get the same cached config, got %+v", cfg)
+		}
+	}
+}
+
+func TestLazyProvider_CachesError(t *testing.T) {
+	t.Parallel()
+
+	fetchErr := errors.New("fetch failed")
+
+	var fetches int
+
+	load, err := LazyProvider(&simpleConfig{}, "")(
+		&mockParser{parseFunc: func([]byte, any, string) error { return nil }},
+		&mockDataFetcher{fetchFunc: func() ([]byte, error) {
+			fetches++
+
+			return nil, fetchErr
+		}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 2 {
+		cfg, loadErr := load()
+		if !errors.Is(loadErr, fetchErr) {
+			t.Errorf("expected %v, got %v", fetchErr, loadErr)
+		}
+
+		if cfg != nil {
+			t.Error("expected nil config on error")
+		}
+	}
+
+	if fetches != 1 {
+		t.Errorf("expected the failed load to be cached, got %d fetches", fetches)
+	}
+}
+
+func TestLazyProvider_NilDependency(t *testing.T) {
+	t.Parallel()
+
+	load, err := LazyProvider(&simpleConfig{}, "")(nil, &mockDataFetcher{})
+	if !errors.Is(err, ErrNilDependency) {
+		t.Fatalf("expected ErrNilDependency, got %v", err)
+	}
+
+	if load != nil {
+		t.Error("expected nil load function")
+	}
+}
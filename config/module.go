@@ -0,0 +1,26 @@
+package config
+
+import "go.uber.org/fx"
+
+// Module returns an Fx module that provides *T loaded with the given parser and fetcher.
+// It bundles what otherwise takes three provides (parser, fetcher and Provider) into one
+// option. The parser and fetcher are used directly and are not added to the container,
+// so several config modules with different parsers or fetchers can coexist.
+func Module[T any](target *T, path string, parser Parser, fetcher DataFetcher) fx.Option {
+	return fx.Module("config",
+		fx.Provide(func() (*T, error) {
+			return load(target, path, parser, fetcher)
+		}),
+	)
+}
+
+// FxProvider wraps Provider for fx.Provide, tagging the *T result with name:"<fxName>"
+// so several configs of the same type can coexist in one container. The Parser and
+// DataFetcher are resolved from the container. Consume the result with
+// `name:"<fxName>"` on an fx.In field or through fx.ParamTags.
+func FxProvider[T any](target *T, path string, fxName string) any {
+	return fx.Annotated{
+		Name:   fxName,
+		Target: Provider(target, path),
+	}
+}
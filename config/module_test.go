@@ -0,0 +1,181 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/0xalexb/hjarta-di/config"
+	yamlparser "github.com/0xalexb/hjarta-di/config/parser/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestModule_InjectsConfig(t *testing.T) {
+	t.Parallel()
+
+	fetcher := &StaticDataFetcher{
+		Data: []byte("server:\n  host: api.example.com\n  port: 9000\n  timeout: 30\n"),
+	}
+
+	var cfg *ServerConfig
+
+	app := fxtest.New(t,
+		config.Module(new(ServerConfig), "server", yamlparser.NewParser(), fetcher),
+		fx.Populate(&cfg),
+	)
+
+	app.RequireStart()
+	defer app.RequireStop()
+
+	require.NotNil(t, cfg)
+	assert.Equal(t, "api.example.com", cfg.Host)
+	assert.Equal(t, 9000, cfg.Port)
+	assert.Equal(t, 30, cfg.Timeout)
+}
+
+func TestModule_MultipleConfigs(t *testing.T) {
+	t.Parallel()
+
+	fetcher := &StaticDataFetcher{
+		Data: []byte("server:\n  host: api.example.com\n  port: 9000\ndatabase:\n  host: db.example.com\n  port: 5432\n"),
+	}
+	parser := yamlparser.NewParser()
+
+	var (
+		server   *ServerConfig
+		database *DatabaseConfig
+	)
+
+	app := fxtest.New(t,
+		config.Module(new(ServerConfig), "server", parser, fetcher),
+		config.Module(new(DatabaseConfig), "database", parser, fetcher),
+		fx.Populate(&server, &database),
+	)
+
+	app.RequireStart()
+	defer app.RequireStop()
+
+	assert.Equal(t, 9000, server.Port)
+	assert.Equal(t, "db.example.com", database.Host)
+}
+
+func TestModule_ProviderError(t *testing.T) {
+	t.Parallel()
+
+	fetcher := &StaticDataFetcher{Data: []byte("port: 99999\n")}
+
+	var cfg *AppConfig
+
+	app := fx.New(
+		fx.NopLogger,
+		config.Module(new(AppConfig), "", yamlparser.NewParser(), fetcher),
+		fx.Populate(&cfg),
+	)
+
+	require.Error(t, app.Err(), "validation error should fail the app")
+}
+
+func TestFxProvider_NamedConfigs(t *testing.T) {
+	t.Parallel()
+
+	fetcher := &StaticDataFetcher{
+		Data: []byte("public:\n  host: api.example.com\n  port: 443\nadmin:\n  host: admin.internal\n  port: 8443\n"),
+	}
+
+	var got struct {
+		fx.In
+
+		Public *AppConfig `name:"public"`
+		Admin  *AppConfig `name:"admin"`
+	}
+
+	app := fxtest.New(t,
+		fx.Supply(
+			fx.Annotate(yamlparser.NewParser(), fx.As(new(config.Parser))),
+			fx.Annotate(fetcher, fx.As(new(config.DataFetcher))),
+		),
+		fx.Provide(
+			config.FxProvider(new(AppConfig), "public", "public"),
+			config.FxProvider(new(AppConfig), "admin", "admin"),
+		),
+		fx.Populate(&got),
+	)
+
+	app.RequireStart()
+	defer app.RequireStop()
+
+	require.NotNil(t, got.Public)
+	require.NotNil(t, got.Admin)
+	assert.NotSame(t, got.Public, got.Admin)
+	assert.Equal(t, "api.example.com", got.Public.Host)
+	assert.Equal(t, 443, got.Public.Port)
+	assert.Equal(t, "admin.internal", got.Admin.Host)
+	assert.Equal(t, 8443, got.Admin.Port)
+}
+
+func TestFxProvider_UnnamedNotProvided(t *testing.T) {
+	t.Parallel()
+
+	var cfg *AppConfig
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(
+			fx.Annotate(yamlparser.NewParser(), fx.As(new(config.Parser))),
+			fx.Annotate(&StaticDataFetcher{Data: []byte("port: 80\n")}, fx.As(new(config.DataFetcher))),
+		),
+		fx.Provide(config.FxProvider(new(AppConfig), "", "web")),
+		fx.Populate(&cfg),
+	)
+
+	require.Error(t, app.Err(), "the config should only be available under its name")
+}
+
+// BaseConfig holds fields shared by several configs and is merged inline.
+type BaseConfig struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+}
+
+// ServiceConfig embeds BaseConfig with yaml:",inline" so its fields sit at the same level.
+type ServiceConfig struct {
+	BaseConfig `yaml:",inline"`
+
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+func TestProvider_InlineStruct(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("name: orders\nversion: \"2.1\"\nhost: 0.0.0.0\nport: 8080\n" +
+		"service:\n  name: billing\n  version: \"1.0\"\n  host: localhost\n  port: 9090\n")
+
+	tests := []struct {
+		name string
+		path string
+		want ServiceConfig
+	}{
+		{
+			name: "root document",
+			path: "",
+			want: ServiceConfig{BaseConfig: BaseConfig{Name: "orders", Version: "2.1"}, Host: "0.0.0.0", Port: 8080},
+		},
+		{
+			name: "nested path",
+			path: "service",
+			want: ServiceConfig{BaseConfig: BaseConfig{Name: "billing", Version: "1.0"}, Host: "localhost", Port: 9090},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.ProviderFresh[ServiceConfig](tt.path)(yamlparser.NewParser(), &StaticDataFetcher{Data: data})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *cfg)
+		})
+	}
+}
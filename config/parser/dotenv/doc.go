@@ -0,0 +1,27 @@
+// Package dotenv provides a dotenv (.env) parser implementation for the config package.
+//
+// Each non-blank line holds a KEY=VALUE pair, optionally prefixed with "export ".
+// Lines starting with # are comments. Values may be unquoted (an inline comment
+// must be preceded by whitespace), single-quoted (taken literally) or double-quoted
+// (supporting \n, \r, \t, \" and \\ escapes). It pairs with config/fetcher/file.
+//
+// Usage:
+//
+//	parser := dotenv.NewParser()
+//	var cfg struct {
+//		Host string `env:"DB_HOST"`
+//		Port int    // matches DB_PORT via the "DB" path
+//	}
+//	err := parser.Parse(data, &cfg, "DB")
+//
+// Path Navigation:
+//   - Empty path "" -> decode all keys
+//   - Key "DB_HOST" -> decode that value into a scalar target
+//   - Prefix "DB" -> decode the keys starting with "DB_", with the prefix removed
+//   - Colon-separated segments are joined with "_": "DB:HOST" is the same as "DB_HOST"
+//
+// Targets can be a *map[string]string, a pointer to a struct, or a pointer to a
+// string, bool, integer, float, time.Duration or []string (comma-separated).
+// Struct fields are matched by their `env` tag, or else by field name ignoring case
+// and underscores (DBHost matches DB_HOST). Fields without a matching key are left unchanged.
+package dotenv
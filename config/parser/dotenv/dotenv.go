@@ -0,0 +1,387 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrEmptyData is returned when the input data is empty.
+var ErrEmptyData = errors.New("empty data")
+
+// ErrPathNotFound is returned when no key matches the specified path.
+var ErrPathNotFound = errors.New("path not found")
+
+// ErrTypeMismatch is returned when a value cannot be decoded into the target type,
+// e.g. "abc" decoded into an int.
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// ErrInvalidLine is returned when a line is not a valid KEY=VALUE pair.
+var ErrInvalidLine = errors.New("invalid line")
+
+var (
+	errMissingEquals           = errors.New("missing '='")
+	errInvalidKey              = errors.New("invalid key")
+	errUnterminatedSingleQuote = errors.New("unterminated single-quoted value")
+	errUnterminatedDoubleQuote = errors.New("unterminated double-quoted value")
+	errTrailingCharacters      = errors.New("unexpected characters after quoted value")
+	errUnsupportedValueType    = errors.New("unsupported type")
+)
+
+// envTag is the struct tag naming the key a field is decoded from.
+const envTag = "env"
+
+// Parser implements config.Parser interface for dotenv data.
+type Parser struct{}
+
+// NewParser creates a new dotenv parser instance.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse parses dotenv data and decodes it into the target.
+// The path parameter selects a key, or a prefix of keys, with colon (:) segments
+// joined by "_". Empty path decodes all keys. See the package documentation for
+// the supported syntax and targets.
+func (p *Parser) Parse(data []byte, target any, path string) error {
+	if len(data) == 0 {
+		return ErrEmptyData
+	}
+
+	values, err := parseLines(string(data))
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return decode(values, target)
+	}
+
+	key := strings.Join(strings.Split(path, ":"), "_")
+
+	if value, ok := values[key]; ok {
+		err = decodeValue(value, target)
+		if err != nil {
+			return fmt.Errorf("%w at path %q: %w", ErrTypeMismatch, path, err)
+		}
+
+		return nil
+	}
+
+	prefix := key + "_"
+	scoped := make(map[string]string)
+
+	for name, value := range values {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" {
+			scoped[rest] = value
+		}
+	}
+
+	if len(scoped) == 0 {
+		return fmt.Errorf("%w: %s", ErrPathNotFound, path)
+	}
+
+	err = decode(scoped, target)
+	if err != nil {
+		return fmt.Errorf("path %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// parseLines parses dotenv content into a flat map. Later keys override earlier ones.
+func parseLines(content string) (map[string]string, error) {
+	values := make(map[string]string)
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidLine, i+1, err)
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// parseLine parses a single trimmed, non-comment KEY=VALUE line.
+func parseLine(line string) (string, string, error) {
+	line = strings.TrimPrefix(line, "export ")
+
+	key, rawValue, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", errMissingEquals
+	}
+
+	key = strings.TrimSpace(key)
+	if !isValidKey(key) {
+		return "", "", fmt.Errorf("%w %q", errInvalidKey, key)
+	}
+
+	value, err := parseValue(strings.TrimSpace(rawValue))
+	if err != nil {
+		return "", "", fmt.Errorf("key %s: %w", key, err)
+	}
+
+	return key, value, nil
+}
+
+// isValidKey reports whether key is a non-empty identifier of letters, digits,
+// underscores and dots, not starting with a digit.
+func isValidKey(key string) bool {
+	if key == "" || ('0' <= key[0] && key[0] <= '9') {
+		return false
+	}
+
+	for i := range len(key) {
+		c := key[i]
+		if !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !('0' <= c && c <= '9') && c != '_' && c != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseValue decodes a trimmed value: quoted, or unquoted with an optional inline comment.
+func parseValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	switch raw[0] {
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", errUnterminatedSingleQuote
+		}
+
+		return raw[1 : end+1], checkTrailing(raw[end+2:])
+	case '"':
+		return parseDoubleQuoted(raw)
+	default:
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '#' && (raw[i-1] == ' ' || raw[i-1] == '\t') {
+				return strings.TrimSpace(raw[:i]), nil
+			}
+		}
+
+		return raw, nil
+	}
+}
+
+// parseDoubleQuoted decodes a double-quoted value, processing escape sequences.
+func parseDoubleQuoted(raw string) (string, error) {
+	var sb strings.Builder
+
+	for i := 1; i < len(raw); i++ {
+		c := raw[i]
+
+		switch {
+		case c == '"':
+			return sb.String(), checkTrailing(raw[i+1:])
+		case c == '\\' && i+1 < len(raw):
+			i++
+
+			switch raw[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '"', '\\':
+				sb.WriteByte(raw[i])
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(raw[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", errUnterminatedDoubleQuote
+}
+
+// checkTrailing allows only whitespace or a comment after a closing quote.
+func checkTrailing(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return fmt.Errorf("%w: %q", errTrailingCharacters, rest)
+	}
+
+	return nil
+}
+
+// decode stores values into target: a *map[string]string, a pointer to a struct,
+// or, for a single value, a pointer to a scalar.
+func decode(values map[string]string, target any) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("%w: target must be a non-nil pointer, got %T", ErrTypeMismatch, target)
+	}
+
+	elem := ptr.Elem()
+
+	//nolint:exhaustive // remaining kinds cannot hold multiple values
+	switch elem.Kind() {
+	case reflect.Map:
+		if elem.Type().Key().Kind() != reflect.String || elem.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%w: cannot decode into %s", ErrTypeMismatch, elem.Type())
+		}
+
+		if elem.IsNil() {
+			elem.Set(reflect.MakeMapWithSize(elem.Type(), len(values)))
+		}
+
+		for key, value := range values {
+			elem.SetMapIndex(reflect.ValueOf(key).Convert(elem.Type().Key()),
+				reflect.ValueOf(value).Convert(elem.Type().Elem()))
+		}
+
+		return nil
+	case reflect.Struct:
+		return decodeStruct(values, elem)
+	default:
+		return fmt.Errorf("%w: cannot decode multiple keys into %s", ErrTypeMismatch, elem.Type())
+	}
+}
+
+// decodeStruct sets the exported fields of value that have a matching key.
+func decodeStruct(values map[string]string, value reflect.Value) error {
+	normalized := make(map[string]string, len(values))
+	for key, v := range values {
+		normalized[normalizeKey(key)] = v
+	}
+
+	typ := value.Type()
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		raw, ok := lookupField(field, values, normalized)
+		if !ok {
+			continue
+		}
+
+		err := setValue(value.Field(i), raw)
+		if err != nil {
+			return fmt.Errorf("%w: field %s: %w", ErrTypeMismatch, field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// lookupField finds the value for field by its env tag or, without one, its normalized name.
+func lookupField(field reflect.StructField, values, normalized map[string]string) (string, bool) {
+	if tag, ok := field.Tag.Lookup(envTag); ok {
+		if tag == "-" {
+			return "", false
+		}
+
+		raw, found := values[tag]
+
+		return raw, found
+	}
+
+	raw, found := normalized[normalizeKey(field.Name)]
+
+	return raw, found
+}
+
+// normalizeKey lowercases key and removes underscores, so DB_HOST and DBHost compare equal.
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+// decodeValue stores a single value into target, a pointer to a scalar or []string.
+func decodeValue(raw string, target any) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+		return fmt.Errorf("%w: target must be a non-nil pointer, got %T", ErrTypeMismatch, target)
+	}
+
+	return setValue(ptr.Elem(), raw)
+}
+
+// setValue converts raw to the type of value and stores it.
+func setValue(value reflect.Value, raw string) error {
+	if value.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("parsing duration: %w", err)
+		}
+
+		value.SetInt(int64(d))
+
+		return nil
+	}
+
+	//nolint:exhaustive // remaining kinds are unsupported
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		value.SetFloat(f)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%w %s", errUnsupportedValueType, value.Type())
+		}
+
+		parts := []string{}
+		if raw != "" {
+			parts = strings.Split(raw, ",")
+		}
+
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			slice.Index(i).SetString(strings.TrimSpace(part))
+		}
+
+		value.Set(slice)
+	default:
+		return fmt.Errorf("%w %s", errUnsupportedValueType, value.Type())
+	}
+
+	return nil
+}
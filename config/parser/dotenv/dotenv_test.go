@@ -0,0 +1,224 @@
+package dotenv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_Parse_Syntax(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`# database settings
+DB_HOST=localhost
+
+export DB_PORT=5432
+   DB_USER = admin   
+DB_PASS="p@ss # not a comment"
+DB_NAME='literal \n value'
+GREETING="line1\nline2 \"quoted\"" # trailing comment
+PLAIN=value # inline comment
+HASH=abc#def
+EMPTY=
+EMPTY_QUOTED=""
+`)
+
+	var result map[string]string
+
+	err := NewParser().Parse(data, &result, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"DB_HOST":      "localhost",
+		"DB_PORT":      "5432",
+		"DB_USER":      "admin",
+		"DB_PASS":      "p@ss # not a comment",
+		"DB_NAME":      `literal \n value`,
+		"GREETING":     "line1\nline2 \"quoted\"",
+		"PLAIN":        "value",
+		"HASH":         "abc#def",
+		"EMPTY":        "",
+		"EMPTY_QUOTED": "",
+	}, result)
+}
+
+func TestParser_Parse_CRLF(t *testing.T) {
+	t.Parallel()
+
+	var result map[string]string
+
+	err := NewParser().Parse([]byte("A=1\r\nB=\"two\"\r\n"), &result, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"A": "1", "B": "two"}, result)
+}
+
+func TestParser_Parse_InvalidLine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		data  string
+		cause error
+	}{
+		{name: "missing equals", data: "A=1\nNOT_A_PAIR\n", cause: errMissingEquals},
+		{name: "empty key", data: "=value", cause: errInvalidKey},
+		{name: "key with space", data: "MY KEY=value", cause: errInvalidKey},
+		{name: "key starting with digit", data: "1KEY=value", cause: errInvalidKey},
+		{name: "unterminated double quote", data: `KEY="value`, cause: errUnterminatedDoubleQuote},
+		{name: "unterminated single quote", data: `KEY='value`, cause: errUnterminatedSingleQuote},
+		{name: "text after closing quote", data: `KEY="value"extra`, cause: errTrailingCharacters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var result map[string]string
+
+			err := NewParser().Parse([]byte(tt.data), &result, "")
+
+			require.ErrorIs(t, err, ErrInvalidLine)
+			require.ErrorIs(t, err, tt.cause)
+		})
+	}
+}
+
+func TestParser_Parse_InvalidLineReportsLineNumber(t *testing.T) {
+	t.Parallel()
+
+	var result map[string]string
+
+	err := NewParser().Parse([]byte("# comment\nA=1\n\nBROKEN\n"), &result, "")
+
+	require.ErrorIs(t, err, ErrInvalidLine)
+	assert.Contains(t, err.Error(), "invalid line 4")
+}
+
+func TestParser_Parse_Struct(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`APP_NAME=demo
+PORT=8080
+DEBUG=true
+TIMEOUT=1m30s
+RATIO=0.5
+ALLOWED_HOSTS=a.example.com, b.example.com
+DB_URL=postgres://localhost
+SECRET=ignored
+`)
+
+	var result struct {
+		AppName      string
+		Port         int
+		Debug        bool
+		Timeout      time.Duration
+		Ratio        float64
+		AllowedHosts []string
+		Database     string `env:"DB_URL"`
+		Secret       string `env:"-"`
+		Missing      string
+	}
+
+	result.Missing = "unchanged"
+
+	err := NewParser().Parse(data, &result, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "demo", result.AppName)
+	assert.Equal(t, 8080, result.Port)
+	assert.True(t, result.Debug)
+	assert.Equal(t, 90*time.Second, result.Timeout)
+	assert.InDelta(t, 0.5, result.Ratio, 0)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, result.AllowedHosts)
+	assert.Equal(t, "postgres://localhost", result.Database)
+	assert.Empty(t, result.Secret)
+	assert.Equal(t, "unchanged", result.Missing)
+}
+
+func TestParser_Parse_Path(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`DB_HOST=db.example.com
+DB_PORT=5432
+API_HOST=api.example.com
+`)
+
+	t.Run("key", func(t *testing.T) {
+		t.Parallel()
+
+		var host string
+
+		require.NoError(t, NewParser().Parse(data, &host, "DB_HOST"))
+		assert.Equal(t, "db.example.com", host)
+	})
+
+	t.Run("colon segments", func(t *testing.T) {
+		t.Parallel()
+
+		var port int
+
+		require.NoError(t, NewParser().Parse(data, &port, "DB:PORT"))
+		assert.Equal(t, 5432, port)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		t.Parallel()
+
+		var db struct {
+			Host string
+			Port int
+		}
+
+		require.NoError(t, NewParser().Parse(data, &db, "DB"))
+		assert.Equal(t, "db.example.com", db.Host)
+		assert.Equal(t, 5432, db.Port)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		var value string
+
+		require.ErrorIs(t, NewParser().Parse(data, &value, "CACHE"), ErrPathNotFound)
+	})
+}
+
+func TestParser_Parse_TypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("PORT=http\nSMALL=300\n")
+
+	var port int
+	require.ErrorIs(t, NewParser().Parse(data, &port, "PORT"), ErrTypeMismatch)
+
+	var small int8
+	require.ErrorIs(t, NewParser().Parse(data, &small, "SMALL"), ErrTypeMismatch)
+
+	var cfg struct{ Port int }
+	require.ErrorIs(t, NewParser().Parse(data, &cfg, ""), ErrTypeMismatch)
+
+	var scalar string
+	require.ErrorIs(t, NewParser().Parse(data, &scalar, ""), ErrTypeMismatch)
+}
+
+func TestParser_Parse_EmptyData(t *testing.T) {
+	t.Parallel()
+
+	var result map[string]string
+
+	require.ErrorIs(t, NewParser().Parse(nil, &result, ""), ErrEmptyData)
+}
+
+func TestParser_Parse_CommentsOnly(t *testing.T) {
+	t.Parallel()
+
+	var result map[string]string
+
+	err := NewParser().Parse([]byte("# nothing here\n\n"), &result, "")
+
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
 )
 
 // ErrEmptyData is returned when the input data is empty.
@@ -15,13 +20,135 @@ var ErrEmptyData = errors.New("empty data")
 // ErrPathNotFound is returned when the specified path is not found in the YAML document.
 var ErrPathNotFound = errors.New("path not found")
 
+// ErrTypeMismatch is returned when the value (at the path, if any) exists but cannot be
+// decoded into the target type, e.g. a mapping decoded into an int.
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// ErrInvalidPathSegment is returned when a colon-separated path segment is empty or
+// contains characters outside the allowed set (by default [a-zA-Z0-9_-]).
+var ErrInvalidPathSegment = errors.New("invalid path segment")
+
+// ErrKeyCollision is returned when key normalization maps two keys of the same
+// mapping to the same key.
+var ErrKeyCollision = errors.New("normalized key collision")
+
+// DefaultPathChars is the character class path segments are validated against by default.
+const DefaultPathChars = `[a-zA-Z0-9_-]`
+
+// defaultPathSegment matches a path segment made of DefaultPathChars.
+var defaultPathSegment = pathSegmentPattern(DefaultPathChars) //nolint:gochecknoglobals
+
 // Parser implements config.Parser interface for YAML data.
 // It uses goccy/go-yaml PathString for efficient path navigation.
-type Parser struct{}
+type Parser struct {
+	stripComments bool
+	pathSegment   *regexp.Regexp
+	pathCharsErr  error
+	normalizeKey  func(string) string
+	aliases       []keyAlias
+}
+
+// keyAlias is an alternative key for the key a struct field is decoded from, in the
+// mapping at scope relative to the decoded one.
+type keyAlias struct {
+	scope []string
+	key   string
+	alias string
+}
+
+// YAMLOption configures a Parser created by NewParser.
+type YAMLOption func(*Parser)
+
+// WithStripComments makes the parser blank out full-line # comments before parsing.
+// Lines are replaced with empty lines rather than removed, so line numbers in error
+// messages still match the original document. Lines inside block scalars (| and >)
+// are content, not comments, and are kept. Trailing comments after a value are left
+// to the YAML decoder.
+func WithStripComments() YAMLOption {
+	return func(p *Parser) {
+		p.stripComments = true
+	}
+}
+
+// WithAllowedPathChars replaces the characters allowed in path segments. chars is a
+// regular expression matching a single allowed character, typically a character class,
+// e.g. `[a-zA-Z0-9_ \[\].-]` to also allow spaces, brackets and dots. Segments with
+// characters outside [a-zA-Z0-9_-] are quoted, so they are looked up as literal keys.
+// If chars does not compile, Parse returns an error for every non-empty path.
+func WithAllowedPathChars(chars string) YAMLOption {
+	return func(p *Parser) {
+		re, err := regexp.Compile("^(?:" + chars + ")+$")
+		if err != nil {
+			p.pathSegment = nil
+			p.pathCharsErr = fmt.Errorf("invalid allowed path characters %q: %w", chars, err)
+
+			return
+		}
+
+		p.pathSegment = re
+		p.pathCharsErr = nil
+	}
+}
+
+// WithKeyNormalization applies fn to every mapping key in the document, and to every
+// path segment, before decoding, so sources with inconsistent key casing still match
+// the target's yaml tags, e.g. WithKeyNormalization(strings.ToLower) decodes "HOST: x"
+// into a field tagged `yaml:"host"`. Keys are left as they are by default. Keys are
+// rewritten in the parsed document, so key order, anchors and the line numbers in
+// decode errors are kept. Parse returns ErrKeyCollision if two keys of one mapping
+// normalize to the same key. A nil fn disables normalization.
+func WithKeyNormalization(fn func(string) string) YAMLOption {
+	return func(p *Parser) {
+		p.normalizeKey = fn
+	}
+}
+
+// WithYAMLAlias makes aliasKey an alternative name for structField, the key a struct
+// field is decoded from (its yaml tag name), e.g. WithYAMLAlias("timeout",
+// "request_timeout") fills a field tagged `yaml:"timeout"` from "request_timeout: 30".
+// The alias applies only to the mapping Parse decodes, the one at its path or the
+// document root, so keys of the same name in unrelated nested mappings are left alone.
+// For a field of a nested struct, prefix structField with the colon-separated path of
+// its mapping relative to the decoded one, e.g. WithYAMLAlias("server:timeout",
+// "request_timeout"). The alias is used only when the mapping has no structField key of
+// its own. An option per alias can be given for the same field; the first one present
+// wins. With WithKeyNormalization, both keys are normalized as well. Empty keys and
+// path segments are ignored.
+func WithYAMLAlias(structField, aliasKey string) YAMLOption {
+	return func(p *Parser) {
+		segments := strings.Split(structField, ":")
+		key := segments[len(segments)-1]
+
+		if aliasKey == "" || key == aliasKey || slices.Contains(segments, "") {
+			return
+		}
+
+		p.aliases = append(p.aliases, keyAlias{scope: segments[:len(segments)-1], key: key, alias: aliasKey})
+	}
+}
 
 // NewParser creates a new YAML parser instance.
-func NewParser() *Parser {
-	return &Parser{}
+func NewParser(opts ...YAMLOption) *Parser {
+	p := &Parser{}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+
+	if p.normalizeKey != nil {
+		for i, a := range p.aliases {
+			scope := make([]string, len(a.scope))
+			for j, segment := range a.scope {
+				scope[j] = p.normalizeKey(segment)
+			}
+
+			p.aliases[i] = keyAlias{scope: scope, key: p.normalizeKey(a.key), alias: p.normalizeKey(a.alias)}
+		}
+	}
+
+	return p
 }
 
 // Parse parses YAML data and unmarshals it into the target.
@@ -32,16 +159,63 @@ func (p *Parser) Parse(data []byte, target any, path string) error {
 		return ErrEmptyData
 	}
 
+	if p.stripComments {
+		data = stripCommentLines(data)
+	}
+
+	var file *ast.File
+
+	if p.normalizeKey != nil || len(p.aliases) > 0 {
+		var err error
+
+		path = normalizePath(path, p.normalizeKey)
+
+		file, err = p.rewriteKeys(data, path)
+		if err != nil {
+			return err
+		}
+	}
+
 	if path == "" {
-		err := yaml.Unmarshal(data, target)
+		err := unmarshalDocument(data, file, target)
 		if err != nil {
+			if isTypeMismatchError(err) {
+				if kind := collectionKind(data); kind != "" && isScalarTarget(target) {
+					return fmt.Errorf("%w: document is a %s but target %T is a scalar, "+
+						"use a path to select a single value: %w", ErrTypeMismatch, kind, target, err)
+				}
+
+				return fmt.Errorf("%w: %w", ErrTypeMismatch, err)
+			}
+
 			return fmt.Errorf("unmarshal error: %w", err)
 		}
 
 		return nil
 	}
 
-	yamlPath := convertToYAMLPath(path)
+	if file != nil {
+		data = []byte(file.String())
+	}
+
+	data, err := resolveMergeKeys(data)
+	if err != nil {
+		return err
+	}
+
+	if p.pathCharsErr != nil {
+		return p.pathCharsErr
+	}
+
+	segment := p.pathSegment
+	if segment == nil {
+		segment = defaultPathSegment
+	}
+
+	yamlPath, err := convertToYAMLPath(path, segment)
+	if err != nil {
+		return err
+	}
 
 	pathObj, err := yaml.PathString(yamlPath)
 	if err != nil {
@@ -56,23 +230,358 @@ func (p *Parser) Parse(data []byte, target any, path string) error {
 			return fmt.Errorf("%w: %s", ErrPathNotFound, path)
 		}
 
+		if isTypeMismatchError(err) {
+			return fmt.Errorf("%w at path %q: %w", ErrTypeMismatch, path, err)
+		}
+
 		return fmt.Errorf("reading path %q: %w", path, err)
 	}
 
 	return nil
 }
 
+// resolveMergeKeys expands anchors, aliases and merge keys (<<) in the document.
+// PathString reads only the addressed node, so aliases pointing outside of it
+// cannot be resolved; a full decode/encode round-trip inlines them beforehand.
+// Documents without a merge key are returned unchanged.
+func resolveMergeKeys(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte("<<")) {
+		return data, nil
+	}
+
+	var doc any
+
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	resolved, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("resolving merge keys: %w", err)
+	}
+
+	return resolved, nil
+}
+
+// rewriteKeys parses the document and rewrites its mapping keys in place: the key
+// normalization function is applied to every key, then the key aliases are resolved in
+// the mappings they are scoped to below path. Working on the syntax tree keeps key
+// order, anchors and the positions reported in decode errors.
+func (p *Parser) rewriteKeys(data []byte, path string) (*ast.File, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	var base []string
+	if path != "" {
+		base = strings.Split(path, ":")
+	}
+
+	for _, doc := range file.Docs {
+		if p.normalizeKey != nil {
+			err = normalizeNode(doc.Body, p.normalizeKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, a := range p.aliases {
+			applyAlias(mappingAt(doc.Body, slices.Concat(base, a.scope)), a)
+		}
+	}
+
+	return file, nil
+}
+
+// normalizeNode applies fn to the keys of node's mappings, recursively. Aliases are
+// skipped, as the anchored node they refer to is normalized where it is defined.
+func normalizeNode(node ast.Node, fn func(string) string) error {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return normalizeMapping(n.Values, fn)
+	case *ast.MappingValueNode:
+		return normalizeMapping([]*ast.MappingValueNode{n}, fn)
+	case *ast.SequenceNode:
+		for _, child := range n.Values {
+			err := normalizeNode(child, fn)
+			if err != nil {
+				return err
+			}
+		}
+	case *ast.AnchorNode:
+		return normalizeNode(n.Value, fn)
+	case *ast.TagNode:
+		return normalizeNode(n.Value, fn)
+	}
+
+	return nil
+}
+
+// normalizeMapping applies fn to the string keys of one mapping and normalizes the
+// mapping's values.
+func normalizeMapping(values []*ast.MappingValueNode, fn func(string) string) error {
+	seen := make(map[string]bool, len(values))
+
+	for _, value := range values {
+		if key := stringKey(value.Key); key != nil {
+			key.Value = fn(key.Value)
+			if seen[key.Value] {
+				return fmt.Errorf("%w: %q", ErrKeyCollision, key.Value)
+			}
+
+			seen[key.Value] = true
+		}
+
+		err := normalizeNode(value.Value, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mappingAt returns the entries of the mapping reached from node by following the
+// string keys in segments, or nil if there is no such mapping.
+func mappingAt(node ast.Node, segments []string) []*ast.MappingValueNode {
+	var values []*ast.MappingValueNode
+
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		values = n.Values
+	case *ast.MappingValueNode:
+		values = []*ast.MappingValueNode{n}
+	case *ast.AnchorNode:
+		return mappingAt(n.Value, segments)
+	case *ast.TagNode:
+		return mappingAt(n.Value, segments)
+	default:
+		return nil
+	}
+
+	if len(segments) == 0 {
+		return values
+	}
+
+	for _, value := range values {
+		if key := stringKey(value.Key); key != nil && key.Value == segments[0] {
+			return mappingAt(value.Value, segments[1:])
+		}
+	}
+
+	return nil
+}
+
+// applyAlias renames the alias key of a mapping's entries to the aliased key, unless
+// the mapping already has that key.
+func applyAlias(values []*ast.MappingValueNode, a keyAlias) {
+	var aliased *ast.StringNode
+
+	for _, value := range values {
+		key := stringKey(value.Key)
+		if key == nil {
+			continue
+		}
+
+		if key.Value == a.key {
+			return
+		}
+
+		if key.Value == a.alias && aliased == nil {
+			aliased = key
+		}
+	}
+
+	if aliased != nil {
+		aliased.Value = a.key
+	}
+}
+
+// stringKey returns the string node of a mapping key, or nil for other keys such as
+// the merge key.
+func stringKey(key ast.MapKeyNode) *ast.StringNode {
+	var node ast.Node = key
+	if explicit, ok := key.(*ast.MappingKeyNode); ok {
+		node = explicit.Value
+	}
+
+	str, _ := node.(*ast.StringNode)
+
+	return str
+}
+
+// unmarshalDocument decodes the first document of data into target, from file instead
+// of data when it is set.
+func unmarshalDocument(data []byte, file *ast.File, target any) error {
+	if file == nil {
+		return yaml.Unmarshal(data, target) //nolint:wrapcheck // wrapped by Parse
+	}
+
+	if len(file.Docs) == 0 || file.Docs[0].Body == nil {
+		return nil
+	}
+
+	return yaml.NodeToValue(file.Docs[0].Body, target) //nolint:wrapcheck // wrapped by Parse
+}
+
+// normalizePath applies fn to each colon-separated segment of path.
+func normalizePath(path string, fn func(string) string) string {
+	if path == "" || fn == nil {
+		return path
+	}
+
+	parts := strings.Split(path, ":")
+	for i, part := range parts {
+		parts[i] = fn(part)
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// stripCommentLines replaces every line whose first non-blank character is # with an
+// empty line, except inside block scalars. The number of lines is unchanged.
+func stripCommentLines(data []byte) []byte {
+	lines := bytes.Split(data, []byte("\n"))
+
+	// blockIndent is the indentation of the line that opened a block scalar, or -1.
+	blockIndent := -1
+
+	for i, line := range lines {
+		trimmed := bytes.TrimLeft(line, " \t")
+		indent := len(line) - len(trimmed)
+		blank := len(bytes.TrimSpace(trimmed)) == 0
+
+		if blockIndent >= 0 {
+			if blank || indent > blockIndent {
+				continue
+			}
+
+			blockIndent = -1
+		}
+
+		if bytes.HasPrefix(trimmed, []byte("#")) {
+			lines[i] = nil
+
+			continue
+		}
+
+		if opensBlockScalar(trimmed) {
+			blockIndent = indent
+		}
+	}
+
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// opensBlockScalar reports whether a line ends with a block scalar indicator
+// (| or >, optionally followed by chomping and indentation indicators and a comment).
+func opensBlockScalar(line []byte) bool {
+	if idx := bytes.Index(line, []byte(" #")); idx >= 0 {
+		line = line[:idx]
+	}
+
+	line = bytes.TrimRight(line, " \t\r")
+	line = bytes.TrimRight(line, "+-0123456789")
+
+	if !bytes.HasSuffix(line, []byte("|")) && !bytes.HasSuffix(line, []byte(">")) {
+		return false
+	}
+
+	// The indicator must stand alone, as in "key: |" or "- >", not end a plain scalar.
+	return len(line) == 1 || line[len(line)-2] == ' '
+}
+
 // convertToYAMLPath converts a colon-separated path to goccy/go-yaml PathString format.
+// Each segment must match segment, otherwise ErrInvalidPathSegment is returned.
+// Segments with characters outside DefaultPathChars are single-quoted so that
+// PathString treats them as literal keys.
 // Examples:
 //   - "key" -> "$.key"
 //   - "api:permissions" -> "$.api.permissions"
-func convertToYAMLPath(path string) string {
+//   - "api:my key" -> "$.api.'my key'" (when spaces are allowed)
+func convertToYAMLPath(path string, segment *regexp.Regexp) (string, error) {
 	parts := strings.Split(path, ":")
 
-	return "$." + strings.Join(parts, ".")
+	for i, part := range parts {
+		if !segment.MatchString(part) {
+			return "", fmt.Errorf("%w %q in path %q", ErrInvalidPathSegment, part, path)
+		}
+
+		if !defaultPathSegment.MatchString(part) {
+			parts[i] = quotePathKey(part)
+		}
+	}
+
+	return "$." + strings.Join(parts, "."), nil
+}
+
+// quotePathKey encloses key in single quotes, escaping quotes and backslashes.
+func quotePathKey(key string) string {
+	key = strings.ReplaceAll(key, `\`, `\\`)
+	key = strings.ReplaceAll(key, `'`, `\'`)
+
+	return "'" + key + "'"
+}
+
+// pathSegmentPattern returns a regexp matching a non-empty run of chars.
+func pathSegmentPattern(chars string) *regexp.Regexp {
+	return regexp.MustCompile("^(?:" + chars + ")+$")
+}
+
+// collectionKind returns "mapping" or "sequence" for a document whose root node is
+// one, and an empty string otherwise.
+func collectionKind(data []byte) string {
+	var doc any
+
+	if yaml.Unmarshal(data, &doc) != nil {
+		return ""
+	}
+
+	switch reflect.ValueOf(doc).Kind() { //nolint:exhaustive // only collections matter
+	case reflect.Map:
+		return "mapping"
+	case reflect.Slice:
+		return "sequence"
+	default:
+		return ""
+	}
+}
+
+// isScalarTarget reports whether target points, possibly through several pointers,
+// to a type that cannot hold a mapping or a sequence.
+func isScalarTarget(target any) bool {
+	typ := reflect.TypeOf(target)
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ == nil {
+		return false
+	}
+
+	switch typ.Kind() { //nolint:exhaustive // everything else is a scalar
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Interface:
+		return false
+	default:
+		return true
+	}
 }
 
 // isKeyNotFoundError checks if the error indicates a key was not found.
 func isKeyNotFoundError(err error) bool {
 	return yaml.IsNotFoundNodeError(err)
 }
+
+// isTypeMismatchError checks if the error indicates a value that cannot be decoded
+// into the target type: an incompatible type, an unexpected node kind, or an overflow.
+func isTypeMismatchError(err error) bool {
+	var (
+		typeErr     *yaml.TypeError
+		nodeTypeErr *yaml.UnexpectedNodeTypeError
+		overflowErr *yaml.OverflowError
+	)
+
+	return errors.As(err, &typeErr) || errors.As(err, &nodeTypeErr) || errors.As(err, &overflowErr)
+}
@@ -1,6 +1,8 @@
 package yaml
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -264,12 +266,85 @@ func TestConvertToYAMLPath(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := convertToYAMLPath(tt.input)
+			result, err := convertToYAMLPath(tt.input, defaultPathSegment)
+			require.NoError(t, err)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
+func TestParser_Parse_InvalidPathSegment(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("server:\n  host: localhost\n")
+	parser := NewParser()
+
+	for _, path := range []string{"my server:host", "server[0]", "server:host.name", "server::host", "server:"} {
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			var result string
+
+			err := parser.Parse(data, &result, path)
+			require.ErrorIs(t, err, ErrInvalidPathSegment)
+		})
+	}
+}
+
+func TestParser_Parse_WithAllowedPathChars(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`
+"my server":
+  "tags[primary]": web
+  "it's": quoted
+  plain: value
+`)
+	parser := NewParser(WithAllowedPathChars(`[a-zA-Z0-9_ \[\]'-]`))
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "my server:tags[primary]", expected: "web"},
+		{path: "my server:it's", expected: "quoted"},
+		{path: "my server:plain", expected: "value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			var result string
+
+			err := parser.Parse(data, &result, tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	var result string
+
+	err := parser.Parse(data, &result, "my server:plain.value")
+	require.ErrorIs(t, err, ErrInvalidPathSegment, "dots are still outside the configured class")
+}
+
+func TestParser_Parse_WithAllowedPathCharsInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(WithAllowedPathChars(`[a-z`))
+
+	var result string
+
+	err := parser.Parse([]byte("key: value\n"), &result, "key")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid allowed path characters")
+
+	var doc map[string]string
+
+	require.NoError(t, parser.Parse([]byte("key: value\n"), &doc, ""), "an empty path needs no validation")
+}
+
 func TestParser_Parse_BoolValue(t *testing.T) {
 	t.Parallel()
 
@@ -311,3 +386,384 @@ config:
 	require.NoError(t, err)
 	assert.InDelta(t, 3.14159, result, 0.00001)
 }
+
+func TestParser_Parse_MergeKey(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser()
+
+	data := []byte(`
+defaults: &defaults
+  host: localhost
+  port: 8080
+  timeout: 30
+services:
+  api:
+    <<: *defaults
+    port: 9090
+  worker:
+    <<: *defaults
+`)
+
+	type service struct {
+		Host    string `yaml:"host"`
+		Port    int    `yaml:"port"`
+		Timeout int    `yaml:"timeout"`
+	}
+
+	var api service
+
+	err := parser.Parse(data, &api, "services:api")
+
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", api.Host)
+	assert.Equal(t, 9090, api.Port)
+	assert.Equal(t, 30, api.Timeout)
+
+	var worker service
+
+	err = parser.Parse(data, &worker, "services:worker")
+
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", worker.Host)
+	assert.Equal(t, 8080, worker.Port)
+
+	var host string
+
+	err = parser.Parse(data, &host, "services:worker:host")
+
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", host)
+}
+
+func TestParser_Parse_PathNotFoundVsTypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser()
+
+	data := []byte(`
+server:
+  host: localhost
+  port: 8080
+  labels:
+    env: prod
+`)
+
+	tests := []struct {
+		name     string
+		path     string
+		target   func() any
+		wantErr  error
+		otherErr error
+	}{
+		{
+			name:     "missing path",
+			path:     "server:missing",
+			target:   func() any { return new(int) },
+			wantErr:  ErrPathNotFound,
+			otherErr: ErrTypeMismatch,
+		},
+		{
+			name:     "string map into int",
+			path:     "server:labels",
+			target:   func() any { return new(int) },
+			wantErr:  ErrTypeMismatch,
+			otherErr: ErrPathNotFound,
+		},
+		{
+			name:     "string into int",
+			path:     "server:host",
+			target:   func() any { return new(int) },
+			wantErr:  ErrTypeMismatch,
+			otherErr: ErrPathNotFound,
+		},
+		{
+			name:     "scalar into struct",
+			path:     "server:port",
+			target:   func() any { return new(struct{ Port int }) },
+			wantErr:  ErrTypeMismatch,
+			otherErr: ErrPathNotFound,
+		},
+		{
+			name:     "overflow",
+			path:     "server:port",
+			target:   func() any { return new(int8) },
+			wantErr:  ErrTypeMismatch,
+			otherErr: ErrPathNotFound,
+		},
+		{
+			name:     "whole document",
+			path:     "",
+			target:   func() any { return new(map[string]int) },
+			wantErr:  ErrTypeMismatch,
+			otherErr: ErrPathNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := parser.Parse(data, tt.target(), tt.path)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.NotErrorIs(t, err, tt.otherErr)
+		})
+	}
+}
+
+func TestParser_Parse_EmptyPathScalarTarget(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser()
+
+	var port int
+
+	err := parser.Parse([]byte("server:\n  port: 8080\n"), &port, "")
+	require.ErrorIs(t, err, ErrTypeMismatch)
+	assert.Contains(t, err.Error(), "document is a mapping but target *int is a scalar")
+	assert.Contains(t, err.Error(), "use a path")
+
+	var name string
+
+	err = parser.Parse([]byte("- a\n- b\n"), &name, "")
+	require.ErrorIs(t, err, ErrTypeMismatch)
+	assert.Contains(t, err.Error(), "document is a sequence but target *string is a scalar")
+
+	var values map[string]int
+
+	err = parser.Parse([]byte("server:\n  port: 8080\n"), &values, "")
+	require.ErrorIs(t, err, ErrTypeMismatch)
+	assert.NotContains(t, err.Error(), "is a scalar", "collection targets keep the decoder error")
+}
+
+func TestParser_Parse_WithStripComments(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`# Service configuration
+# maintained by the platform team
+server:
+  # public hostname
+  host: example.com # trailing comments are handled by the decoder
+    # oddly indented comment
+  port: 8080
+  motd: |
+    # this line is content, not a comment
+    welcome
+  banner: >-
+    # folded content
+  tags:
+    # first tag
+    - api
+    - "#not-a-comment"
+`)
+
+	type server struct {
+		Host   string   `yaml:"host"`
+		Port   int      `yaml:"port"`
+		Motd   string   `yaml:"motd"`
+		Banner string   `yaml:"banner"`
+		Tags   []string `yaml:"tags"`
+	}
+
+	var withStrip, without server
+
+	require.NoError(t, NewParser(WithStripComments()).Parse(data, &withStrip, "server"))
+	require.NoError(t, NewParser().Parse(data, &without, "server"))
+
+	assert.Equal(t, "example.com", withStrip.Host)
+	assert.Equal(t, 8080, withStrip.Port)
+	assert.Equal(t, "# this line is content, not a comment\nwelcome\n", withStrip.Motd)
+	assert.Equal(t, "# folded content", withStrip.Banner)
+	assert.Equal(t, []string{"api", "#not-a-comment"}, withStrip.Tags)
+	assert.Equal(t, without, withStrip, "stripping comments should not change the structure")
+}
+
+func TestStripCommentLines_PreservesLineCount(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("# header\nkey: value\n  # indented\nlist:\n  - a\n")
+
+	stripped := stripCommentLines(data)
+
+	assert.Equal(t, "\nkey: value\n\nlist:\n  - a\n", string(stripped))
+	assert.Equal(t, bytes.Count(data, []byte("\n")), bytes.Count(stripped, []byte("\n")))
+}
+
+func TestParser_Parse_WithStripComments_ErrorLine(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("# comment\n# comment\nkey: [unclosed\n")
+
+	var withStrip, without map[string]any
+
+	errStrip := NewParser(WithStripComments()).Parse(data, &withStrip, "")
+	errPlain := NewParser().Parse(data, &without, "")
+
+	require.Error(t, errStrip)
+	require.Error(t, errPlain)
+	// The source excerpt differs, but the reported position is the same.
+	position := func(err error) string {
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+
+		return msg
+	}
+
+	assert.Contains(t, errStrip.Error(), "[3:6]")
+	assert.Equal(t, position(errPlain), position(errStrip), "errors should report the original line numbers")
+}
+
+func TestParser_Parse_WithKeyNormalization(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`
+Server:
+  HOST: x
+  Port: 8080
+  Tags:
+    - {Name: a}
+    - {NAME: b}
+  Version: "1.0"
+`)
+
+	type tag struct {
+		Name string `yaml:"name"`
+	}
+
+	type server struct {
+		Host    string `yaml:"host"`
+		Port    int    `yaml:"port"`
+		Tags    []tag  `yaml:"tags"`
+		Version string `yaml:"version"`
+	}
+
+	parser := NewParser(WithKeyNormalization(strings.ToLower))
+
+	var atPath server
+
+	require.NoError(t, parser.Parse(data, &atPath, "server"))
+	assert.Equal(t, server{Host: "x", Port: 8080, Tags: []tag{{Name: "a"}, {Name: "b"}}, Version: "1.0"}, atPath)
+
+	var mixedCasePath server
+
+	require.NoError(t, parser.Parse(data, &mixedCasePath, "SERVER"), "path segments should be normalized too")
+	assert.Equal(t, atPath, mixedCasePath)
+
+	var root struct {
+		Server server `yaml:"server"`
+	}
+
+	require.NoError(t, parser.Parse(data, &root, ""))
+	assert.Equal(t, atPath, root.Server)
+
+	var withoutNormalization server
+
+	require.NoError(t, NewParser().Parse(data, &withoutNormalization, "Server"))
+	assert.Empty(t, withoutNormalization.Host, "keys are not normalized by default")
+}
+
+func TestParser_Parse_WithKeyNormalizationCollision(t *testing.T) {
+	t.Parallel()
+
+	var target map[string]string
+
+	err := NewParser(WithKeyNormalization(strings.ToLower)).Parse([]byte("Host: a\nhost: b\n"), &target, "")
+	require.ErrorIs(t, err, ErrKeyCollision)
+	assert.Contains(t, err.Error(), `"host"`)
+}
+
+func TestParser_Parse_WithKeyNormalizationKeepsPositionsAndAnchors(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(WithStripComments(), WithKeyNormalization(strings.ToLower))
+
+	var anchored struct {
+		Server struct {
+			Host string `yaml:"host"`
+		} `yaml:"server"`
+	}
+
+	require.NoError(t, parser.Parse([]byte("Base: &base\n  Host: x\nServer: *base\n"), &anchored, ""))
+	assert.Equal(t, "x", anchored.Server.Host, "keys behind an alias should be normalized at the anchor")
+
+	var target struct {
+		Port int `yaml:"port"`
+	}
+
+	err := parser.Parse([]byte("# comment\n\n\nName: api\nPort: not-a-number\n"), &target, "")
+	require.ErrorIs(t, err, ErrTypeMismatch)
+	assert.Contains(t, err.Error(), "[5:7]", "errors should report the original line numbers")
+}
+
+func TestParser_Parse_WithYAMLAlias(t *testing.T) {
+	t.Parallel()
+
+	type settings struct {
+		Timeout int    `yaml:"timeout"`
+		Host    string `yaml:"host"`
+	}
+
+	parser := NewParser(WithYAMLAlias("timeout", "request_timeout"))
+
+	var root settings
+
+	require.NoError(t, parser.Parse([]byte("request_timeout: 30\nhost: x\n"), &root, ""))
+	assert.Equal(t, settings{Timeout: 30, Host: "x"}, root)
+
+	var atPath settings
+
+	require.NoError(t, parser.Parse([]byte("server:\n  request_timeout: 30\n"), &atPath, "server"))
+	assert.Equal(t, 30, atPath.Timeout, "aliases should apply to the mapping at the path")
+
+	var nested struct {
+		Server settings `yaml:"server"`
+	}
+
+	require.NoError(t, parser.Parse([]byte("server:\n  request_timeout: 30\n"), &nested, ""))
+	assert.Zero(t, nested.Server.Timeout, "aliases should not apply to nested mappings")
+
+	scoped := NewParser(WithYAMLAlias("server:timeout", "request_timeout"))
+	require.NoError(t, scoped.Parse([]byte("server:\n  request_timeout: 30\n"), &nested, ""))
+	assert.Equal(t, 30, nested.Server.Timeout, "scoped aliases should apply to the mapping at their path")
+
+	var primaryWins settings
+
+	require.NoError(t, parser.Parse([]byte("timeout: 10\nrequest_timeout: 30\n"), &primaryWins, ""))
+	assert.Equal(t, 10, primaryWins.Timeout, "the primary key should take precedence")
+
+	var withoutAlias settings
+
+	require.NoError(t, NewParser().Parse([]byte("request_timeout: 30\n"), &withoutAlias, ""))
+	assert.Zero(t, withoutAlias.Timeout)
+}
+
+func TestParser_Parse_WithYAMLAliasLeavesUnrelatedMappings(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("request_timeout: 30\nlabels:\n  request_timeout: keep\n")
+
+	var target map[string]any
+
+	require.NoError(t, NewParser(WithYAMLAlias("timeout", "request_timeout")).Parse(data, &target, ""))
+	assert.Equal(t, map[string]any{
+		"timeout": uint64(30),
+		"labels":  map[string]any{"request_timeout": "keep"},
+	}, target)
+}
+
+func TestParser_Parse_WithYAMLAliasMultiple(t *testing.T) {
+	t.Parallel()
+
+	var target struct {
+		URL string `yaml:"database_url"`
+	}
+
+	parser := NewParser(
+		WithYAMLAlias("database_url", "db_url"),
+		WithYAMLAlias("database_url", "dsn"),
+		WithKeyNormalization(strings.ToLower),
+	)
+
+	require.NoError(t, parser.Parse([]byte("DSN: b\nDB_URL: a\n"), &target, ""))
+	assert.Equal(t, "a", target.URL, "the first alias present should win, after normalization")
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// WatchingFetcher is a DataFetcher that reports changes to the underlying data.
+// After a value is received from Changes, Fetch returns the updated data.
+// See config/fetcher/file for a polling file implementation.
+type WatchingFetcher interface {
+	DataFetcher
+
+	// Changes returns a channel that receives a value each time the data changes.
+	// The channel is closed when the fetcher stops watching.
+	Changes() <-chan struct{}
+}
+
+// ReloadableProvider keeps a configuration up to date with a WatchingFetcher.
+// On every change it re-parses the data into a new T, applies defaults, validates it
+// like Provider does, and publishes it to subscribers. Updates that fail are logged
+// and discarded, so the last valid configuration stays current.
+type ReloadableProvider[T any] struct {
+	path    string
+	parser  Parser
+	fetcher WatchingFetcher
+
+	mu          sync.RWMutex
+	current     *T
+	subscribers []chan *T
+	closed      bool
+
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewReloadableProvider loads the initial configuration and starts watching fetcher for
+// changes. It returns an error if the initial configuration cannot be loaded or is invalid.
+// Call Close (e.g. from an fx.Lifecycle OnStop hook) to stop watching.
+func NewReloadableProvider[T any](path string, parser Parser, fetcher WatchingFetcher) (*ReloadableProvider[T], error) {
+	initial, err := load(new(T), path, parser, fetcher)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &ReloadableProvider[T]{
+		path:    path,
+		parser:  parser,
+		fetcher: fetcher,
+		current: initial,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go provider.watch()
+
+	return provider, nil
+}
+
+// Current returns the latest valid configuration.
+func (p *ReloadableProvider[T]) Current() *T {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.current
+}
+
+// Subscribe returns a channel receiving each newly applied configuration.
+// The channel holds only the latest update: a subscriber that falls behind skips
+// intermediate versions rather than blocking reloads. It is closed by Close.
+func (p *ReloadableProvider[T]) Subscribe() <-chan *T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch := make(chan *T, 1)
+
+	if p.closed {
+		close(ch)
+
+		return ch
+	}
+
+	p.subscribers = append(p.subscribers, ch)
+
+	return ch
+}
+
+// Close stops watching for changes and closes all subscriber channels.
+// It does not close the fetcher. It is safe to call more than once.
+func (p *ReloadableProvider[T]) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		<-p.stopped
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.closed = true
+
+		for _, ch := range p.subscribers {
+			close(ch)
+		}
+
+		p.subscribers = nil
+	})
+}
+
+// watch reloads the configuration on every change until Close is called
+// or the fetcher's Changes channel is closed.
+func (p *ReloadableProvider[T]) watch() {
+	defer close(p.stopped)
+
+	changes := p.fetcher.Changes()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case _, ok := <-changes:
+			if !ok {
+				return
+			}
+
+			p.reload()
+		}
+	}
+}
+
+// reload loads a fresh T and publishes it, or logs and discards it on failure.
+func (p *ReloadableProvider[T]) reload() {
+	next, err := load(new(T), p.path, p.parser, p.fetcher)
+	if err != nil {
+		slog.Error("config reload rejected, keeping current config",
+			slog.String("path", p.path), slog.Any("error", err))
+
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = next
+
+	for _, ch := range p.subscribers {
+		// Replace a pending update the subscriber has not received yet.
+		select {
+		case <-ch:
+		default:
+		}
+
+		ch <- next
+	}
+
+	slog.Info("config reloaded", slog.String("path", p.path))
+}
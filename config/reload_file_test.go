@@ -0,0 +1,56 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xalexb/hjarta-di/config"
+	filefetcher "github.com/0xalexb/hjarta-di/config/fetcher/file"
+	yamlparser "github.com/0xalexb/hjarta-di/config/parser/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadableProvider_FileChange(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  host: a.example.com\n  port: 8080\n"), 0o600))
+
+	fetcher, err := filefetcher.NewWatchingFetcher(configPath, 10*time.Millisecond)()
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = fetcher.Close() })
+
+	provider, err := config.NewReloadableProvider[AppConfig]("server", yamlparser.NewParser(), fetcher)
+	require.NoError(t, err)
+
+	t.Cleanup(provider.Close)
+
+	assert.Equal(t, "a.example.com", provider.Current().Host)
+
+	updates := provider.Subscribe()
+
+	// An invalid port fails AppConfig.Validate and must be rejected.
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  host: bad.example.com\n  port: 99999\n"), 0o600))
+
+	select {
+	case cfg := <-updates:
+		t.Fatalf("invalid config must not be published, got %+v", cfg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Equal(t, "a.example.com", provider.Current().Host)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  host: b.example.com\n  port: 9090\n"), 0o600))
+
+	select {
+	case cfg := <-updates:
+		assert.Equal(t, "b.example.com", cfg.Host)
+		assert.Equal(t, 9090, cfg.Port)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reloaded config")
+	}
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockWatchingFetcher is a WatchingFetcher whose data and change notifications are set by tests.
+type mockWatchingFetcher struct {
+	mu      sync.Mutex
+	data    []byte
+	changes chan struct{}
+}
+
+func newMockWatchingFetcher(data string) *mockWatchingFetcher {
+	return &mockWatchingFetcher{data: []byte(data), changes: make(chan struct{})}
+}
+
+func (m *mockWatchingFetcher) Fetch() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.data, nil
+}
+
+func (m *mockWatchingFetcher) Changes() <-chan struct{} {
+	return m.changes
+}
+
+// change replaces the data and blocks until the provider has picked up the notification.
+func (m *mockWatchingFetcher) change(data string) {
+	m.mu.Lock()
+	m.data = []byte(data)
+	m.mu.Unlock()
+
+	m.changes <- struct{}{}
+}
+
+type reloadConfig struct {
+	Value string
+}
+
+func (c *reloadConfig) Validate() error {
+	if c.Value == "invalid" {
+		return errors.New("invalid value")
+	}
+
+	return nil
+}
+
+// rawParser stores the raw data as the config value.
+var rawParser = &mockParser{ //nolint:gochecknoglobals
+	parseFunc: func(data []byte, target any, _ string) error {
+		cfg, ok := target.(*reloadConfig)
+		if !ok {
+			return errors.New("invalid target type")
+		}
+
+		cfg.Value = string(data)
+
+		return nil
+	},
+}
+
+func receive(t *testing.T, ch <-chan *reloadConfig) *reloadConfig {
+	t.Helper()
+
+	select {
+	case cfg := <-ch:
+		return cfg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for config update")
+
+		return nil
+	}
+}
+
+func TestReloadableProvider_PublishesValidatedChange(t *testing.T) {
+	t.Parallel()
+
+	fetcher := newMockWatchingFetcher("v1")
+
+	provider, err := NewReloadableProvider[reloadConfig]("app", rawParser, fetcher)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer provider.Close()
+
+	if got := provider.Current().Value; got != "v1" {
+		t.Fatalf("expected initial value v1, got %q", got)
+	}
+
+	updates := provider.Subscribe()
+
+	fetcher.change("v2")
+
+	cfg := receive(t, updates)
+	if cfg.Value != "v2" {
+		t.Errorf("expected published value v2, got %q", cfg.Value)
+	}
+
+	if provider.Current() != cfg {
+		t.Error("expected Current to return the published config")
+	}
+}
+
+func TestReloadableProvider_RejectsInvalidChange(t *testing.T) {
+	t.Parallel()
+
+	fetcher := newMockWatchingFetcher("v1")
+
+	provider, err := NewReloadableProvider[reloadConfig]("app", rawParser, fetcher)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer provider.Close()
+
+	initial := provider.Current()
+	updates := provider.Subscribe()
+
+	fetcher.change("invalid")
+	// The provider handles notifications in order, so the next valid change
+	// is the first one published if the invalid one was discarded.
+	fetcher.change("v3")
+
+	cfg := receive(t, updates)
+	if cfg.Value != "v3" {
+		t.Errorf("expected invalid update to be discarded, got %q", cfg.Value)
+	}
+
+	if initial.Value != "v1" {
+		t.Errorf("previous config must not be mutated, got %q", initial.Value)
+	}
+}
+
+func TestReloadableProvider_InvalidInitialConfig(t *testing.T) {
+	t.Parallel()
+
+	provider, err := NewReloadableProvider[reloadConfig]("app", rawParser, newMockWatchingFetcher("invalid"))
+	if err == nil {
+		t.Fatal("expected error for invalid initial config, got nil")
+	}
+
+	if provider != nil {
+		t.Error("expected provider to be nil")
+	}
+}
+
+func TestReloadableProvider_CloseClosesSubscribers(t *testing.T) {
+	t.Parallel()
+
+	provider, err := NewReloadableProvider[reloadConfig]("app", rawParser, newMockWatchingFetcher("v1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updates := provider.Subscribe()
+
+	provider.Close()
+	provider.Close()
+
+	if _, ok := <-updates; ok {
+		t.Error("expected subscriber channel to be closed")
+	}
+
+	if _, ok := <-provider.Subscribe(); ok {
+		t.Error("expected Subscribe after Close to return a closed channel")
+	}
+}
@@ -0,0 +1,330 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrSchemaValidation is returned when a struct fails its validate tag rules.
+// The individual failures are joined into the error as *FieldError values.
+var ErrSchemaValidation = errors.New("schema validation failed")
+
+// ErrInvalidValidateTag is returned when a validate tag contains an unknown rule
+// or a malformed parameter.
+var ErrInvalidValidateTag = errors.New("invalid validate tag")
+
+var (
+	errUnknownRule     = errors.New("unknown rule")
+	errMissingParam    = errors.New("requires a parameter")
+	errUnsupportedKind = errors.New("unsupported kind")
+)
+
+// validateTag is the struct tag holding validation rules.
+const validateTag = "validate"
+
+// FieldError describes a single configuration field that failed validation.
+// Path and Message are always set: user Validate methods report their own failures
+// with them, usually via NewFieldError, and ValidateStruct derives them from the
+// failed tag rule, so Provider errors point at the offending config key. ValidateStruct
+// additionally sets Field, Rule and Param.
+type FieldError struct {
+	// Field is the dotted Go field path, e.g. "Server.Port".
+	Field string
+	// Rule is the failed rule name, e.g. "min".
+	Rule string
+	// Param is the rule parameter, e.g. "1" for "min=1". Empty for rules without one.
+	Param string
+	// Path is the dotted config key path, e.g. "api.port". ValidateStruct builds it
+	// from each field's yaml tag, else its json tag, else its lowercased name, relative
+	// to the target; Providers prefix it with their config path.
+	Path string
+	// Message describes the failure, e.g. "must be 1-65535".
+	Message string
+}
+
+// NewFieldError returns a *FieldError for the config key at path, for use in Validate
+// methods. Its message reads "config error at <path>: <message>".
+func NewFieldError(path, message string) *FieldError {
+	return &FieldError{Path: path, Message: message}
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("config error at %s: %s", e.Path, e.Message)
+}
+
+// ValidateStruct checks target against the rules in its `validate` struct tags.
+// Rules are comma-separated; supported rules are:
+//   - required: the field must not be the zero value
+//   - min=N / max=N: bounds on the value for numbers, on the length for strings, slices and maps
+//   - oneof=a b c: the value, formatted as a string, must be one of the space-separated options
+//
+// Nested structs and pointers to structs are validated recursively; nil pointers are
+// only checked by required. Failures are reported together as an error wrapping
+// ErrSchemaValidation and one *FieldError per failure. Targets that are not structs
+// are accepted as is.
+func ValidateStruct[T any](target *T) error {
+	return validateStruct(target, "")
+}
+
+// validateStruct implements ValidateStruct, prefixing each FieldError.Path with
+// pathPrefix, a colon-separated config path as given to Provider, converted to the
+// dotted form.
+func validateStruct[T any](target *T, pathPrefix string) error {
+	if target == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(target).Elem()
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fieldErrs []error
+
+	err := validateStructValue(value, "", strings.ReplaceAll(pathPrefix, ":", "."), &fieldErrs)
+	if err != nil {
+		return err
+	}
+
+	if len(fieldErrs) > 0 {
+		return fmt.Errorf("%w: %w", ErrSchemaValidation, errors.Join(fieldErrs...))
+	}
+
+	return nil
+}
+
+// hasValidateTags reports whether typ, or any struct it embeds or references, declares validate tags.
+func hasValidateTags(typ reflect.Type) bool {
+	return hasValidateTagsSeen(typ, map[reflect.Type]bool{})
+}
+
+func hasValidateTagsSeen(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return false
+	}
+
+	seen[typ] = true
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if _, ok := field.Tag.Lookup(validateTag); ok {
+			return true
+		}
+
+		if hasValidateTagsSeen(field.Type, seen) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateStructValue applies validate tag rules to the exported fields of value.
+// Rule failures are appended to fieldErrs; malformed tags are returned as an error.
+// prefix is the Go field path of value, with a trailing dot, and pathPrefix its
+// config key path.
+func validateStructValue(value reflect.Value, prefix, pathPrefix string, fieldErrs *[]error) error {
+	typ := value.Type()
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + field.Name
+		path := joinConfigPath(pathPrefix, configKey(field))
+		fieldValue := value.Field(i)
+
+		if tag := field.Tag.Get(validateTag); tag != "" && tag != "-" {
+			err := validateField(fieldValue, name, path, tag, fieldErrs)
+			if err != nil {
+				return err
+			}
+		}
+
+		nested := fieldValue
+		for nested.Kind() == reflect.Pointer && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+
+		if nested.Kind() == reflect.Struct {
+			err := validateStructValue(nested, name+".", path, fieldErrs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// configKey returns the config key of field: its yaml tag name, else its json tag
+// name, else its lowercased name, as the YAML parser decodes it. Inlined fields
+// (`yaml:",inline"`) have no key of their own, so it returns an empty string for them.
+func configKey(field reflect.StructField) string {
+	tag := field.Tag.Get("yaml")
+	if tag == "" {
+		tag = field.Tag.Get("json")
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if slices.Contains(strings.Split(options, ","), "inline") {
+		return ""
+	}
+
+	if name != "" && name != "-" {
+		return name
+	}
+
+	return strings.ToLower(field.Name)
+}
+
+// joinConfigPath appends key to the dotted config path prefix, skipping empty parts.
+func joinConfigPath(prefix, key string) string {
+	if prefix == "" || key == "" {
+		return prefix + key
+	}
+
+	return prefix + "." + key
+}
+
+// validateField applies the comma-separated rules in tag to a single field.
+func validateField(value reflect.Value, name, path, tag string, fieldErrs *[]error) error {
+	for rule := range strings.SplitSeq(tag, ",") {
+		ruleName, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if ruleName == "" {
+			continue
+		}
+
+		if ruleName == "required" {
+			if value.IsZero() {
+				*fieldErrs = append(*fieldErrs, &FieldError{
+					Field: name, Rule: ruleName, Path: path, Message: "is required",
+				})
+			}
+
+			continue
+		}
+
+		target := value
+		for target.Kind() == reflect.Pointer {
+			if target.IsNil() {
+				break
+			}
+
+			target = target.Elem()
+		}
+
+		if target.Kind() == reflect.Pointer {
+			continue
+		}
+
+		ok, err := checkRule(target, ruleName, param)
+		if err != nil {
+			return fmt.Errorf("%w: field %s: %w", ErrInvalidValidateTag, name, err)
+		}
+
+		if !ok {
+			*fieldErrs = append(*fieldErrs, &FieldError{
+				Field: name, Rule: ruleName, Param: param, Path: path, Message: ruleMessage(target, ruleName, param),
+			})
+		}
+	}
+
+	return nil
+}
+
+// checkRule reports whether value satisfies the rule. It returns an error for
+// unknown rules, missing or malformed parameters, and unsupported field kinds.
+func checkRule(value reflect.Value, rule, param string) (bool, error) {
+	switch rule {
+	case "min", "max":
+		if param == "" {
+			return false, fmt.Errorf("rule %q %w", rule, errMissingParam)
+		}
+
+		bound, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false, fmt.Errorf("rule %q: %w", rule, err)
+		}
+
+		measure, err := measureOf(value)
+		if err != nil {
+			return false, fmt.Errorf("rule %q: %w", rule, err)
+		}
+
+		if rule == "min" {
+			return measure >= bound, nil
+		}
+
+		return measure <= bound, nil
+	case "oneof":
+		if param == "" {
+			return false, fmt.Errorf("rule %q %w", rule, errMissingParam)
+		}
+
+		actual := fmt.Sprint(value.Interface())
+
+		for option := range strings.FieldsSeq(param) {
+			if option == actual {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w %q", errUnknownRule, rule)
+	}
+}
+
+// ruleMessage describes the failure of a min, max or oneof rule on value.
+func ruleMessage(value reflect.Value, rule, param string) string {
+	subject := "must be"
+
+	//nolint:exhaustive // only lengths are worded differently
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		subject = "length must be"
+	}
+
+	switch rule {
+	case "min":
+		return fmt.Sprintf("%s at least %s", subject, param)
+	case "max":
+		return fmt.Sprintf("%s at most %s", subject, param)
+	default:
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	}
+}
+
+// measureOf returns the number compared by min/max: the value of numeric kinds,
+// the length of strings, slices, arrays and maps.
+func measureOf(value reflect.Value) (float64, error) {
+	//nolint:exhaustive // remaining kinds are unsupported
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), nil
+	default:
+		return 0, fmt.Errorf("%w %s", errUnsupportedKind, value.Kind())
+	}
+}
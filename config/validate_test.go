@@ -0,0 +1,422 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type portConfig struct {
+	Port int `validate:"min=1,max=65535"`
+}
+
+type serverConfig struct {
+	Host    string   `validate:"required"`
+	Mode    string   `validate:"oneof=dev prod"`
+	Tags    []string `validate:"max=2"`
+	Listen  portConfig
+	Backup  *portConfig
+	Timeout *int `validate:"required,min=1"`
+}
+
+type validatedTaggedConfig struct {
+	Port int `validate:"min=1"`
+	err  error
+}
+
+func (c *validatedTaggedConfig) Validate() error {
+	return c.err
+}
+
+// collectFieldErrors walks the error tree and returns every *FieldError in it.
+func collectFieldErrors(err error) []*FieldError {
+	var result []*FieldError
+
+	switch wrapped := err.(type) { //nolint:errorlint // walking the tree explicitly
+	case *FieldError:
+		result = append(result, wrapped)
+	case interface{ Unwrap() []error }:
+		for _, e := range wrapped.Unwrap() {
+			result = append(result, collectFieldErrors(e)...)
+		}
+	case interface{ Unwrap() error }:
+		result = collectFieldErrors(wrapped.Unwrap())
+	}
+
+	return result
+}
+
+func TestValidateStruct_PortBounds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		port    int
+		wantErr bool
+	}{
+		{name: "zero rejected", port: 0, wantErr: true},
+		{name: "above range rejected", port: 99999, wantErr: true},
+		{name: "valid port accepted", port: 8080, wantErr: false},
+		{name: "lower bound accepted", port: 1, wantErr: false},
+		{name: "upper bound accepted", port: 65535, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateStruct(&portConfig{Port: tt.port})
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, ErrSchemaValidation) {
+				t.Fatalf("expected ErrSchemaValidation, got %v", err)
+			}
+
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("expected *FieldError in %v", err)
+			}
+
+			if fieldErr.Field != "Port" {
+				t.Errorf("expected field Port, got %q", fieldErr.Field)
+			}
+		})
+	}
+}
+
+func TestValidateStruct_CollectsAllFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	timeout := 0
+	cfg := serverConfig{
+		Mode:    "staging",
+		Tags:    []string{"a", "b", "c"},
+		Listen:  portConfig{Port: 0},
+		Backup:  &portConfig{Port: 70000},
+		Timeout: &timeout,
+	}
+
+	err := ValidateStruct(&cfg)
+	if !errors.Is(err, ErrSchemaValidation) {
+		t.Fatalf("expected ErrSchemaValidation, got %v", err)
+	}
+
+	got := map[string]string{}
+	for _, fieldErr := range collectFieldErrors(err) {
+		got[fieldErr.Field] = fieldErr.Rule
+	}
+
+	want := map[string]string{
+		"Host":        "required",
+		"Mode":        "oneof",
+		"Tags":        "max",
+		"Listen.Port": "min",
+		"Backup.Port": "max",
+		"Timeout":     "min",
+	}
+
+	for field, rule := range want {
+		if got[field] != rule {
+			t.Errorf("field %s: expected rule %q, got %q", field, rule, got[field])
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("expected %d field errors, got %d: %v", len(want), len(got), got)
+	}
+}
+
+func TestValidateStruct_NilPointerOnlyCheckedByRequired(t *testing.T) {
+	t.Parallel()
+
+	cfg := serverConfig{Host: "localhost", Mode: "dev", Listen: portConfig{Port: 80}}
+
+	err := ValidateStruct(&cfg)
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("expected *FieldError, got %v", err)
+	}
+
+	if fieldErr.Field != "Timeout" || fieldErr.Rule != "required" {
+		t.Errorf("expected Timeout required failure, got %+v", fieldErr)
+	}
+}
+
+func TestValidateStruct_InvalidTag(t *testing.T) {
+	t.Parallel()
+
+	type badRule struct {
+		Name string `validate:"email"`
+	}
+
+	type badParam struct {
+		Port int `validate:"min=abc"`
+	}
+
+	err := ValidateStruct(&badRule{})
+	if !errors.Is(err, ErrInvalidValidateTag) {
+		t.Errorf("expected ErrInvalidValidateTag for unknown rule, got %v", err)
+	}
+
+	if !errors.Is(err, errUnknownRule) {
+		t.Errorf("expected errUnknownRule for unknown rule, got %v", err)
+	}
+
+	err = ValidateStruct(&badParam{})
+	if !errors.Is(err, ErrInvalidValidateTag) {
+		t.Errorf("expected ErrInvalidValidateTag for malformed parameter, got %v", err)
+	}
+}
+
+func TestValidateStruct_NonStruct(t *testing.T) {
+	t.Parallel()
+
+	value := 0
+
+	err := ValidateStruct(&value)
+	if err != nil {
+		t.Errorf("expected nil error for non-struct target, got %v", err)
+	}
+}
+
+func TestProvider_ValidatesStructTags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		port    int
+		wantErr bool
+	}{
+		{name: "invalid port", port: 0, wantErr: true},
+		{name: "valid port", port: 8080, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := &mockParser{
+				parseFunc: func(_ []byte, target any, _ string) error {
+					cfg, ok := target.(*portConfig)
+					if !ok {
+						return errors.New("invalid target type")
+					}
+
+					cfg.Port = tt.port
+
+					return nil
+				},
+			}
+			fetcher := &mockDataFetcher{
+				fetchFunc: func() ([]byte, error) {
+					return []byte("data"), nil
+				},
+			}
+
+			result, err := Provider(&portConfig{}, "")(parser, fetcher)
+			if tt.wantErr {
+				if !errors.Is(err, ErrSchemaValidation) {
+					t.Fatalf("expected ErrSchemaValidation, got %v", err)
+				}
+
+				if result != nil {
+					t.Error("expected result to be nil")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Port != tt.port {
+				t.Errorf("expected port %d, got %d", tt.port, result.Port)
+			}
+		})
+	}
+}
+
+func TestProvider_ValidatorTakesPrecedenceOverTags(t *testing.T) {
+	t.Parallel()
+
+	parser := &mockParser{
+		parseFunc: func(_ []byte, _ any, _ string) error {
+			return nil
+		},
+	}
+	fetcher := &mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return []byte("data"), nil
+		},
+	}
+
+	// Port 0 violates the min=1 tag, but Validate is authoritative and returns nil.
+	result, err := Provider(&validatedTaggedConfig{}, "")(parser, fetcher)
+	if err != nil {
+		t.Fatalf("expected tags to be skipped for Validator targets, got %v", err)
+	}
+
+	if result == nil {
+		t.Fatal("expected result, got nil")
+	}
+}
+
+type apiConfig struct {
+	Port int
+	Host string
+}
+
+func (c *apiConfig) Validate() error {
+	var errs []error
+
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, NewFieldError("api.port", "must be 1-65535"))
+	}
+
+	if c.Host == "" {
+		errs = append(errs, NewFieldError("api.host", "must not be empty"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func TestProvider_FormatsFieldErrorPath(t *testing.T) {
+	t.Parallel()
+
+	parser := &mockParser{
+		parseFunc: func(_ []byte, target any, _ string) error {
+			cfg, ok := target.(*apiConfig)
+			if !ok {
+				return errors.New("invalid target type")
+			}
+
+			cfg.Port = 70000
+
+			return nil
+		},
+	}
+	fetcher := &mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return []byte("data"), nil
+		},
+	}
+
+	_, err := Provider(&apiConfig{}, "api")(parser, fetcher)
+	if err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+
+	for _, want := range []string{
+		"config error at api.port: must be 1-65535",
+		"config error at api.host: must not be empty",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got %q", want, err.Error())
+		}
+	}
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("expected *FieldError in %v", err)
+	}
+
+	if fieldErr.Path != "api.port" || fieldErr.Message != "must be 1-65535" {
+		t.Errorf("unexpected field error %+v", fieldErr)
+	}
+}
+
+type taggedListenConfig struct {
+	Port int `validate:"min=1" yaml:"port"`
+}
+
+type taggedAPIConfig struct {
+	BaseURL string             `validate:"required" yaml:"base_url,omitempty"`
+	Mode    string             `json:"mode" validate:"oneof=dev prod"`
+	Tags    []string           `validate:"max=1"`
+	Listen  taggedListenConfig `yaml:"listen"`
+}
+
+func TestValidateStruct_SetsPathAndMessage(t *testing.T) {
+	t.Parallel()
+
+	err := ValidateStruct(&taggedAPIConfig{Mode: "staging", Tags: []string{"a", "b"}})
+
+	got := map[string]string{}
+	for _, fieldErr := range collectFieldErrors(err) {
+		got[fieldErr.Path] = fieldErr.Message
+	}
+
+	want := map[string]string{
+		"base_url":    "is required",
+		"mode":        "must be one of dev, prod",
+		"tags":        "length must be at most 1",
+		"listen.port": "must be at least 1",
+	}
+
+	for path, message := range want {
+		if got[path] != message {
+			t.Errorf("path %s: expected message %q, got %q", path, message, got[path])
+		}
+	}
+
+	if !strings.Contains(err.Error(), "config error at listen.port: must be at least 1") {
+		t.Errorf("expected the error to name the config key, got %q", err.Error())
+	}
+}
+
+func TestProvider_PrefixesTagFieldErrorPath(t *testing.T) {
+	t.Parallel()
+
+	parser := &mockParser{
+		parseFunc: func(_ []byte, _ any, _ string) error {
+			return nil
+		},
+	}
+	fetcher := &mockDataFetcher{
+		fetchFunc: func() ([]byte, error) {
+			return []byte("data"), nil
+		},
+	}
+
+	_, err := Provider(&taggedAPIConfig{BaseURL: "http://api", Mode: "dev"}, "services:api")(parser, fetcher)
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("expected *FieldError in %v", err)
+	}
+
+	if fieldErr.Path != "services.api.listen.port" || fieldErr.Field != "Listen.Port" {
+		t.Errorf("unexpected field error %+v", fieldErr)
+	}
+}
+
+type InlineBase struct {
+	Name string `validate:"required" yaml:"name"`
+}
+
+type inlineConfig struct {
+	InlineBase `yaml:",inline"`
+
+	Server taggedListenConfig `yaml:"server"`
+}
+
+func TestValidateStruct_InlineAddsNoPathSegment(t *testing.T) {
+	t.Parallel()
+
+	got := map[string]bool{}
+	for _, fieldErr := range collectFieldErrors(ValidateStruct(&inlineConfig{})) {
+		got[fieldErr.Path] = true
+	}
+
+	if !got["name"] || !got["server.port"] || len(got) != 2 {
+		t.Errorf("expected paths name and server.port, got %v", got)
+	}
+}
@@ -0,0 +1,29 @@
+package di
+
+import (
+	"context"
+	"maps"
+)
+
+// FeatureFlagProvider reports whether a feature flag is enabled.
+// Implementations may consult the context, e.g. for per-user or per-tenant rollouts.
+type FeatureFlagProvider interface {
+	IsEnabled(ctx context.Context, flag string) bool
+}
+
+// staticFeatureFlags is a FeatureFlagProvider backed by a fixed map.
+type staticFeatureFlags struct {
+	flags map[string]bool
+}
+
+// NewStaticFeatureFlags returns a FeatureFlagProvider with fixed flag values, mainly
+// for tests and local development. Flags missing from the map are disabled.
+// The map is copied, so later changes to it have no effect.
+func NewStaticFeatureFlags(flags map[string]bool) FeatureFlagProvider {
+	return &staticFeatureFlags{flags: maps.Clone(flags)}
+}
+
+// IsEnabled reports the configured value of flag, ignoring the context.
+func (s *staticFeatureFlags) IsEnabled(_ context.Context, flag string) bool {
+	return s.flags[flag]
+}
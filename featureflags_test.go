@@ -0,0 +1,74 @@
+package di_test
+
+import (
+	"context"
+	"testing"
+
+	di "github.com/0xalexb/hjarta-di"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+// checkout is a test service gated by feature flags.
+type checkout struct {
+	flags di.FeatureFlagProvider
+}
+
+func (c *checkout) usesNewFlow(ctx context.Context) bool {
+	return c.flags.IsEnabled(ctx, "new-checkout")
+}
+
+func TestWithFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	module := fx.Module("checkout",
+		fx.Provide(func(flags di.FeatureFlagProvider) *checkout {
+			return &checkout{flags: flags}
+		}),
+	)
+
+	app := di.NewApp(
+		di.WithFeatureFlags(di.NewStaticFeatureFlags(map[string]bool{
+			"new-checkout": true,
+			"dark-mode":    false,
+		})),
+		di.WithModules(module),
+	)
+
+	var (
+		svc   *checkout
+		flags di.FeatureFlagProvider
+	)
+
+	require.NoError(t, app.Populate(&svc, &flags))
+
+	ctx := context.Background()
+
+	assert.True(t, svc.usesNewFlow(ctx))
+	assert.False(t, flags.IsEnabled(ctx, "dark-mode"))
+	assert.False(t, flags.IsEnabled(ctx, "unknown"), "unknown flags should be disabled")
+}
+
+func TestWithFeatureFlags_NilIgnored(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp(di.WithFeatureFlags(nil))
+
+	var flags di.FeatureFlagProvider
+
+	require.Error(t, app.Populate(&flags), "no provider should be registered")
+}
+
+func TestNewStaticFeatureFlags_CopiesMap(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]bool{"beta": true}
+	flags := di.NewStaticFeatureFlags(values)
+
+	values["beta"] = false
+
+	assert.True(t, flags.IsEnabled(context.Background(), "beta"))
+	assert.False(t, di.NewStaticFeatureFlags(nil).IsEnabled(context.Background(), "beta"))
+}
@@ -0,0 +1,44 @@
+package di
+
+import (
+	"log/slog"
+	"reflect"
+
+	"go.uber.org/fx/fxevent"
+)
+
+// fxEventLogger logs Fx events through slog, using a custom level for the
+// event types listed in levels and the fxevent.SlogLogger default otherwise.
+type fxEventLogger struct {
+	fallback *fxevent.SlogLogger
+	byType   map[reflect.Type]*fxevent.SlogLogger
+}
+
+// newFxEventLogger creates an fxevent.Logger writing to logger with the given
+// per-event-type levels.
+func newFxEventLogger(logger *slog.Logger, levels map[reflect.Type]slog.Level) fxevent.Logger {
+	eventLogger := &fxEventLogger{
+		fallback: &fxevent.SlogLogger{Logger: logger},
+		byType:   make(map[reflect.Type]*fxevent.SlogLogger, len(levels)),
+	}
+
+	for eventType, level := range levels {
+		typed := &fxevent.SlogLogger{Logger: logger}
+		typed.UseLogLevel(level)
+
+		eventLogger.byType[eventType] = typed
+	}
+
+	return eventLogger
+}
+
+// LogEvent implements fxevent.Logger.
+func (l *fxEventLogger) LogEvent(event fxevent.Event) {
+	if typed, ok := l.byType[reflect.TypeOf(event)]; ok {
+		typed.LogEvent(event)
+
+		return
+	}
+
+	l.fallback.LogEvent(event)
+}
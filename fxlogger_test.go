@@ -0,0 +1,86 @@
+package di
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+)
+
+type suppliedConfig struct {
+	Name string
+}
+
+// supplyLogOutput builds an app supplying suppliedConfig with the given options
+// and returns what was logged at info level.
+func supplyLogOutput(t *testing.T, opts ...Option) string {
+	t.Helper()
+
+	var options Options
+
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	var buf bytes.Buffer
+
+	app := configure(&options, createLogger("info", &buf), fx.Supply(suppliedConfig{Name: "db"}))
+	require.NoError(t, app.Err())
+
+	return buf.String()
+}
+
+func TestFxEventLogger_SupplyLoggedByDefault(t *testing.T) {
+	t.Parallel()
+
+	out := supplyLogOutput(t)
+
+	assert.Contains(t, out, `"msg":"supplied"`)
+	assert.Contains(t, out, "suppliedConfig")
+}
+
+func TestFxEventLogger_WithSilentSupply(t *testing.T) {
+	t.Parallel()
+
+	out := supplyLogOutput(t, WithSilentSupply())
+
+	assert.NotContains(t, out, `"msg":"supplied"`)
+	assert.Contains(t, out, `"msg":"provided"`, "other Fx events keep the default level")
+}
+
+func TestFxEventLogger_WithFxSupplyLogLevel(t *testing.T) {
+	t.Parallel()
+
+	out := supplyLogOutput(t, WithFxSupplyLogLevel(&fxevent.Supplied{}, slog.LevelWarn))
+
+	assert.Contains(t, out, `"level":"WARN","msg":"supplied"`)
+}
+
+func TestFxEventLogger_SilentSupplyVisibleAtDebug(t *testing.T) {
+	t.Parallel()
+
+	var options Options
+
+	WithSilentSupply()(&options)
+
+	var buf bytes.Buffer
+
+	app := configure(&options, createLogger("debug", &buf), fx.Supply(suppliedConfig{}))
+	require.NoError(t, app.Err())
+
+	assert.Contains(t, buf.String(), `"level":"DEBUG","msg":"supplied"`)
+}
+
+func TestWithFxSupplyLogLevel_NilEvent(t *testing.T) {
+	t.Parallel()
+
+	var options Options
+
+	WithFxSupplyLogLevel(nil, slog.LevelDebug)(&options)
+
+	assert.Empty(t, options.FxEventLogLevels)
+}
@@ -1,11 +1,33 @@
 // Package listener provides an HTTP listener module for the Fx DI container.
+//
+// Migration note: Option used to be func(*Config). It is now func(*options), so that
+// settings such as the logger, routes and middleware, which are not part of Config, can be
+// options too. This breaks custom options written as func(*Config) literals; wrap them
+// with ConfigOption:
+//
+//	// before
+//	listener.Option(func(cfg *listener.Config) { cfg.Address = ":9090" })
+//	// after
+//	listener.ConfigOption(func(cfg *listener.Config) { cfg.Address = ":9090" })
+//
+// The With* constructors are unchanged.
 package listener
 
-import "errors"
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
 
 // DefaultAddress is the default address for the HTTP listener.
 const DefaultAddress = ":8080"
 
+// DefaultMinTLSVersion is the minimum TLS version used when Config.MinTLSVersion is empty.
+const DefaultMinTLSVersion = "1.2"
+
 // ErrEmptyAddress is returned when the address is empty.
 var ErrEmptyAddress = errors.New("address must not be empty")
 
@@ -21,9 +43,67 @@ var ErrEmptyName = errors.New("listener name must not be empty")
 // ErrNilHandler is returned when a nil http.Handler is provided.
 var ErrNilHandler = errors.New("handler must not be nil")
 
+// ErrModuleOnlyOption is returned by NewServer when given an option that only NewModule applies.
+var ErrModuleOnlyOption = errors.New("option is only supported by NewModule")
+
+// ErrServePanic is passed to the WithServeErrorHandler callback when the Serve goroutine panics.
+var ErrServePanic = errors.New("serve goroutine panicked")
+
+// ErrReadTimeoutShorterThanHeader is returned when ReadTimeout is set but shorter than ReadHeaderTimeout.
+var ErrReadTimeoutShorterThanHeader = errors.New("read timeout must not be shorter than read header timeout")
+
+// ErrUnknownTLSVersion is returned when MinTLSVersion is not a supported TLS version.
+var ErrUnknownTLSVersion = errors.New("unknown TLS version")
+
+// ErrUnknownCipherSuite is returned when CipherSuites names a suite that is unknown,
+// insecure, or a TLS 1.3 suite, which cannot be configured.
+var ErrUnknownCipherSuite = errors.New("unknown or insecure cipher suite")
+
+// ErrMissingHTTP2CipherSuite is returned when CipherSuites lacks the AES-128-GCM suite
+// HTTP/2 requires.
+var ErrMissingHTTP2CipherSuite = errors.New("cipher suites must include TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 " +
+	"or TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 for HTTP/2")
+
+// ErrIncompleteTLSKeyPair is returned when only one of TLSCertFile and TLSKeyFile is set.
+var ErrIncompleteTLSKeyPair = errors.New("TLS certificate and key files must be set together")
+
+// ErrLoadTLSKeyPair is returned when the TLSCertFile and TLSKeyFile pair cannot be loaded.
+var ErrLoadTLSKeyPair = errors.New("failed to load TLS key pair")
+
 // Config holds the configuration for an HTTP listener.
+//
+// The timeouts map to the http.Server fields of the same name; zero means no timeout,
+// except for ReadHeaderTimeout, which defaults to 10s, or to ReadTimeout when that is
+// set and shorter. ReadTimeout covers reading the entire request including its headers,
+// so when both are set the headers must arrive within the shorter of the two: an
+// explicit ReadTimeout below ReadHeaderTimeout would silently cap the header timeout
+// and is rejected by Validate. WriteTimeout runs from the end
+// of reading the request headers to the end of writing the response. IdleTimeout bounds
+// the wait for the next request on a keep-alive connection; when zero, ReadTimeout is used.
+//
+// TLSCertFile and TLSKeyFile name PEM files holding the certificate chain and private
+// key; when set, the listener serves HTTPS instead of plain HTTP. MinTLSVersion ("1.2"
+// or "1.3", optionally prefixed with "TLS") and CipherSuites (IANA names as listed by
+// tls.CipherSuites) restrict the TLS settings returned by TLSConfig, and so only take
+// effect when serving HTTPS; TLS 1.2 is the minimum by default. CipherSuites only affect
+// TLS 1.2 connections, since TLS 1.3 suites are not configurable and are rejected; they
+// must include an AES-128-GCM ECDHE suite, which HTTP/2 requires. When empty, Go's
+// default suites are used.
+//
+// PreShutdownDelay makes Server.Stop keep serving for that long before shutting down,
+// giving a load balancer time to notice the instance is going away and stop routing
+// to it. Zero, the default, shuts down immediately.
 type Config struct {
-	Address string
+	Address           string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	TLSCertFile       string
+	TLSKeyFile        string
+	MinTLSVersion     string
+	CipherSuites      []string
+	PreShutdownDelay  time.Duration
 }
 
 // SetDefaults sets default values for the Config.
@@ -31,6 +111,17 @@ func (c *Config) SetDefaults() {
 	if c.Address == "" {
 		c.Address = DefaultAddress
 	}
+
+	if c.ReadHeaderTimeout == 0 {
+		c.ReadHeaderTimeout = ReadHeaderTimeout
+		if c.ReadTimeout > 0 && c.ReadTimeout < ReadHeaderTimeout {
+			c.ReadHeaderTimeout = c.ReadTimeout
+		}
+	}
+
+	if c.MinTLSVersion == "" {
+		c.MinTLSVersion = DefaultMinTLSVersion
+	}
 }
 
 // Validate validates the Config.
@@ -39,5 +130,113 @@ func (c *Config) Validate() error {
 		return ErrEmptyAddress
 	}
 
-	return nil
+	if c.ReadTimeout > 0 && c.ReadTimeout < c.ReadHeaderTimeout {
+		return ErrReadTimeoutShorterThanHeader
+	}
+
+	_, err := c.TLSConfig()
+
+	return err
+}
+
+// TLSEnabled reports whether the Config sets a certificate or key file, so the
+// listener serves HTTPS.
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" || c.TLSKeyFile != ""
+}
+
+// TLSConfig returns a tls.Config with MinVersion and CipherSuites set from the Config,
+// and Certificates loaded from TLSCertFile and TLSKeyFile when they are set.
+// It returns ErrUnknownTLSVersion, ErrUnknownCipherSuite or ErrMissingHTTP2CipherSuite
+// for invalid values, and ErrIncompleteTLSKeyPair or ErrLoadTLSKeyPair for an unusable
+// key pair.
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	minVersion, err := parseTLSVersion(c.MinTLSVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	suites, err := parseCipherSuites(c.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: suites,
+	}
+
+	if !c.TLSEnabled() {
+		return tlsConfig, nil
+	}
+
+	if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return nil, ErrIncompleteTLSKeyPair
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadTLSKeyPair, err)
+	}
+
+	tlsConfig.Certificates = []tls.Certificate{cert}
+
+	return tlsConfig, nil
+}
+
+// parseTLSVersion converts "1.2" or "1.3" (optionally prefixed with "TLS") to its
+// tls constant; an empty version yields DefaultMinTLSVersion.
+func parseTLSVersion(version string) (uint16, error) {
+	normalized := strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(version), "TLS"))
+
+	switch normalized {
+	case "1.2", "":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownTLSVersion, version)
+	}
+}
+
+// parseCipherSuites converts IANA cipher suite names to their IDs, rejecting names
+// missing from tls.CipherSuites, including the insecure ones, and TLS 1.3 suites.
+// It returns nil for no names.
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	tls13 := make(map[string]bool)
+
+	for _, suite := range tls.CipherSuites() {
+		if slices.Contains(suite.SupportedVersions, tls.VersionTLS12) {
+			known[suite.Name] = suite.ID
+		} else {
+			tls13[suite.Name] = true
+		}
+	}
+
+	ids := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		if tls13[name] {
+			return nil, fmt.Errorf("%w: %q is a TLS 1.3 suite, which is not configurable", ErrUnknownCipherSuite, name)
+		}
+
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCipherSuite, name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if !slices.Contains(ids, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256) &&
+		!slices.Contains(ids, tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256) {
+		return nil, ErrMissingHTTP2CipherSuite
+	}
+
+	return ids, nil
 }
@@ -1,7 +1,9 @@
 package listener
 
 import (
+	"crypto/tls"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,6 +29,43 @@ func TestConfig_SetDefaults(t *testing.T) {
 
 		assert.Equal(t, ":9090", cfg.Address)
 	})
+
+	t.Run("sets default read header timeout when zero", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{}
+		cfg.SetDefaults()
+
+		assert.Equal(t, ReadHeaderTimeout, cfg.ReadHeaderTimeout)
+	})
+
+	t.Run("caps default read header timeout at a shorter read timeout", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{Address: ":8080", ReadTimeout: 5 * time.Second}
+		cfg.SetDefaults()
+
+		assert.Equal(t, 5*time.Second, cfg.ReadHeaderTimeout)
+		require.NoError(t, cfg.Validate(), "a ReadTimeout-only config should be valid")
+	})
+
+	t.Run("does not override existing read header timeout", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{ReadHeaderTimeout: 2 * time.Second}
+		cfg.SetDefaults()
+
+		assert.Equal(t, 2*time.Second, cfg.ReadHeaderTimeout)
+	})
+
+	t.Run("sets default minimum TLS version when empty", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{}
+		cfg.SetDefaults()
+
+		assert.Equal(t, DefaultMinTLSVersion, cfg.MinTLSVersion)
+	})
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -50,4 +89,123 @@ func TestConfig_Validate(t *testing.T) {
 		require.Error(t, err)
 		assert.ErrorIs(t, err, ErrEmptyAddress)
 	})
+
+	t.Run("read timeout shorter than read header timeout", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{Address: ":8080", ReadHeaderTimeout: 10 * time.Second, ReadTimeout: 5 * time.Second}
+		err := cfg.Validate()
+
+		require.ErrorIs(t, err, ErrReadTimeoutShorterThanHeader)
+	})
+
+	t.Run("read timeout equal to read header timeout", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{Address: ":8080", ReadHeaderTimeout: 5 * time.Second, ReadTimeout: 5 * time.Second}
+
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("zero read timeout disables the check", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{Address: ":8080", ReadHeaderTimeout: 10 * time.Second}
+
+		require.NoError(t, cfg.Validate())
+	})
+}
+
+func TestConfig_TLSConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		version    string
+		minVersion uint16
+	}{
+		{name: "default", version: "", minVersion: tls.VersionTLS12},
+		{name: "1.2", version: "1.2", minVersion: tls.VersionTLS12},
+		{name: "1.3", version: "1.3", minVersion: tls.VersionTLS13},
+		{name: "TLS prefix", version: "TLS1.3", minVersion: tls.VersionTLS13},
+		{name: "lowercase prefix with space", version: "tls 1.2", minVersion: tls.VersionTLS12},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{MinTLSVersion: testCase.version}
+
+			tlsCfg, err := cfg.TLSConfig()
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.minVersion, tlsCfg.MinVersion)
+			assert.Nil(t, tlsCfg.CipherSuites, "no suites configured should keep Go's defaults")
+		})
+	}
+
+	t.Run("cipher suites", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := &Config{CipherSuites: []string{
+			"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+			"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		}}
+
+		tlsCfg, err := cfg.TLSConfig()
+		require.NoError(t, err)
+
+		assert.Equal(t, []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		}, tlsCfg.CipherSuites)
+	})
+}
+
+func TestConfig_ValidateTLS(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		cfg     Config
+		wantErr error
+	}{
+		{name: "unknown version", cfg: Config{MinTLSVersion: "2.0"}, wantErr: ErrUnknownTLSVersion},
+		{name: "TLS 1.1 is rejected", cfg: Config{MinTLSVersion: "1.1"}, wantErr: ErrUnknownTLSVersion},
+		{name: "unknown suite", cfg: Config{CipherSuites: []string{"TLS_FAKE"}}, wantErr: ErrUnknownCipherSuite},
+		{
+			name:    "insecure suite",
+			cfg:     Config{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			wantErr: ErrUnknownCipherSuite,
+		},
+		{
+			name:    "TLS 1.3 suite",
+			cfg:     Config{CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}},
+			wantErr: ErrUnknownCipherSuite,
+		},
+		{
+			name:    "no HTTP/2 suite",
+			cfg:     Config{CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"}},
+			wantErr: ErrMissingHTTP2CipherSuite,
+		},
+		{name: "cert without key", cfg: Config{TLSCertFile: "cert.pem"}, wantErr: ErrIncompleteTLSKeyPair},
+		{name: "key without cert", cfg: Config{TLSKeyFile: "key.pem"}, wantErr: ErrIncompleteTLSKeyPair},
+		{
+			name:    "missing key pair",
+			cfg:     Config{TLSCertFile: "missing-cert.pem", TLSKeyFile: "missing-key.pem"},
+			wantErr: ErrLoadTLSKeyPair,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := testCase.cfg
+			cfg.Address = ":8080"
+
+			require.ErrorIs(t, cfg.Validate(), testCase.wantErr)
+		})
+	}
 }
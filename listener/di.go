@@ -10,8 +10,16 @@ import (
 
 // NewModule creates an Fx module for a named HTTP listener.
 // The name is used as both the module name and the DI named tag for http.Handler and Config.
-// If any options are passed, the module supplies Config to DI from those options.
+// If any config options (e.g., WithAddress) are passed, the module supplies Config to DI from those options.
 // Otherwise, Config must be provided externally (e.g., via config.Provider).
+// The named http.Handler is provided from the WithHandlerProvider constructor if one is set,
+// otherwise it must be supplied externally.
+// The named http.Handler is optional when routes are declared via WithRoutes; it then serves
+// as the fallback for unmatched requests. The handler is always wrapped with
+// middleware.InjectListenerName (see NewServer), so handlers can read the serving
+// listener's name via middleware.GetListenerName.
+// Listener logs go to the logger set via WithLogger, otherwise to the *slog.Logger
+// from the DI container if one is provided, otherwise to slog.Default().
 //
 //nolint:ireturn // fx.Option is the standard return type for Fx modules
 func NewModule(name string, opts ...Option) fx.Option {
@@ -19,31 +27,59 @@ func NewModule(name string, opts ...Option) fx.Option {
 		return fx.Error(ErrEmptyName)
 	}
 
-	var cfg Config
-
-	for _, apply := range opts {
-		apply(&cfg)
-	}
-
-	hasConfigFromOptions := len(opts) > 0
+	o := newOptions(opts...)
 
 	var moduleOpts []fx.Option
 
-	if hasConfigFromOptions {
+	if o.hasConfig {
 		moduleOpts = append(moduleOpts, fx.Supply(
-			fx.Annotate(cfg, fx.ResultTags(fmt.Sprintf(`name:"%s"`, name))),
+			fx.Annotate(o.config, fx.ResultTags(fmt.Sprintf(`name:"%s"`, name))),
+		))
+	}
+
+	if o.handlerProvider != nil {
+		moduleOpts = append(moduleOpts, fx.Provide(
+			fx.Annotate(o.handlerProvider,
+				fx.As(new(http.Handler)),
+				fx.ResultTags(fmt.Sprintf(`name:"%s"`, name)),
+			),
 		))
 	}
 
 	moduleOpts = append(moduleOpts, fx.Invoke(
 		fx.Annotate(
-			func(lifecycle fx.Lifecycle, shutdowner fx.Shutdowner, handler http.Handler, listenerCfg Config) error {
-				srv, err := NewServer(name, handler, listenerCfg, func() {
+			func(
+				lifecycle fx.Lifecycle,
+				shutdowner fx.Shutdowner,
+				handler http.Handler,
+				listenerCfg Config,
+				diLogger *slog.Logger,
+			) error {
+				logger := o.logger
+				if logger == nil {
+					logger = diLogger
+				}
+
+				if logger == nil {
+					logger = slog.Default()
+				}
+
+				onServeErr := func(serveErr error) {
+					if o.serveErrors != nil {
+						o.serveErrors(serveErr)
+					}
+
 					shutdownErr := shutdowner.Shutdown()
 					if shutdownErr != nil {
-						slog.Error("failed to trigger shutdown", "name", name, "error", shutdownErr)
+						logger.Error("failed to trigger shutdown", "name", name, "error", shutdownErr)
 					}
-				})
+				}
+
+				serverOpts := o
+				serverOpts.logger = logger
+				serverOpts.serveErrors = onServeErr
+
+				srv, err := newServer(name, handler, listenerCfg, nil, serverOpts)
 				if err != nil {
 					return err
 				}
@@ -55,7 +91,12 @@ func NewModule(name string, opts ...Option) fx.Option {
 
 				return nil
 			},
-			fx.ParamTags("", "", fmt.Sprintf(`name:"%s"`, name), fmt.Sprintf(`name:"%s"`, name)),
+			fx.ParamTags(
+				"", "",
+				fmt.Sprintf(`name:"%s" optional:"true"`, name),
+				fmt.Sprintf(`name:"%s"`, name),
+				`optional:"true"`,
+			),
 		),
 	))
 
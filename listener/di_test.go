@@ -1,14 +1,18 @@
 package listener
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/0xalexb/hjarta-di/listener/middleware"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/fx"
@@ -47,6 +51,37 @@ func TestNewModule_WithOptions(t *testing.T) {
 	app.RequireStop()
 }
 
+func TestNewModule_ConfigOption(t *testing.T) {
+	t.Parallel()
+
+	addr := freePort(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(handler, fx.As(new(http.Handler)), fx.ResultTags(`name:"api"`))),
+		NewModule("api", ConfigOption(func(cfg *Config) {
+			cfg.Address = addr
+			cfg.IdleTimeout = time.Minute
+		})),
+	)
+
+	app.RequireStart()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+	require.NoError(t, err)
+
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	app.RequireStop()
+}
+
 func TestNewModule_WithExternalConfig(t *testing.T) {
 	t.Parallel()
 
@@ -203,3 +238,191 @@ func TestNewModule_EmptyName(t *testing.T) {
 	require.Error(t, err, "should fail with empty name")
 	assert.ErrorIs(t, err, ErrEmptyName)
 }
+
+func TestNewModule_InjectsListenerName(t *testing.T) {
+	t.Parallel()
+
+	addr1 := freePort(t)
+	addr2 := freePort(t)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, middleware.GetListenerName(r.Context()))
+	})
+
+	app := fxtest.New(t,
+		fx.Supply(
+			fx.Annotate(handler, fx.As(new(http.Handler)), fx.ResultTags(`name:"api"`)),
+			fx.Annotate(handler, fx.As(new(http.Handler)), fx.ResultTags(`name:"admin"`)),
+		),
+		NewModule("api", WithAddress(addr1)),
+		NewModule("admin", WithAddress(addr2)),
+	)
+
+	app.RequireStart()
+
+	for addr, want := range map[string]string{addr1: "api", addr2: "admin"} {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+		require.NoError(t, err)
+
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, want, string(body))
+	}
+
+	app.RequireStop()
+}
+
+func TestNewModule_UsesDILogger(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(handler, fx.As(new(http.Handler)), fx.ResultTags(`name:"api"`))),
+		fx.Supply(logger),
+		NewModule("api", WithAddress(freePort(t))),
+	)
+
+	app.RequireStart()
+	app.RequireStop()
+
+	assert.Contains(t, logs.String(), "starting HTTP listener")
+}
+
+func TestNewModule_WithLoggerOverridesDILogger(t *testing.T) {
+	t.Parallel()
+
+	var diLogs, optLogs bytes.Buffer
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(handler, fx.As(new(http.Handler)), fx.ResultTags(`name:"api"`))),
+		fx.Supply(slog.New(slog.NewJSONHandler(&diLogs, nil))),
+		NewModule("api",
+			WithAddress(freePort(t)),
+			WithLogger(slog.New(slog.NewJSONHandler(&optLogs, nil))),
+		),
+	)
+
+	app.RequireStart()
+	app.RequireStop()
+
+	assert.Contains(t, optLogs.String(), "starting HTTP listener")
+	assert.NotContains(t, diLogs.String(), "starting HTTP listener")
+}
+
+func TestNewModule_WithLoggerOnlyUsesExternalConfig(t *testing.T) {
+	t.Parallel()
+
+	addr := freePort(t)
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	app := fxtest.New(t,
+		fx.Supply(
+			fx.Annotate(handler, fx.As(new(http.Handler)), fx.ResultTags(`name:"ext"`)),
+			fx.Annotate(Config{Address: addr}, fx.ResultTags(`name:"ext"`)),
+		),
+		NewModule("ext", WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil)))),
+	)
+
+	app.RequireStart()
+	app.RequireStop()
+}
+
+func TestNewModule_MissingHandler(t *testing.T) {
+	t.Parallel()
+
+	app := fx.New(
+		NewModule("api", WithAddress(freePort(t))),
+		fx.NopLogger,
+	)
+
+	err := app.Err()
+	require.ErrorIs(t, err, ErrNilHandler)
+}
+
+type greeter struct {
+	greeting string
+}
+
+type greetingHandler struct {
+	greeter *greeter
+}
+
+func (h *greetingHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	_, _ = fmt.Fprint(w, h.greeter.greeting)
+}
+
+func TestNewModule_WithHandlerProvider(t *testing.T) {
+	t.Parallel()
+
+	addr := freePort(t)
+	calls := 0
+
+	app := fxtest.New(t,
+		fx.Supply(&greeter{greeting: "hello from DI"}),
+		NewModule("api", WithAddress(addr), WithHandlerProvider(func(g *greeter) *greetingHandler {
+			calls++
+
+			return &greetingHandler{greeter: g}
+		})),
+	)
+
+	app.RequireStart()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "hello from DI", string(body))
+	assert.Equal(t, 1, calls)
+
+	app.RequireStop()
+}
+
+func TestNewModule_WithHandlerProviderInterfaceResult(t *testing.T) {
+	t.Parallel()
+
+	var got http.Handler
+
+	handler := http.NotFoundHandler()
+
+	app := fxtest.New(t,
+		NewModule("api", WithAddress(freePort(t)), WithHandlerProvider(func() (http.Handler, error) {
+			return handler, nil
+		})),
+		fx.Populate(fx.Annotate(&got, fx.ParamTags(`name:"api"`))),
+	)
+
+	app.RequireStart()
+	assert.NotNil(t, got)
+	app.RequireStop()
+}
+
+func TestNewModule_WithHandlerProviderError(t *testing.T) {
+	t.Parallel()
+
+	errBuild := errors.New("build failed")
+
+	app := fx.New(
+		NewModule("api", WithAddress(freePort(t)), WithHandlerProvider(func() (http.Handler, error) {
+			return nil, errBuild
+		})),
+		fx.NopLogger,
+	)
+
+	require.ErrorIs(t, app.Err(), errBuild)
+}
@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AccessLogFormatCommon is the Common Log Format used by Apache and Nginx:
+	// host ident authuser [date] "request line" status bytes.
+	AccessLogFormatCommon = "common"
+
+	// AccessLogFormatW3C is the W3C Extended Log Format with the fields
+	// date time c-ip cs-method cs-uri-stem cs-uri-query sc-status sc-bytes time-taken.
+	// The #Version and #Fields directives are written before the first entry.
+	AccessLogFormatW3C = "w3c"
+)
+
+// clfTimeLayout is the timestamp layout of the Common Log Format.
+const clfTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// w3cHeader holds the directives opening a W3C Extended Log Format log.
+const w3cHeader = "#Version: 1.0\n" +
+	"#Fields: date time c-ip cs-method cs-uri-stem cs-uri-query sc-status sc-bytes time-taken\n"
+
+// accessLogConfig holds configuration for the AccessLog middleware.
+type accessLogConfig struct {
+	format string
+}
+
+// AccessLogOption configures the AccessLog middleware.
+type AccessLogOption func(*accessLogConfig)
+
+// WithAccessLogFormat sets the access log format: AccessLogFormatCommon (default)
+// or AccessLogFormatW3C. Unknown formats fall back to the default with a warning log.
+func WithAccessLogFormat(format string) AccessLogOption {
+	return func(c *accessLogConfig) {
+		c.format = format
+	}
+}
+
+// AccessLog returns a middleware that writes one access log line per request to w,
+// independently of the slog output of the Logging middleware. By default lines use
+// the Common Log Format (%h %l %u %t "%r" %>s %b), e.g.:
+//
+//	192.0.2.1 - alice [10/Oct/2026:13:55:36 +0000] "GET /index.html HTTP/1.1" 200 2326
+//
+// The user is taken from HTTP basic auth; unknown fields and empty bodies are written as "-".
+// In the user and request line, quotes and backslashes are backslash-escaped and control
+// characters written as \xhh, as Apache does.
+// Writes to w are serialized, so w does not need to be safe for concurrent use.
+// Write errors are logged via slog and do not affect the response.
+//
+// Options:
+//   - WithAccessLogFormat(format) - use AccessLogFormatW3C instead of the Common Log Format
+func AccessLog(w io.Writer, opts ...AccessLogOption) func(http.Handler) http.Handler {
+	cfg := accessLogConfig{format: AccessLogFormatCommon}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	if cfg.format != AccessLogFormatCommon && cfg.format != AccessLogFormatW3C {
+		slog.Warn("middleware: unknown access log format, using default",
+			"provided", cfg.format, "default", AccessLogFormatCommon)
+
+		cfg.format = AccessLogFormatCommon
+	}
+
+	var (
+		mu            sync.Mutex
+		headerWritten bool
+	)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			sw := &statusWriter{ResponseWriter: rw}
+
+			next.ServeHTTP(sw, r)
+
+			sw.resolveStatus()
+
+			var line string
+			if cfg.format == AccessLogFormatW3C {
+				line = w3cLine(r, sw, start, time.Since(start))
+			} else {
+				line = clfLine(r, sw, start)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if cfg.format == AccessLogFormatW3C && !headerWritten {
+				line = w3cHeader + line
+				headerWritten = true
+			}
+
+			_, err := io.WriteString(w, line)
+			if err != nil {
+				slog.Error("middleware: failed to write access log", "error", err)
+			}
+		})
+	}
+}
+
+// clfLine formats a Common Log Format line for the request.
+func clfLine(r *http.Request, sw *statusWriter, start time.Time) string {
+	user := "-"
+	if name, _, ok := r.BasicAuth(); ok && name != "" {
+		user = escapeLogItem(strings.ReplaceAll(name, " ", "_"))
+	}
+
+	size := "-"
+	if sw.bytes > 0 {
+		size = strconv.FormatInt(sw.bytes, 10)
+	}
+
+	requestLine := escapeLogItem(r.Method + " " + r.RequestURI + " " + r.Proto)
+
+	return clientHost(r) + " - " + user + " [" + start.Format(clfTimeLayout) + `] "` +
+		requestLine + `" ` + strconv.Itoa(sw.status) + " " + size + "\n"
+}
+
+// escapeLogItem escapes s for a CLF field the way Apache does: quotes and backslashes
+// are prefixed with a backslash and control characters are written as \xhh, so a
+// client cannot forge or split log lines.
+func escapeLogItem(s string) string {
+	var b strings.Builder
+
+	for i := range len(s) {
+		c := s[i]
+
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			const hexDigits = "0123456789abcdef"
+
+			b.WriteString(`\x`)
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&0x0f])
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// w3cLine formats a W3C Extended Log Format line for the request.
+func w3cLine(r *http.Request, sw *statusWriter, start time.Time, taken time.Duration) string {
+	query := "-"
+	if r.URL.RawQuery != "" {
+		query = r.URL.RawQuery
+	}
+
+	utc := start.UTC()
+
+	return utc.Format(time.DateOnly) + " " + utc.Format(time.TimeOnly) + " " + clientHost(r) + " " +
+		r.Method + " " + r.URL.EscapedPath() + " " + query + " " + strconv.Itoa(sw.status) + " " +
+		strconv.FormatInt(sw.bytes, 10) + " " + strconv.FormatFloat(taken.Seconds(), 'f', 3, 64) + "\n"
+}
+
+// clientHost returns the host part of the request's remote address.
+func clientHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil || host == "" {
+		if r.RemoteAddr == "" {
+			return "-"
+		}
+
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clfPattern matches a Common Log Format line whose request line follows
+// RFC 1945: Method SP Request-URI SP HTTP-Version.
+var clfPattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`^(\S+) - (\S+) \[(\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4})\] ` +
+		`"([A-Z]+) (\S+) (HTTP/\d\.\d)" (\d{3}) (\d+|-)$`)
+
+func TestAccessLog_CommonLogFormat(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handler := AccessLog(&buf)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/items?id=1", nil)
+	req.RemoteAddr = "192.0.2.1:54321"
+	req.SetBasicAuth("alice", "secret")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	require.NotContains(t, line, "\n", "expected exactly one line")
+
+	match := clfPattern.FindStringSubmatch(line)
+	require.NotNil(t, match, "line does not match CLF: %q", line)
+
+	assert.Equal(t, "192.0.2.1", match[1])
+	assert.Equal(t, "alice", match[2])
+	assert.Equal(t, "POST", match[4])
+	assert.Equal(t, "/items?id=1", match[5])
+	assert.Equal(t, "HTTP/1.1", match[6])
+	assert.Equal(t, "201", match[7])
+	assert.Equal(t, "5", match[8])
+}
+
+func TestAccessLog_CommonLogFormatDefaults(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handler := AccessLog(&buf)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "[2001:db8::1]:8080"
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	match := clfPattern.FindStringSubmatch(strings.TrimSuffix(buf.String(), "\n"))
+	require.NotNil(t, match, "line does not match CLF: %q", buf.String())
+
+	assert.Equal(t, "2001:db8::1", match[1])
+	assert.Equal(t, "-", match[2], "missing user should be written as -")
+	assert.Equal(t, "200", match[7], "status should default to 200")
+	assert.Equal(t, "-", match[8], "empty body should be written as -")
+}
+
+func TestAccessLog_CommonLogFormatEscapesUser(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handler := AccessLog(&buf)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:54321"
+	req.SetBasicAuth("eve\n10.0.0.1 - admin \"x\\y\x7f", "secret")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	require.NotContains(t, line, "\n", "expected exactly one line")
+
+	match := clfPattern.FindStringSubmatch(line)
+	require.NotNil(t, match, "line does not match CLF: %q", line)
+
+	assert.Equal(t, `eve\x0a10.0.0.1_-_admin_\"x\\y\x7f`, match[2])
+}
+
+func TestAccessLog_W3CFormat(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handler := AccessLog(&buf, WithAccessLogFormat(AccessLogFormatW3C))(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.NotFound(w, nil)
+		}))
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "/missing?q=1", nil)
+		req.RemoteAddr = "198.51.100.7:1234"
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 4, "expected directives once followed by one entry per request")
+
+	assert.Equal(t, "#Version: 1.0", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "#Fields: "))
+
+	entry := regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2} 198\.51\.100\.7 GET /missing q=1 404 19 \d+\.\d{3}$`)
+	assert.Regexp(t, entry, lines[2])
+	assert.Regexp(t, entry, lines[3])
+}
+
+func TestAccessLog_UnknownFormatFallsBack(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	var buf bytes.Buffer
+
+	handler := AccessLog(&buf, WithAccessLogFormat("combined"))(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, "combined", h.records[0].Attrs["provided"])
+	assert.Regexp(t, clfPattern, strings.TrimSuffix(buf.String(), "\n"))
+}
+
+func TestAccessLog_ConcurrentLinesNotInterleaved(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handler := AccessLog(&buf)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Go(func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	}
+
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 50)
+
+	for _, line := range lines {
+		assert.Regexp(t, clfPattern, line)
+	}
+}
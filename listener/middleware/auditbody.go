@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+const defaultAuditMaxSize int64 = 64 << 10 // 64KB
+
+// auditBodyConfig holds configuration for the AuditBody middleware.
+type auditBodyConfig struct {
+	paths   []string
+	methods []string
+	maxSize int64
+}
+
+// AuditBodyOption configures the AuditBody middleware.
+type AuditBodyOption func(*auditBodyConfig)
+
+// WithAuditPaths restricts auditing to requests whose URL path is exactly one of paths.
+// By default requests to every path are audited.
+func WithAuditPaths(paths ...string) AuditBodyOption {
+	return func(c *auditBodyConfig) {
+		c.paths = append(c.paths, paths...)
+	}
+}
+
+// WithAuditMethods replaces the audited request methods (default POST, PUT and PATCH).
+func WithAuditMethods(methods ...string) AuditBodyOption {
+	return func(c *auditBodyConfig) {
+		c.methods = methods
+	}
+}
+
+// WithAuditMaxSize sets the maximum number of body bytes passed to the sink
+// (default 64KB). Longer bodies are truncated for the sink only.
+// If n is not positive, it defaults to 64KB with a warning log.
+func WithAuditMaxSize(n int64) AuditBodyOption {
+	return func(c *auditBodyConfig) {
+		c.maxSize = n
+	}
+}
+
+// AuditBody returns a middleware that passes a copy of the request body to sink, e.g.
+// to persist it for compliance, before calling the next handler. Up to the
+// WithAuditMaxSize limit is read ahead of the handler and then replayed in front of the
+// rest of the body, so the handler still reads the complete, unmodified body and the
+// original body is read only once. sink receives the request context, the URL path and
+// the captured bytes, which are truncated when the body is longer than the limit, or
+// short if reading the body failed. It runs synchronously before the handler, so the
+// body is audited even if the handler fails. sink must not modify body, which is also
+// replayed to the handler.
+//
+// Only requests matching WithAuditMethods (default POST, PUT and PATCH) and, if set,
+// WithAuditPaths are audited; others, and requests without a body, pass through.
+// If sink is nil, the middleware passes every request through with a warning log.
+func AuditBody(sink func(ctx context.Context, path string, body []byte), opts ...AuditBodyOption) func(http.Handler) http.Handler {
+	cfg := auditBodyConfig{
+		methods: []string{http.MethodPost, http.MethodPut, http.MethodPatch},
+		maxSize: defaultAuditMaxSize,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	if cfg.maxSize <= 0 {
+		slog.Warn("middleware: maxSize must be positive, using default",
+			"provided", cfg.maxSize, "default", defaultAuditMaxSize)
+
+		cfg.maxSize = defaultAuditMaxSize
+	}
+
+	if sink == nil {
+		slog.Warn("middleware: audit sink is nil, request bodies are not audited")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if sink == nil || r.Body == nil || r.Body == http.NoBody || !cfg.audits(r) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			captured, _ := io.ReadAll(io.LimitReader(r.Body, cfg.maxSize))
+
+			sink(r.Context(), r.URL.Path, captured)
+
+			r.Body = replayBody{
+				Reader: io.MultiReader(bytes.NewReader(captured), r.Body),
+				Closer: r.Body,
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// audits reports whether r matches the configured methods and paths.
+func (c *auditBodyConfig) audits(r *http.Request) bool {
+	if !slices.Contains(c.methods, r.Method) {
+		return false
+	}
+
+	return len(c.paths) == 0 || slices.Contains(c.paths, r.URL.Path)
+}
+
+// replayBody serves the bytes already read from a request body followed by the rest of
+// it, closing the original body.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
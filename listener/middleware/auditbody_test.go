@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditRecord is one call of a recording audit sink.
+type auditRecord struct {
+	path string
+	body string
+}
+
+// recordingSink returns a sink appending its calls to records.
+func recordingSink(records *[]auditRecord) func(context.Context, string, []byte) {
+	return func(_ context.Context, path string, body []byte) {
+		*records = append(*records, auditRecord{path: path, body: string(body)})
+	}
+}
+
+// echoBodyHandler responds with the request body it read.
+func echoBodyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		_, _ = w.Write(body)
+	})
+}
+
+func TestAuditBody_TeesBodyToSink(t *testing.T) {
+	t.Parallel()
+
+	var records []auditRecord
+
+	handler := AuditBody(recordingSink(&records))(echoBodyHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{"amount":100}`)))
+
+	assert.Equal(t, `{"amount":100}`, rr.Body.String(), "handler should read the body intact")
+	assert.Equal(t, []auditRecord{{path: "/payments", body: `{"amount":100}`}}, records)
+}
+
+func TestAuditBody_TruncatesSinkCopyOnly(t *testing.T) {
+	t.Parallel()
+
+	var records []auditRecord
+
+	body := strings.Repeat("x", 100)
+	handler := AuditBody(recordingSink(&records), WithAuditMaxSize(10))(echoBodyHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/docs", strings.NewReader(body)))
+
+	assert.Equal(t, body, rr.Body.String(), "handler should read the full body")
+	require.Len(t, records, 1)
+	assert.Equal(t, body[:10], records[0].body)
+}
+
+func TestAuditBody_FiltersPathsAndMethods(t *testing.T) {
+	t.Parallel()
+
+	var records []auditRecord
+
+	handler := AuditBody(recordingSink(&records),
+		WithAuditPaths("/payments", "/refunds"),
+		WithAuditMethods(http.MethodPost, http.MethodDelete),
+	)(echoBodyHandler())
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader("a")),
+		httptest.NewRequest(http.MethodDelete, "/refunds", strings.NewReader("b")),
+		httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("c")),
+		httptest.NewRequest(http.MethodPut, "/payments", strings.NewReader("d")),
+		httptest.NewRequest(http.MethodPost, "/payments", nil),
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	assert.Equal(t, []auditRecord{{path: "/payments", body: "a"}, {path: "/refunds", body: "b"}}, records)
+}
+
+func TestAuditBody_ClosesOriginalBody(t *testing.T) {
+	t.Parallel()
+
+	body := &closeTrackingBody{Reader: strings.NewReader("payload")}
+
+	var records []auditRecord
+
+	handler := AuditBody(recordingSink(&records))(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_ = r.Body.Close()
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Body = body
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, body.closed)
+	assert.Equal(t, []auditRecord{{path: "/", body: "payload"}}, records)
+}
+
+func TestAuditBody_ReadErrorReachesHandler(t *testing.T) {
+	t.Parallel()
+
+	var records []auditRecord
+
+	handler := MaxRequestSize(4)(AuditBody(recordingSink(&records))(echoBodyHandler()))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("too long")))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "handler should still see the body error")
+	require.Len(t, records, 1)
+	assert.Equal(t, "too ", records[0].body, "sink should get what was read before the error")
+}
+
+func TestAuditBody_InvalidArguments(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := AuditBody(nil, WithAuditMaxSize(0))(echoBodyHandler())
+
+	require.Len(t, h.records, 2)
+	assert.Equal(t, int64(0), h.records[0].Attrs["provided"])
+	assert.Equal(t, defaultAuditMaxSize, h.records[0].Attrs["default"])
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body")))
+	assert.Equal(t, "body", rr.Body.String())
+}
+
+// closeTrackingBody records whether Close was called.
+type closeTrackingBody struct {
+	io.Reader
+
+	closed bool
+}
+
+func (b *closeTrackingBody) Close() error {
+	b.closed = true
+
+	return nil
+}
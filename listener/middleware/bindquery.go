@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+var (
+	errUnsupportedQueryType = errors.New("unsupported query field type")
+	errQueryNotBool         = errors.New("not a boolean")
+	errQueryNotInt          = errors.New("not a valid integer")
+)
+
+// boundQueryKeyType is the context key for the value bound by BindQuery[T]; being
+// generic, each T gets its own key.
+type boundQueryKeyType[T any] struct{}
+
+// queryField is a struct field bound to a query parameter.
+type queryField struct {
+	name  string
+	index int
+}
+
+// BindQuery returns a middleware that decodes the URL query into a new T and stores
+// it in the request context, where handlers read it with GetQuery[T]. Exported fields
+// of T are bound by their `query:"name"` tag; untagged fields and `query:"-"` are
+// skipped. Supported field types are string, bool, the signed integer kinds, and
+// slices of these; a non-slice field takes the first value of its parameter and a
+// missing parameter leaves the field zero. If *T implements Validate() error, it is
+// called after decoding.
+//
+// Requests with a malformed value or failing validation are rejected with 400 Bad
+// Request (a JSON ErrorResponse inside JSONErrors) describing the problem.
+// If T is not a struct or has a tagged field of an unsupported type, an error is
+// logged and every request is rejected with 500 Internal Server Error.
+func BindQuery[T any]() func(http.Handler) http.Handler {
+	typ := reflect.TypeFor[T]()
+
+	fields, fieldsErr := queryFields(typ)
+	if fieldsErr != nil {
+		slog.Error("middleware: cannot bind query parameters, rejecting requests",
+			"type", typ.String(), "error", fieldsErr)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fieldsErr != nil {
+				writeMiddlewareError(w, r, http.StatusInternalServerError,
+					http.StatusText(http.StatusInternalServerError))
+
+				return
+			}
+
+			target := new(T)
+
+			err := decodeQuery(r.URL.Query(), reflect.ValueOf(target).Elem(), fields)
+			if err == nil {
+				if validator, ok := any(target).(interface{ Validate() error }); ok {
+					err = validator.Validate()
+				}
+			}
+
+			if err != nil {
+				writeMiddlewareError(w, r, http.StatusBadRequest, err.Error())
+
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), boundQueryKeyType[T]{}, target)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetQuery returns the query parameters bound by BindQuery[T] for the request.
+// It returns false if BindQuery[T] did not run for the request.
+func GetQuery[T any](ctx context.Context) (*T, bool) {
+	target, ok := ctx.Value(boundQueryKeyType[T]{}).(*T)
+
+	return target, ok
+}
+
+// queryFields returns the tagged fields of typ, checking that each has a supported type.
+func queryFields(typ reflect.Type) ([]queryField, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct", errUnsupportedQueryType, typ)
+	}
+
+	var fields []queryField
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+
+		name, ok := field.Tag.Lookup("query")
+		if !ok || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		elem := field.Type
+		if elem.Kind() == reflect.Slice {
+			elem = elem.Elem()
+		}
+
+		if !isQueryScalar(elem.Kind()) {
+			return nil, fmt.Errorf("%w: field %s is %s", errUnsupportedQueryType, field.Name, field.Type)
+		}
+
+		fields = append(fields, queryField{name: name, index: i})
+	}
+
+	return fields, nil
+}
+
+func isQueryScalar(kind reflect.Kind) bool {
+	switch kind { //nolint:exhaustive // all other kinds are unsupported
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeQuery sets the fields of target from query.
+func decodeQuery(query url.Values, target reflect.Value, fields []queryField) error {
+	for _, field := range fields {
+		values, ok := query[field.name]
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		dst := target.Field(field.index)
+
+		if dst.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(dst.Type(), len(values), len(values))
+
+			for i, value := range values {
+				err := setQueryValue(slice.Index(i), value)
+				if err != nil {
+					return fmt.Errorf("invalid query parameter %q: %w", field.name, err)
+				}
+			}
+
+			dst.Set(slice)
+
+			continue
+		}
+
+		err := setQueryValue(dst, values[0])
+		if err != nil {
+			return fmt.Errorf("invalid query parameter %q: %w", field.name, err)
+		}
+	}
+
+	return nil
+}
+
+// setQueryValue parses value into dst, which has a kind accepted by isQueryScalar.
+func setQueryValue(dst reflect.Value, value string) error {
+	switch dst.Kind() { //nolint:exhaustive // queryFields only admits the kinds below
+	case reflect.String:
+		dst.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is %w", value, errQueryNotBool)
+		}
+
+		dst.SetBool(parsed)
+	default:
+		parsed, err := strconv.ParseInt(value, 10, dst.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is %w", value, errQueryNotInt)
+		}
+
+		dst.SetInt(parsed)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errPageOutOfRange = errors.New("page must be positive")
+
+type listQuery struct {
+	Page    int      `query:"page"`
+	Search  string   `query:"q"`
+	Archive bool     `query:"archived"`
+	Tags    []string `query:"tag"`
+	IDs     []int64  `query:"id"`
+	Ignored string
+}
+
+func (q *listQuery) Validate() error {
+	if q.Page < 0 {
+		return errPageOutOfRange
+	}
+
+	return nil
+}
+
+// bindListQuery serves target through BindQuery[listQuery], capturing the bound value.
+func bindListQuery(target string) (*httptest.ResponseRecorder, *listQuery) {
+	var bound *listQuery
+
+	handler := BindQuery[listQuery]()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bound, _ = GetQuery[listQuery](r.Context())
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return rr, bound
+}
+
+func TestBindQuery_Success(t *testing.T) {
+	t.Parallel()
+
+	rr, bound := bindListQuery("/items?page=3&q=go&archived=true&tag=a&tag=b&id=7&id=9&Ignored=x")
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, bound)
+	assert.Equal(t, listQuery{
+		Page:    3,
+		Search:  "go",
+		Archive: true,
+		Tags:    []string{"a", "b"},
+		IDs:     []int64{7, 9},
+	}, *bound)
+}
+
+func TestBindQuery_MissingParamsStayZero(t *testing.T) {
+	t.Parallel()
+
+	rr, bound := bindListQuery("/items")
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NotNil(t, bound)
+	assert.Equal(t, listQuery{}, *bound)
+}
+
+func TestBindQuery_TypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{"int", "/items?page=abc", `invalid query parameter "page": "abc" is not a valid integer`},
+		{"bool", "/items?archived=maybe", `invalid query parameter "archived": "maybe" is not a boolean`},
+		{"slice element", "/items?id=1&id=x", `invalid query parameter "id": "x" is not a valid integer`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr, bound := bindListQuery(tt.target)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, tt.want+"\n", rr.Body.String())
+			assert.Nil(t, bound, "handler must not be called")
+		})
+	}
+}
+
+func TestBindQuery_ValidationFailure(t *testing.T) {
+	t.Parallel()
+
+	rr, bound := bindListQuery("/items?page=-1")
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, errPageOutOfRange.Error()+"\n", rr.Body.String())
+	assert.Nil(t, bound)
+}
+
+func TestBindQuery_JSONErrors(t *testing.T) {
+	t.Parallel()
+
+	handler := Chain(RequestID(), JSONErrors(), BindQuery[listQuery]())(okHandler())
+
+	req := requestWithID("req-400")
+	req.URL.RawQuery = "page=abc"
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	body := decodeErrorResponse(t, rr, http.StatusBadRequest)
+	assert.Contains(t, body.Error, `"page"`)
+	assert.Equal(t, "req-400", body.RequestID)
+}
+
+func TestBindQuery_UnsupportedType(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	type badQuery struct {
+		Ratio float64 `query:"ratio"`
+	}
+
+	handler := BindQuery[badQuery]()(okHandler())
+
+	require.Len(t, h.records, 1)
+	assert.ErrorIs(t, h.records[0].Attrs["error"].(error), errUnsupportedQueryType) //nolint:forcetypeassert // slog stores errors as-is
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestGetQuery_NotBound(t *testing.T) {
+	t.Parallel()
+
+	bound, ok := GetQuery[listQuery](httptest.NewRequest(http.MethodGet, "/", nil).Context())
+
+	assert.False(t, ok)
+	assert.Nil(t, bound)
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// MiddlewareFunc is a middleware constructor step that can fail, e.g. because
+// of invalid configuration detected while wrapping the handler.
+type MiddlewareFunc func(http.Handler) (http.Handler, error)
+
+var errNilMiddlewareHandler = errors.New("middleware returned nil handler")
+
+// MultiError collects the errors returned by the steps of ChainWithErrors.
+type MultiError struct {
+	Errors []error
+}
+
+// Error joins the collected error messages.
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the collected errors for use with errors.Is and errors.As.
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// Chain composes middlewares into a single middleware. The first middleware is
+// the outermost: Chain(a, b)(h) is equivalent to a(b(h)). Nil middlewares are skipped.
+func Chain(middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			if middlewares[i] != nil {
+				next = middlewares[i](next)
+			}
+		}
+
+		return next
+	}
+}
+
+// OnMethods returns a middleware that applies mw only to requests whose method is one of
+// methods, e.g. OnMethods([]string{http.MethodGet, http.MethodHead}, Compress()) or
+// OnMethods([]string{http.MethodPost}, RateLimit(10, 20)). Requests with other methods
+// bypass mw and go straight to the next handler. Methods are matched exactly, as HTTP
+// methods are case-sensitive. mw wraps each handler once, so state such as a rate
+// limiter's bucket is shared by the matching requests only. A nil mw is ignored.
+func OnMethods(methods []string, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	if mw == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	set := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		set[method] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := set[r.Method]; ok {
+				wrapped.ServeHTTP(w, r)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ChainWithErrors composes fallible middleware steps like Chain (the first step is
+// the outermost) into a single MiddlewareFunc, which can itself be passed to another
+// ChainWithErrors. The steps run each time the result wraps a handler. Every step is
+// invoked even if an earlier one fails, so all configuration errors are reported
+// together as a *MultiError; each error is prefixed with the step's position. A step
+// that returns a nil handler without error fails with errNilMiddlewareHandler. Nil
+// steps are skipped.
+func ChainWithErrors(fns ...MiddlewareFunc) MiddlewareFunc {
+	return func(next http.Handler) (http.Handler, error) {
+		var errs []error
+
+		handler := next
+
+		for i := len(fns) - 1; i >= 0; i-- {
+			if fns[i] == nil {
+				continue
+			}
+
+			wrapped, err := fns[i](handler)
+
+			switch {
+			case err != nil:
+				errs = append(errs, fmt.Errorf("middleware %d: %w", i, err))
+			case wrapped == nil:
+				errs = append(errs, fmt.Errorf("middleware %d: %w", i, errNilMiddlewareHandler))
+			default:
+				handler = wrapped
+			}
+		}
+
+		if len(errs) > 0 {
+			slices.Reverse(errs)
+
+			return nil, &MultiError{Errors: errs}
+		}
+
+		return handler, nil
+	}
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_Order(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+
+	tag := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	handler := Chain(tag("a"), nil, tag("b"))(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		order = append(order, "handler")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, []string{"a", "b", "handler"}, order)
+}
+
+func TestChain_Empty(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	handler := Chain()(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		called = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, called)
+}
+
+// stepFunc returns a MiddlewareFunc recording its invocation and, if err is set, failing.
+func stepFunc(name string, calls *[]string, err error) MiddlewareFunc {
+	return func(next http.Handler) (http.Handler, error) {
+		*calls = append(*calls, name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Steps", name)
+			next.ServeHTTP(w, r)
+		}), nil
+	}
+}
+
+func TestChainWithErrors_Success(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	mw := ChainWithErrors(stepFunc("a", &calls, nil), nil, stepFunc("b", &calls, nil))
+
+	for _, body := range []string{"one", "two"} {
+		handler, err := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, []string{"a", "b"}, rec.Header().Values("X-Steps"))
+		assert.Equal(t, body, rec.Body.String())
+	}
+
+	// Steps run innermost first, once per wrapped handler.
+	assert.Equal(t, []string{"b", "a", "b", "a"}, calls)
+}
+
+func TestChainWithErrors_CollectsErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	errSecond := errors.New("second failed")
+	errFourth := errors.New("fourth failed")
+
+	handler, err := ChainWithErrors(
+		stepFunc("first", &calls, nil),
+		stepFunc("second", &calls, errSecond),
+		stepFunc("third", &calls, nil),
+		stepFunc("fourth", &calls, errFourth),
+	)(okHandler())
+
+	require.Error(t, err)
+	assert.Nil(t, handler)
+	assert.ElementsMatch(t, []string{"first", "second", "third", "fourth"}, calls,
+		"all steps should be called despite errors")
+
+	var multi *MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi.Errors, 2)
+	require.ErrorIs(t, err, errSecond)
+	require.ErrorIs(t, err, errFourth)
+	assert.Equal(t, "middleware 1: second failed; middleware 3: fourth failed", err.Error())
+}
+
+func TestChainWithErrors_NilHandler(t *testing.T) {
+	t.Parallel()
+
+	_, err := ChainWithErrors(func(http.Handler) (http.Handler, error) {
+		return nil, nil //nolint:nilnil // exercising a misbehaving step
+	})(okHandler())
+
+	require.ErrorIs(t, err, errNilMiddlewareHandler)
+}
+
+func TestChainWithErrors_Nested(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	outer := ChainWithErrors(
+		ChainWithErrors(stepFunc("inner", &calls, nil)),
+		stepFunc("outer", &calls, nil),
+	)
+
+	handler, err := outer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, []string{"inner", "outer"}, rec.Header().Values("X-Steps"))
+}
+
+func TestOnMethods(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	counting := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+
+			w.Header().Set("X-Middleware", "ran")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	handler := OnMethods([]string{http.MethodGet, http.MethodHead}, counting)(okHandler())
+
+	tests := []struct {
+		method  string
+		wantRun bool
+	}{
+		{method: http.MethodGet, wantRun: true},
+		{method: http.MethodHead, wantRun: true},
+		{method: http.MethodPost, wantRun: false},
+		{method: http.MethodDelete, wantRun: false},
+		{method: "get", wantRun: false},
+	}
+
+	for _, tt := range tests {
+		calls = 0
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, httptest.NewRequest(tt.method, "/", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code, tt.method)
+
+		if tt.wantRun {
+			assert.Equal(t, 1, calls, "%s should run the middleware", tt.method)
+			assert.Equal(t, "ran", rr.Header().Get("X-Middleware"))
+		} else {
+			assert.Zero(t, calls, "%s should skip the middleware", tt.method)
+			assert.Empty(t, rr.Header().Get("X-Middleware"))
+		}
+	}
+}
+
+func TestOnMethods_RateLimitOnlyPost(t *testing.T) {
+	t.Parallel()
+
+	handler := OnMethods([]string{http.MethodPost}, RateLimit(1, 1))(okHandler())
+
+	send := func(method string) int {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, "/", nil))
+
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send(http.MethodPost))
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPost))
+	assert.Equal(t, http.StatusOK, send(http.MethodGet), "GET should not be rate limited")
+}
+
+func TestOnMethods_NilMiddleware(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	OnMethods([]string{http.MethodGet}, nil)(okHandler()).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
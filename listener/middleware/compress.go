@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"compress/gzip"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"strconv"
@@ -12,9 +13,13 @@ import (
 	"sync"
 )
 
-// minCompressSize is the minimum response size in bytes before compression is applied.
+// minCompressSize is the default minimum response size in bytes before compression is applied.
 const minCompressSize = 256
 
+// maxBufferedGzipSize is the amount of compressed output held back before the response
+// is streamed. Responses that complete within it are sent with a Content-Length.
+const maxBufferedGzipSize = 64 << 10
+
 // compressedContentTypes contains content types that are already compressed
 // and should not be compressed again.
 var compressedContentTypes = map[string]bool{ //nolint:gochecknoglobals
@@ -48,6 +53,72 @@ var compressedContentTypes = map[string]bool{ //nolint:gochecknoglobals
 	"application/x-shockwave-flash": true,
 }
 
+// compressConfig holds configuration for the Compress middleware.
+type compressConfig struct {
+	skipFunc    func(*http.Request) bool
+	noVary      bool
+	minSize     int
+	ratioLogger *slog.Logger
+}
+
+// CompressOption configures the Compress middleware.
+type CompressOption func(*compressConfig)
+
+// WithSkipFunc sets a predicate evaluated before the response writer is wrapped.
+// Requests for which it returns true bypass gzip entirely: the response is neither
+// buffered nor compressed. Use it for paths such as downloads or streams that should
+// never be compressed, which is cheaper than deciding after the handler writes.
+func WithSkipFunc(fn func(*http.Request) bool) CompressOption {
+	return func(c *compressConfig) {
+		c.skipFunc = fn
+	}
+}
+
+// WithoutVary stops Compress from adding "Vary: Accept-Encoding" to responses.
+// Only use it when Vary is managed elsewhere: shared caches (CDNs, proxies) key
+// responses on the Vary header, and without it a cache may serve a gzip-encoded
+// body to a client that did not accept gzip, or vice versa.
+func WithoutVary() CompressOption {
+	return func(c *compressConfig) {
+		c.noVary = true
+	}
+}
+
+// WithMinSize sets the minimum response size in bytes before compression is applied.
+// The size is the total across all writes: the body is buffered until the writes add up
+// to minSize bytes, and the compression decision is made at the Write that reaches it,
+// so a handler writing many small chunks is treated the same as one writing a single
+// large chunk. If the handler flushes or returns before reaching minSize, the decision
+// is made then, and the body is sent uncompressed. Content type, Content-Encoding and
+// status checks apply either way.
+// If minSize is not positive, it defaults to 256 with a warning log.
+func WithMinSize(minSize int) CompressOption {
+	return func(c *compressConfig) {
+		if minSize <= 0 {
+			slog.Warn("middleware: minSize must be positive, using default",
+				"provided", minSize, "default", minCompressSize)
+
+			minSize = minCompressSize
+		}
+
+		c.minSize = minSize
+	}
+}
+
+// WithRatioLogging logs, at debug level on logger, the original and compressed body
+// sizes and their ratio (original divided by compressed) for every compressed
+// response, to help tune WithMinSize and the skipped content types. Responses that
+// are not compressed are not measured or logged. A nil logger uses slog.Default().
+func WithRatioLogging(logger *slog.Logger) CompressOption {
+	return func(c *compressConfig) {
+		if logger == nil {
+			logger = slog.Default()
+		}
+
+		c.ratioLogger = logger
+	}
+}
+
 var gzipWriterPool = sync.Pool{ //nolint:gochecknoglobals
 	New: func() any {
 		return gzip.NewWriter(io.Discard)
@@ -56,17 +127,39 @@ var gzipWriterPool = sync.Pool{ //nolint:gochecknoglobals
 
 // gzipResponseWriter wraps http.ResponseWriter to apply gzip compression.
 // It buffers data until it can decide whether to compress, then commits
-// headers and flushes the buffer.
+// headers and flushes the buffer. When compressing, the compressed output is
+// additionally held back (up to maxBufferedGzipSize) so that a response that
+// completes without an explicit flush can be sent with a Content-Length.
 type gzipResponseWriter struct {
 	http.ResponseWriter
 
 	gw         *gzip.Writer
+	minSize    int
 	buf        []byte
+	pending    []byte
 	statusCode int
 	decided    bool
 	skipGzip   bool
+	headerSent bool
 	hijacked   bool
 	commitErr  error
+
+	// originalSize and compressedSize count the body bytes fed to and produced by
+	// the gzip.Writer.
+	originalSize   int64
+	compressedSize int64
+}
+
+// gzipSink is the destination of the gzip.Writer: compressed output is routed
+// back to the gzipResponseWriter, which holds it until headers are sent.
+type gzipSink struct {
+	w *gzipResponseWriter
+}
+
+func (s gzipSink) Write(p []byte) (int, error) {
+	s.w.compressedSize += int64(len(p))
+
+	return s.w.writeCompressed(p)
 }
 
 func (w *gzipResponseWriter) WriteHeader(code int) {
@@ -89,12 +182,14 @@ func (w *gzipResponseWriter) Write(b []byte) (int, error) {
 			return w.ResponseWriter.Write(b) //nolint:wrapcheck
 		}
 
+		w.originalSize += int64(len(b))
+
 		return w.gw.Write(b) //nolint:wrapcheck
 	}
 
 	w.buf = append(w.buf, b...)
 
-	if len(w.buf) >= minCompressSize {
+	if len(w.buf) >= w.minSize {
 		w.commit()
 
 		if w.commitErr != nil {
@@ -105,14 +200,36 @@ func (w *gzipResponseWriter) Write(b []byte) (int, error) {
 	return len(b), nil
 }
 
+// ReadFrom implements io.ReaderFrom so io.Copy(w, src) is routed through the
+// buffering and compression logic rather than any ReaderFrom of the underlying
+// writer, which would bypass gzip. Once compression has been skipped, it delegates
+// to the underlying io.ReaderFrom (if any) to keep optimizations such as sendfile.
+func (w *gzipResponseWriter) ReadFrom(src io.Reader) (int64, error) {
+	if w.decided && w.skipGzip {
+		if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+			return rf.ReadFrom(src) //nolint:wrapcheck
+		}
+	}
+
+	// writerOnly hides ReadFrom so io.Copy does not recurse into this method.
+	return io.Copy(writerOnly{w}, src) //nolint:wrapcheck
+}
+
+// writerOnly exposes only the Write method of the wrapped writer.
+type writerOnly struct {
+	io.Writer
+}
+
 // Flush commits any buffered data, flushes the gzip internal state to the underlying
 // writer, and then flushes the underlying writer. This ensures streaming responses
-// (e.g. SSE) produce valid gzip output when explicitly flushed.
+// (e.g. SSE) produce valid gzip output when explicitly flushed. Once flushed, the
+// response is streamed and no Content-Length is set.
 func (w *gzipResponseWriter) Flush() {
 	w.commit()
 
 	if !w.skipGzip {
 		_ = w.gw.Flush()
+		_ = w.sendPending()
 	}
 
 	rc := http.NewResponseController(w.ResponseWriter)
@@ -139,6 +256,67 @@ func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
 	return w.ResponseWriter
 }
 
+// discardUncommitted drops the buffered body and status code if nothing has been
+// sent to the underlying writer yet, so an outer middleware (e.g. Recovery) can
+// still write a different response. It reports whether the discard succeeded.
+func (w *gzipResponseWriter) discardUncommitted() bool {
+	if w.headerSent || w.hijacked {
+		return false
+	}
+
+	if w.decided && !w.skipGzip {
+		w.ResponseWriter.Header().Del("Content-Encoding")
+		w.gw.Reset(gzipSink{w})
+	}
+
+	w.buf = nil
+	w.pending = nil
+	w.originalSize = 0
+	w.compressedSize = 0
+	w.statusCode = 0
+	w.decided = false
+	w.skipGzip = false
+	w.commitErr = nil
+
+	return true
+}
+
+// writeCompressed receives gzip output. Before headers are sent it is held in
+// pending; once pending exceeds maxBufferedGzipSize the response is streamed.
+func (w *gzipResponseWriter) writeCompressed(p []byte) (int, error) {
+	if w.headerSent {
+		return w.ResponseWriter.Write(p) //nolint:wrapcheck
+	}
+
+	w.pending = append(w.pending, p...)
+
+	if len(w.pending) > maxBufferedGzipSize {
+		err := w.sendPending()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// sendPending writes the status code, if not yet sent, and any held-back compressed output.
+func (w *gzipResponseWriter) sendPending() error {
+	if !w.headerSent {
+		w.ResponseWriter.WriteHeader(w.statusCode)
+		w.headerSent = true
+	}
+
+	if len(w.pending) == 0 {
+		return nil
+	}
+
+	_, err := w.ResponseWriter.Write(w.pending)
+	w.pending = nil
+
+	return err //nolint:wrapcheck
+}
+
 func (w *gzipResponseWriter) shouldSkipGzip() bool {
 	ct := w.ResponseWriter.Header().Get("Content-Type")
 	if ct == "" {
@@ -151,7 +329,7 @@ func (w *gzipResponseWriter) shouldSkipGzip() bool {
 	switch {
 	case compressedContentTypes[baseType]:
 		return true
-	case len(w.buf) < minCompressSize:
+	case len(w.buf) < w.minSize:
 		return true
 	case w.ResponseWriter.Header().Get("Content-Encoding") != "":
 		return true
@@ -185,21 +363,27 @@ func (w *gzipResponseWriter) commit() {
 
 		w.ResponseWriter.Header().Set("Content-Encoding", "gzip")
 		w.ResponseWriter.Header().Del("Content-Length")
+
+		if len(w.buf) > 0 {
+			w.originalSize += int64(len(w.buf))
+			_, w.commitErr = w.gw.Write(w.buf)
+			w.buf = nil
+		}
+
+		return
 	}
 
 	w.ResponseWriter.WriteHeader(w.statusCode)
+	w.headerSent = true
 
 	if len(w.buf) > 0 {
-		if w.skipGzip {
-			_, w.commitErr = w.ResponseWriter.Write(w.buf)
-		} else {
-			_, w.commitErr = w.gw.Write(w.buf)
-		}
-
+		_, w.commitErr = w.ResponseWriter.Write(w.buf)
 		w.buf = nil
 	}
 }
 
+// close finishes the response. A compressed response whose output was held back
+// entirely is sent with a Content-Length instead of chunked transfer encoding.
 func (w *gzipResponseWriter) close() {
 	if w.hijacked {
 		return
@@ -207,9 +391,36 @@ func (w *gzipResponseWriter) close() {
 
 	w.commit()
 
-	if !w.skipGzip {
-		_ = w.gw.Close()
+	if w.skipGzip {
+		return
+	}
+
+	_ = w.gw.Close()
+
+	if !w.headerSent {
+		w.ResponseWriter.Header().Set("Content-Length", strconv.Itoa(len(w.pending)))
+	}
+
+	_ = w.sendPending()
+}
+
+// logRatio logs the body sizes of a compressed response to logger.
+func (w *gzipResponseWriter) logRatio(logger *slog.Logger, r *http.Request) {
+	if w.hijacked || !w.decided || w.skipGzip {
+		return
+	}
+
+	ratio := 0.0
+	if w.compressedSize > 0 {
+		ratio = float64(w.originalSize) / float64(w.compressedSize)
 	}
+
+	logger.DebugContext(r.Context(), "compressed response",
+		slog.String("path", r.URL.Path),
+		slog.Int64("original_size", w.originalSize),
+		slog.Int64("compressed_size", w.compressedSize),
+		slog.Float64("ratio", ratio),
+	)
 }
 
 // hasZeroQuality reports whether the parameter string (after the first ";")
@@ -275,11 +486,34 @@ func acceptsGzip(header string) bool {
 
 // Compress returns a middleware that compresses response bodies using gzip
 // when the client supports it (via Accept-Encoding header). It skips compression
-// for small responses (under 256 bytes) and already-compressed content types.
-func Compress() func(http.Handler) http.Handler {
+// for small responses (under 256 bytes by default, counted across all writes) and
+// already-compressed content types.
+//
+// Options:
+//   - WithSkipFunc(fn) - bypass compression for requests matching a predicate
+//   - WithoutVary() - do not add the Vary: Accept-Encoding header
+//   - WithMinSize(n) - change the minimum response size for compression
+//   - WithRatioLogging(logger) - log the sizes and ratio of compressed responses
+func Compress(opts ...CompressOption) func(http.Handler) http.Handler {
+	cfg := compressConfig{minSize: minCompressSize}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Add("Vary", "Accept-Encoding")
+			if !cfg.noVary {
+				w.Header().Add("Vary", "Accept-Encoding")
+			}
+
+			if cfg.skipFunc != nil && cfg.skipFunc(r) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
 
 			if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
 				next.ServeHTTP(w, r)
@@ -294,25 +528,34 @@ func Compress() func(http.Handler) http.Handler {
 				return
 			}
 
-			gz.Reset(w)
-
 			grw := &gzipResponseWriter{
 				ResponseWriter: w,
 				gw:             gz,
+				minSize:        cfg.minSize,
 			}
 
+			gz.Reset(gzipSink{grw})
+
 			panicked := true
 
 			defer func() {
 				if panicked {
-					// On panic after gzip has committed, close the writer to produce
-					// a valid gzip stream end. This also ensures the pooled writer
-					// is not returned in a dirty state.
+					// On panic after gzip output has started streaming, close the writer
+					// to produce a valid gzip stream end. Output that was only held back
+					// is dropped so an outer middleware can still send an error response.
 					if grw.decided && !grw.skipGzip && !grw.hijacked {
-						_ = gz.Close()
+						if grw.headerSent {
+							_ = gz.Close()
+						} else {
+							grw.discardUncommitted()
+						}
 					}
 				} else {
 					grw.close()
+
+					if cfg.ratioLogger != nil {
+						grw.logRatio(cfg.ratioLogger, r)
+					}
 				}
 
 				gz.Reset(io.Discard)
@@ -1,10 +1,15 @@
 package middleware
 
 import (
+	"bytes"
 	"compress/gzip"
 	"io"
+	"log/slog"
+	"math/rand/v2"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -31,7 +36,7 @@ func TestCompress_GzipResponseWhenAccepted(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
-	assert.Empty(t, rr.Header().Get("Content-Length"))
+	assert.Equal(t, strconv.Itoa(rr.Body.Len()), rr.Header().Get("Content-Length"))
 
 	gr, err := gzip.NewReader(rr.Body)
 	require.NoError(t, err)
@@ -83,7 +88,7 @@ func TestCompress_ProperHeaders(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
-	assert.Empty(t, rr.Header().Get("Content-Length"))
+	assert.Equal(t, strconv.Itoa(rr.Body.Len()), rr.Header().Get("Content-Length"))
 }
 
 func TestCompress_SkipSmallResponses(t *testing.T) {
@@ -295,6 +300,74 @@ func TestCompress_MultipleWriteCalls(t *testing.T) {
 	assert.Equal(t, strings.Repeat(chunk, 5), string(decompressed))
 }
 
+func TestCompress_WithMinSize_DecidesAcrossWrites(t *testing.T) {
+	t.Parallel()
+
+	chunk := strings.Repeat("x", 30)
+
+	var encodings []string
+
+	handler := Compress(WithMinSize(300))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+
+		for range 10 {
+			_, _ = w.Write([]byte(chunk))
+			encodings = append(encodings, w.Header().Get("Content-Encoding"))
+		}
+	}))
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, slices.Repeat([]string{""}, 9), encodings[:9], "no decision before the writes reach minSize")
+	assert.Equal(t, "gzip", encodings[9], "decision should be made at the write reaching minSize")
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+
+	gr, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+
+	defer func() { _ = gr.Close() }()
+
+	decompressed, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat(chunk, 10), string(decompressed))
+}
+
+func TestCompress_WithMinSize_BelowThresholdAtClose(t *testing.T) {
+	t.Parallel()
+
+	handler := Compress(WithMinSize(1024))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+
+		for range 10 {
+			_, _ = w.Write([]byte(strings.Repeat("x", 30)))
+		}
+	}))
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, strings.Repeat("x", 300), rr.Body.String())
+}
+
+func TestWithMinSize_InvalidValue(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	cfg := compressConfig{}
+	WithMinSize(0)(&cfg)
+
+	assert.Equal(t, minCompressSize, cfg.minSize)
+	require.Len(t, h.records, 1)
+	assert.Equal(t, int64(0), h.records[0].Attrs["provided"])
+}
+
 func TestCompress_SkipExistingContentEncoding(t *testing.T) {
 	t.Parallel()
 
@@ -393,3 +466,303 @@ func TestCompress_VaryHeaderPresent(t *testing.T) {
 
 	assert.Contains(t, rr.Header().Get("Vary"), "Accept-Encoding")
 }
+
+func TestCompress_WithoutVary(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("compressible body ", 50)
+
+	tests := []struct {
+		name     string
+		opts     []CompressOption
+		wantVary bool
+	}{
+		{name: "default adds Vary", opts: nil, wantVary: true},
+		{name: "WithoutVary omits Vary", opts: []CompressOption{WithoutVary()}, wantVary: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := Compress(tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte(body))
+			}))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"), "compression should be unaffected")
+
+			if tt.wantVary {
+				assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+			} else {
+				assert.Empty(t, rr.Header().Values("Vary"))
+			}
+		})
+	}
+}
+
+func TestCompress_WithSkipFunc(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("Hello, World! This is a compressible response body. ", 20)
+
+	var wrapped bool
+
+	handler := Compress(WithSkipFunc(func(r *http.Request) bool {
+		return strings.HasPrefix(r.URL.Path, "/download/")
+	}))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, wrapped = w.(*gzipResponseWriter)
+
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(body))
+	}))
+
+	t.Run("matching path bypasses compression", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/download/file.txt", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		handler.ServeHTTP(rr, req)
+
+		assert.False(t, wrapped, "skipped request should not be wrapped for buffering")
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rr.Body.String())
+	})
+
+	t.Run("other paths still compress", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/data", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		handler.ServeHTTP(rr, req)
+
+		assert.True(t, wrapped)
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+
+		gr, err := gzip.NewReader(rr.Body)
+		require.NoError(t, err)
+
+		defer func() { _ = gr.Close() }()
+
+		decompressed, err := io.ReadAll(gr)
+		require.NoError(t, err)
+		assert.Equal(t, body, string(decompressed))
+	})
+}
+
+// readerFromRecorder is a ResponseRecorder that implements io.ReaderFrom
+// and records whether ReadFrom was used.
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+
+	readFromCalled bool
+}
+
+func (r *readerFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	r.readFromCalled = true
+
+	return io.Copy(r.ResponseRecorder, src) //nolint:wrapcheck
+}
+
+func TestCompress_IOCopy(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("Large file content that compresses well. ", 50000)
+
+	handler := Compress()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+
+		// LimitReader hides strings.Reader's WriteTo so io.Copy goes through ReadFrom.
+		_, err := io.Copy(w, io.LimitReader(strings.NewReader(body), int64(len(body))))
+		assert.NoError(t, err)
+	}))
+
+	rr := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.False(t, rr.readFromCalled, "underlying ReadFrom must not bypass gzip")
+	assert.Less(t, rr.Body.Len(), len(body))
+
+	gr, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+
+	defer func() { _ = gr.Close() }()
+
+	decompressed, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decompressed))
+}
+
+func TestCompress_IOCopySkippedUsesUnderlyingReaderFrom(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("x", 4096)
+
+	handler := Compress()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte(body[:minCompressSize]))
+
+		_, err := io.Copy(w, io.LimitReader(strings.NewReader(body[minCompressSize:]), int64(len(body))))
+		assert.NoError(t, err)
+	}))
+
+	rr := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.True(t, rr.readFromCalled, "skipped responses should use the underlying ReadFrom")
+	assert.Equal(t, body, rr.Body.String())
+}
+
+// incompressible returns n deterministic pseudo-random bytes that gzip cannot shrink.
+func incompressible(n int) []byte {
+	data := make([]byte, n)
+	_, _ = rand.NewChaCha8([32]byte{}).Read(data)
+
+	return data
+}
+
+func TestCompress_ContentLengthWhenFullyBuffered(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("fully buffered ", 100)
+
+	handler := Compress()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write([]byte(body))
+	}))
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, strconv.Itoa(rr.Body.Len()), rr.Header().Get("Content-Length"))
+	assert.Less(t, rr.Body.Len(), len(body))
+
+	gr, err := gzip.NewReader(rr.Body)
+	require.NoError(t, err)
+
+	decompressed, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decompressed))
+}
+
+func TestCompress_NoContentLengthWhenStreamed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		write func(w http.ResponseWriter) []byte
+	}{
+		{
+			name: "output exceeds buffer",
+			write: func(w http.ResponseWriter) []byte {
+				data := incompressible(2 * maxBufferedGzipSize)
+				_, _ = w.Write(data)
+
+				return data
+			},
+		},
+		{
+			name: "explicit flush",
+			write: func(w http.ResponseWriter) []byte {
+				data := bytes.Repeat([]byte("streamed "), 100)
+				_, _ = w.Write(data)
+				_ = http.NewResponseController(w).Flush()
+
+				return data
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var written []byte
+
+			handler := Compress()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				written = tt.write(w)
+			}))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+			assert.Empty(t, rr.Header().Get("Content-Length"))
+
+			gr, err := gzip.NewReader(rr.Body)
+			require.NoError(t, err)
+
+			decompressed, err := io.ReadAll(gr)
+			require.NoError(t, err)
+			assert.Equal(t, written, decompressed)
+		})
+	}
+}
+
+func TestCompress_WithRatioLogging(t *testing.T) {
+	t.Parallel()
+
+	h := &captureHandler{}
+	body := strings.Repeat("hello world ", 100)
+
+	handler := Compress(WithRatioLogging(slog.New(h)))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+
+		if r.URL.Path == "/small" {
+			_, _ = w.Write([]byte("tiny"))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	require.Len(t, h.records, 1)
+
+	record := h.records[0]
+	assert.Equal(t, slog.LevelDebug, record.Level)
+	assert.Equal(t, "/large", record.Attrs["path"])
+	assert.Equal(t, int64(len(body)), record.Attrs["original_size"])
+	assert.Equal(t, int64(rec.Body.Len()), record.Attrs["compressed_size"])
+	assert.InDelta(t, float64(len(body))/float64(rec.Body.Len()), record.Attrs["ratio"], 0.0001)
+	assert.Greater(t, record.Attrs["ratio"], 1.0)
+
+	small := httptest.NewRequest(http.MethodGet, "/small", nil)
+	small.Header.Set("Accept-Encoding", "gzip")
+	handler.ServeHTTP(httptest.NewRecorder(), small)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/large", nil))
+
+	assert.Len(t, h.records, 1, "skipped responses should not be logged")
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const defaultConcurrencyLimit = 100
+
+// ConcurrencyLimit returns a middleware that allows at most n requests to be handled
+// at the same time. When all slots are taken, a request waits up to maxWait for one
+// to free up, smoothing short bursts instead of rejecting them outright; if none
+// frees in time, or the client goes away while waiting, it gets 503 Service
+// Unavailable (a JSON ErrorResponse inside JSONErrors). A maxWait of 0 rejects
+// immediately when at capacity.
+// If n is not positive, it defaults to 100 with a warning log.
+// If maxWait is negative, it defaults to 0 with a warning log.
+func ConcurrencyLimit(n int, maxWait time.Duration) func(http.Handler) http.Handler {
+	if n <= 0 {
+		slog.Warn("middleware: n must be positive, using default", "provided", n, "default", defaultConcurrencyLimit)
+
+		n = defaultConcurrencyLimit
+	}
+
+	if maxWait < 0 {
+		slog.Warn("middleware: maxWait must not be negative, using default", "provided", maxWait, "default", 0)
+
+		maxWait = 0
+	}
+
+	slots := make(chan struct{}, n)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !acquireSlot(r, slots, maxWait) {
+				writeMiddlewareError(w, r, http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable))
+
+				return
+			}
+
+			defer func() { <-slots }()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// acquireSlot takes a slot, waiting up to maxWait, and reports whether it succeeded.
+func acquireSlot(r *http.Request, slots chan struct{}, maxWait time.Duration) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if maxWait == 0 {
+		return false
+	}
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
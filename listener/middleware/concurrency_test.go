@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHandler signals on started when a request enters and waits for release.
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/block" {
+			started <- struct{}{}
+			<-release
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestConcurrencyLimit_WaitsForSlot(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := ConcurrencyLimit(1, 5*time.Second)(blockingHandler(started, release))
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/block", nil))
+	})
+
+	<-started
+
+	waited := make(chan int)
+
+	go func() {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		waited <- rr.Code
+	}()
+
+	select {
+	case <-waited:
+		t.Fatal("request should wait while the only slot is taken")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, http.StatusOK, <-waited, "waiting request should succeed once the slot frees")
+}
+
+func TestConcurrencyLimit_TimesOutUnderSustainedLoad(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := ConcurrencyLimit(2, 20*time.Millisecond)(blockingHandler(started, release))
+
+	var wg sync.WaitGroup
+
+	for range 2 {
+		wg.Go(func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/block", nil))
+		})
+
+		<-started
+	}
+
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond, "request should wait maxWait before giving up")
+
+	close(release)
+	wg.Wait()
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code, "slots should be released after requests finish")
+}
+
+func TestConcurrencyLimit_ZeroWaitRejectsImmediately(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := ConcurrencyLimit(1, 0)(blockingHandler(started, release))
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/block", nil))
+	})
+
+	<-started
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	close(release)
+	wg.Wait()
+}
+
+func TestConcurrencyLimit_InvalidArguments(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := ConcurrencyLimit(0, -time.Second)(okHandler())
+
+	require.Len(t, h.records, 2)
+	assert.Equal(t, int64(0), h.records[0].Attrs["provided"])
+	assert.Equal(t, int64(defaultConcurrencyLimit), h.records[0].Attrs["default"])
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// WithContextValues returns a middleware that replaces the request context with the
+// one returned by fn before calling the next handler, so values such as a tenant ID or
+// the deployment region can be attached to every request at the edge, e.g.
+//
+//	WithContextValues(func(r *http.Request) context.Context {
+//		return context.WithValue(r.Context(), regionKey{}, "eu-west-1")
+//	})
+//
+// fn should derive the new context from r.Context() to keep its cancellation and the
+// values set by outer middlewares. If fn returns nil, the request keeps its context.
+// If fn is nil, the middleware passes requests through with a warning log.
+func WithContextValues(fn func(*http.Request) context.Context) func(http.Handler) http.Handler {
+	if fn == nil {
+		slog.Warn("middleware: context function is nil, requests pass through unchanged")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fn != nil {
+				if ctx := fn(r); ctx != nil {
+					r = r.WithContext(ctx)
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tenantKey struct{}
+
+func TestWithContextValues_SeedsValue(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tenant   any
+		listener string
+	)
+
+	seed := WithContextValues(func(r *http.Request) context.Context {
+		return context.WithValue(r.Context(), tenantKey{}, r.Header.Get("X-Tenant"))
+	})
+
+	handler := InjectListenerName("api")(seed(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		tenant = r.Context().Value(tenantKey{})
+		listener = GetListenerName(r.Context())
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant", "acme")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "acme", tenant)
+	assert.Equal(t, "api", listener, "values from outer middlewares should be kept")
+}
+
+func TestWithContextValues_NilContextKeepsRequest(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	var got *http.Request
+
+	handler := WithContextValues(func(*http.Request) context.Context { return nil })(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) { got = r }))
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Same(t, req, got)
+}
+
+func TestWithContextValues_NilFunc(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := WithContextValues(nil)(okHandler())
+
+	require.Len(t, h.records, 1)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
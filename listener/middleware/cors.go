@@ -24,6 +24,11 @@ var (
 
 const defaultCORSMaxAge = 3600
 
+// defaultCORSMethods returns the methods allowed when none are configured.
+func defaultCORSMethods() []string {
+	return []string{"GET", "HEAD", "POST"}
+}
+
 // corsConfig holds internal configuration for the CORS middleware.
 type corsConfig struct {
 	allowedOrigins   []string
@@ -31,8 +36,11 @@ type corsConfig struct {
 	allowedHeaders   []string
 	exposedHeaders   []string
 	validateOrigins  []OriginValidator
+	normalizeOrigin  func(string) string
+	isPreflight      func(*http.Request) bool
 	allowCredentials bool
 	maxAge           int
+	preflightStatus  int
 }
 
 // CORSOption configures the CORS middleware.
@@ -53,6 +61,7 @@ func WithAllowedOrigins(origins ...string) CORSOption {
 }
 
 // WithAllowedMethods sets the allowed HTTP methods, replacing defaults.
+// Calling it with no methods keeps the defaults and logs a warning.
 func WithAllowedMethods(methods ...string) CORSOption {
 	return func(c *corsConfig) {
 		c.allowedMethods = methods
@@ -94,6 +103,114 @@ func WithOriginValidators(validators ...OriginValidator) CORSOption {
 	}
 }
 
+// WithPreflightStatus sets the status code of successful preflight responses
+// (default 204 No Content), e.g. 200 for clients or proxies that mishandle 204.
+// Codes outside 2xx fall back to 204 with a warning log.
+func WithPreflightStatus(code int) CORSOption {
+	return func(c *corsConfig) {
+		c.preflightStatus = code
+	}
+}
+
+// WithOriginNormalizer sets the function applied to the incoming Origin header before
+// it is matched against AllowedOrigins and reflected in Access-Control-Allow-Origin,
+// replacing DefaultOriginNormalizer. A nil fn disables normalization.
+func WithOriginNormalizer(fn func(string) string) CORSOption {
+	return func(c *corsConfig) {
+		c.normalizeOrigin = fn
+	}
+}
+
+// DefaultOriginNormalizer returns the normalizer CORS uses by default: it trims
+// surrounding whitespace and lowercases the scheme, so " HTTPS://example.com "
+// becomes "https://example.com". Host and port are left untouched.
+func DefaultOriginNormalizer() func(string) string {
+	return func(origin string) string {
+		origin = strings.TrimSpace(origin)
+
+		if i := strings.Index(origin, "://"); i >= 0 {
+			origin = strings.ToLower(origin[:i]) + origin[i:]
+		}
+
+		return origin
+	}
+}
+
+// WithCORSPreflightDetection replaces the check deciding whether a request is a
+// preflight, which by default is an OPTIONS request carrying an
+// Access-Control-Request-Method header. Use it behind routers that handle OPTIONS
+// themselves or strip that header, e.g. treating every OPTIONS request as a preflight:
+//
+//	middleware.CORS(middleware.WithCORSPreflightDetection(func(r *http.Request) bool {
+//		return r.Method == http.MethodOptions
+//	}))
+//
+// Preflights are answered by CORS without calling the next handler. A nil fn keeps the default.
+func WithCORSPreflightDetection(fn func(*http.Request) bool) CORSOption {
+	return func(c *corsConfig) {
+		c.isPreflight = fn
+	}
+}
+
+// isCORSPreflight is the default preflight check: an OPTIONS request with an
+// Access-Control-Request-Method header.
+func isCORSPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// CORSConfig is a declarative CORS configuration, e.g. loaded from YAML with
+// config.Provider(&middleware.CORSConfig{}, "cors"). Zero-valued fields keep the
+// CORS defaults, so MaxAge cannot be set to 0 through CORSConfig.
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers"`
+	ExposedHeaders   []string `yaml:"exposed_headers"`
+	MaxAge           int      `yaml:"max_age"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	PreflightStatus  int      `yaml:"preflight_status"`
+}
+
+// Options converts the configuration into CORS options, one per set field.
+// A nil receiver yields no options.
+func (c *CORSConfig) Options() []CORSOption {
+	if c == nil {
+		return nil
+	}
+
+	var opts []CORSOption
+
+	if len(c.AllowedOrigins) > 0 {
+		opts = append(opts, WithAllowedOrigins(c.AllowedOrigins...))
+	}
+
+	if len(c.AllowedMethods) > 0 {
+		opts = append(opts, WithAllowedMethods(c.AllowedMethods...))
+	}
+
+	if len(c.AllowedHeaders) > 0 {
+		opts = append(opts, WithAllowedHeaders(c.AllowedHeaders...))
+	}
+
+	if len(c.ExposedHeaders) > 0 {
+		opts = append(opts, WithExposedHeaders(c.ExposedHeaders...))
+	}
+
+	if c.MaxAge != 0 {
+		opts = append(opts, WithMaxAge(c.MaxAge))
+	}
+
+	if c.AllowCredentials {
+		opts = append(opts, WithAllowCredentials())
+	}
+
+	if c.PreflightStatus != 0 {
+		opts = append(opts, WithPreflightStatus(c.PreflightStatus))
+	}
+
+	return opts
+}
+
 // isFullOrigin returns true if the origin string contains a scheme (e.g., "http://example.com").
 // Bare hostnames (e.g., "example.com") return false.
 func isFullOrigin(origin string) bool {
@@ -235,15 +352,23 @@ func validateOrigin(origin string, validators []OriginValidator) bool {
 // checked first, then bare hostnames as a fallback.
 // If AllowCredentials is true with only wildcard origins and no explicit origins,
 // credentials are automatically disabled and a warning is logged.
+// An empty allowed methods list falls back to the defaults with a warning.
+// Successful preflights get 204 No Content unless WithPreflightStatus sets another 2xx code.
+// The Origin header is normalized (see DefaultOriginNormalizer) before matching.
+// Preflights are detected as OPTIONS requests with an Access-Control-Request-Method
+// header unless WithCORSPreflightDetection replaces the check.
 //
 // When called with no options, sensible defaults are applied:
 // origins ["*"], methods ["GET","HEAD","POST"], common headers, maxAge 3600.
 func CORS(opts ...CORSOption) func(http.Handler) http.Handler { //nolint:gocognit,cyclop,funlen
 	cfg := &corsConfig{
-		allowedOrigins: []string{"*"},
-		allowedMethods: []string{"GET", "HEAD", "POST"},
-		allowedHeaders: []string{"Origin", "Accept", "Content-Type", "X-Requested-With"},
-		maxAge:         defaultCORSMaxAge,
+		allowedOrigins:  []string{"*"},
+		allowedMethods:  defaultCORSMethods(),
+		allowedHeaders:  []string{"Origin", "Accept", "Content-Type", "X-Requested-With"},
+		maxAge:          defaultCORSMaxAge,
+		preflightStatus: http.StatusNoContent,
+		normalizeOrigin: DefaultOriginNormalizer(),
+		isPreflight:     isCORSPreflight,
 	}
 
 	for _, opt := range opts {
@@ -254,6 +379,26 @@ func CORS(opts ...CORSOption) func(http.Handler) http.Handler { //nolint:gocogni
 		opt(cfg)
 	}
 
+	// An empty method list would omit Access-Control-Allow-Methods from preflights
+	// and silently fail every CORS request, so fall back to the defaults instead.
+	if len(cfg.allowedMethods) == 0 {
+		cfg.allowedMethods = defaultCORSMethods()
+
+		slog.Warn("middleware: CORS allowed methods must not be empty, using defaults",
+			"default", cfg.allowedMethods)
+	}
+
+	if cfg.isPreflight == nil {
+		cfg.isPreflight = isCORSPreflight
+	}
+
+	if cfg.preflightStatus < http.StatusOK || cfg.preflightStatus >= http.StatusMultipleChoices {
+		slog.Warn("middleware: CORS preflight status must be 2xx, using default",
+			"provided", cfg.preflightStatus, "default", http.StatusNoContent)
+
+		cfg.preflightStatus = http.StatusNoContent
+	}
+
 	allowedFullOrigins := make(map[string]struct{}, len(cfg.allowedOrigins))
 	allowedHostnames := make(map[string]struct{}, len(cfg.allowedOrigins))
 	wildcard := false
@@ -303,6 +448,9 @@ func CORS(opts ...CORSOption) func(http.Handler) http.Handler { //nolint:gocogni
 			w.Header().Add("Vary", "Origin")
 
 			origin := r.Header.Get("Origin")
+			if cfg.normalizeOrigin != nil {
+				origin = cfg.normalizeOrigin(origin)
+			}
 
 			if origin == "" {
 				next.ServeHTTP(w, r)
@@ -340,7 +488,7 @@ func CORS(opts ...CORSOption) func(http.Handler) http.Handler { //nolint:gocogni
 				w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
 			}
 
-			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if cfg.isPreflight(r) {
 				w.Header().Add("Vary", "Access-Control-Request-Method")
 				w.Header().Add("Vary", "Access-Control-Request-Headers")
 
@@ -356,7 +504,7 @@ func CORS(opts ...CORSOption) func(http.Handler) http.Handler { //nolint:gocogni
 					w.Header().Set("Access-Control-Max-Age", maxAge)
 				}
 
-				w.WriteHeader(http.StatusNoContent)
+				w.WriteHeader(cfg.preflightStatus)
 
 				return
 			}
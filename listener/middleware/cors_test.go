@@ -5,8 +5,15 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/0xalexb/hjarta-di/config"
+	filefetcher "github.com/0xalexb/hjarta-di/config/fetcher/file"
+	yamlparser "github.com/0xalexb/hjarta-di/config/parser/yaml"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -769,6 +776,33 @@ func TestCORS_DefaultMethodsInPreflight(t *testing.T) {
 	assert.Equal(t, "3600", rec.Header().Get("Access-Control-Max-Age"))
 }
 
+func TestCORS_EmptyAllowedMethodsFallsBackToDefaults(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	var buf bytes.Buffer
+
+	oldDefault := slog.Default()
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	t.Cleanup(func() { slog.SetDefault(oldDefault) })
+
+	handler := CORS(WithAllowedMethods())(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("handler should not be called for preflight")
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://any-origin.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Contains(t, buf.String(), "CORS allowed methods must not be empty, using defaults")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Methods"), "POST")
+	assert.Equal(t, "GET, HEAD, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+}
+
 func TestCORS_OverrideDefaults(t *testing.T) {
 	t.Parallel()
 
@@ -1040,3 +1074,238 @@ func TestCORS_BareHostnameStillMatchesDifferentPorts(t *testing.T) {
 			"bare hostname 'localhost' should match %s", origin)
 	}
 }
+
+func TestCORSConfig_FromYAML(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`server:
+  cors:
+    allowed_origins:
+      - https://app.example.com
+      - partner.example.org
+    allowed_methods: [GET, PUT]
+    exposed_headers: [X-Request-ID]
+    max_age: 600
+    allow_credentials: true
+    preflight_status: 200
+`)
+
+	fpath := filepath.Join(t.TempDir(), "cors.yaml")
+	require.NoError(t, os.WriteFile(fpath, data, 0o600))
+
+	fetcher, err := filefetcher.NewFetcher(fpath)()
+	require.NoError(t, err)
+
+	cfg, err := config.Provider(&CORSConfig{}, "server:cors")(yamlparser.NewParser(), fetcher)
+	require.NoError(t, err)
+
+	handler := CORS(cfg.Options()...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name    string
+		origin  string
+		allowed bool
+	}{
+		{name: "full origin", origin: "https://app.example.com", allowed: true},
+		{name: "bare hostname", origin: "http://partner.example.org:8080", allowed: true},
+		{name: "other origin", origin: "https://evil.example.com", allowed: false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set("Origin", tt.origin)
+		req.Header.Set("Access-Control-Request-Method", "PUT")
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if !tt.allowed {
+			assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"), tt.name)
+
+			continue
+		}
+
+		assert.Equal(t, tt.origin, rec.Header().Get("Access-Control-Allow-Origin"), tt.name)
+		assert.Equal(t, "GET, PUT", rec.Header().Get("Access-Control-Allow-Methods"), tt.name)
+		assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"), tt.name)
+		assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"), tt.name)
+		assert.Equal(t, http.StatusOK, rec.Code, tt.name)
+	}
+}
+
+func TestCORSConfig_Options_ZeroValueKeepsDefaults(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, (&CORSConfig{}).Options())
+	assert.Nil(t, (*CORSConfig)(nil).Options())
+
+	handler := CORS((&CORSConfig{}).Options()...)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, HEAD, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "3600", rec.Header().Get("Access-Control-Max-Age"))
+}
+
+// preflight sends a CORS preflight request to handler and returns the response.
+func preflight(handler http.Handler) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestCORS_WithPreflightStatus(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("handler should not be called for preflight")
+	})
+
+	rec := preflight(CORS(WithPreflightStatus(http.StatusOK))(next))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, HEAD, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+
+	rec = preflight(CORS()(next))
+	assert.Equal(t, http.StatusNoContent, rec.Code, "default preflight status should remain 204")
+}
+
+func TestCORS_WithPreflightStatusInvalid(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	for _, code := range []int{0, 199, http.StatusMultipleChoices, http.StatusNotFound} {
+		h := setupTestLogger(t)
+
+		rec := preflight(CORS(WithPreflightStatus(code))(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+
+		assert.Equal(t, http.StatusNoContent, rec.Code, "code %d should fall back to 204", code)
+		require.Len(t, h.records, 1)
+		assert.Equal(t, int64(code), h.records[0].Attrs["provided"])
+	}
+}
+
+func TestDefaultOriginNormalizer(t *testing.T) {
+	t.Parallel()
+
+	normalize := DefaultOriginNormalizer()
+
+	tests := map[string]string{
+		"  https://example.com\t": "https://example.com",
+		"HTTPS://example.com":     "https://example.com",
+		"Http://Example.com:8080": "http://Example.com:8080",
+		" example.com ":           "example.com",
+		"":                        "",
+	}
+
+	for in, want := range tests {
+		assert.Equal(t, want, normalize(in), "normalize(%q)", in)
+	}
+}
+
+func TestCORS_NormalizesOriginBeforeMatching(t *testing.T) {
+	t.Parallel()
+
+	handler := CORS(
+		WithAllowedOrigins("https://example.com", "localhost"),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+
+	for origin, want := range map[string]string{
+		"  https://example.com  ": "https://example.com",
+		"HTTPS://example.com":     "https://example.com",
+		" HTTP://localhost:3000":  "http://localhost:3000",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", origin)
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, want, rec.Header().Get("Access-Control-Allow-Origin"), "origin %q", origin)
+	}
+}
+
+func TestCORS_WithOriginNormalizer(t *testing.T) {
+	t.Parallel()
+
+	send := func(handler http.Handler, origin string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", origin)
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		return rec.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	next := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	stripSlash := CORS(
+		WithAllowedOrigins("https://example.com"),
+		WithOriginNormalizer(func(origin string) string { return strings.TrimSuffix(origin, "/") }),
+	)(next)
+	assert.Equal(t, "https://example.com", send(stripSlash, "https://example.com/"))
+
+	disabled := CORS(
+		WithAllowedOrigins("https://example.com"),
+		WithOriginNormalizer(nil),
+	)(next)
+	assert.Empty(t, send(disabled, " https://example.com"), "nil normalizer should match the raw header")
+}
+
+func TestCORS_WithCORSPreflightDetection(t *testing.T) {
+	t.Parallel()
+
+	nextCalled := false
+
+	handler := CORS(
+		WithCORSPreflightDetection(func(*http.Request) bool { return true }),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		nextCalled = true
+	}))
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodOptions} {
+		req := httptest.NewRequest(method, "/", nil)
+		req.Header.Set("Origin", "https://example.com")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code, "%s should be answered as a preflight", method)
+		assert.Equal(t, "GET, HEAD, POST", rec.Header().Get("Access-Control-Allow-Methods"), method)
+	}
+
+	assert.False(t, nextCalled, "preflights must not reach the next handler")
+}
+
+func TestCORS_WithCORSPreflightDetectionNilKeepsDefault(t *testing.T) {
+	t.Parallel()
+
+	handler := CORS(WithCORSPreflightDetection(nil))(okHandler())
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code, "OPTIONS without Access-Control-Request-Method is not a preflight")
+
+	rec = preflight(handler)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
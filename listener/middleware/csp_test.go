@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nonceEcho writes the request nonce as the response body.
+func nonceEcho() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(CSPNonceFromContext(r.Context())))
+	})
+}
+
+func TestCSPNonce_UniquePerRequest(t *testing.T) {
+	t.Parallel()
+
+	handler := CSPNonce()(nonceEcho())
+	seen := make(map[string]struct{}, 100)
+
+	for range 100 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		nonce := rec.Body.String()
+
+		raw, err := base64.StdEncoding.DecodeString(nonce)
+		require.NoError(t, err, "nonce should be valid base64")
+		assert.Len(t, raw, cspNonceSize)
+
+		_, exists := seen[nonce]
+		assert.False(t, exists, "duplicate nonce generated: %s", nonce)
+
+		seen[nonce] = struct{}{}
+	}
+}
+
+func TestCSPNonce_WithCSPPolicy(t *testing.T) {
+	t.Parallel()
+
+	handler := CSPNonce(WithCSPPolicy("script-src 'self' 'nonce-{nonce}'"))(nonceEcho())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	nonce := rec.Body.String()
+	require.NotEmpty(t, nonce)
+	assert.Equal(t, "script-src 'self' 'nonce-"+nonce+"'", rec.Header().Get("Content-Security-Policy"))
+}
+
+func TestCSPNonce_SubstitutesPolicyFromOuterMiddleware(t *testing.T) {
+	t.Parallel()
+
+	securityHeaders := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Security-Policy", "script-src 'nonce-{nonce}'; style-src 'nonce-{nonce}'")
+			w.Header().Set("Content-Security-Policy-Report-Only", "default-src 'nonce-{nonce}'")
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	handler := Chain(securityHeaders, CSPNonce())(nonceEcho())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	nonce := rec.Body.String()
+	require.NotEmpty(t, nonce)
+	assert.Equal(t, "script-src 'nonce-"+nonce+"'; style-src 'nonce-"+nonce+"'",
+		rec.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "default-src 'nonce-"+nonce+"'", rec.Header().Get("Content-Security-Policy-Report-Only"))
+}
+
+func TestCSPNonce_NoPolicyLeavesHeaderUnset(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	CSPNonce()(nonceEcho()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Empty(t, rec.Header().Get("Content-Security-Policy"))
+}
+
+func TestCSPNonceFromContext_Empty(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, CSPNonceFromContext(context.Background()))
+}
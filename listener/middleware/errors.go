@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+type jsonErrorsKeyType struct{}
+
+var jsonErrorsKey = jsonErrorsKeyType{} //nolint:gochecknoglobals
+
+// ErrorResponse is the JSON body written by WriteError.
+type ErrorResponse struct {
+	Error     string `json:"error"`
+	Status    int    `json:"status"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// WriteError writes status with a JSON ErrorResponse body containing message and the
+// request ID from the context (see RequestID), if any. Like http.Error, it sets the
+// Content-Type and X-Content-Type-Options headers and drops any Content-Length.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_, _ = w.Write(errorBody(r.Context(), status, message))
+}
+
+// JSONErrors returns a middleware that switches the error responses of the built-in
+// RateLimit, RateLimitByMethod, NewLRURateLimiter, PerIPRateLimit, ConcurrencyLimit,
+// MaxURLLength, MaxHeaders, BindQuery, Idempotency, IPFilter, MultipartLimits,
+// ValidateJSONSchema, Timeout, StreamingTimeout and Recovery middlewares it wraps from
+// plain text (http.Error) to the JSON format of WriteError. Place it inside RequestID
+// and outside the middlewares whose errors should be JSON, e.g.
+// Chain(RequestID(), JSONErrors(), Recovery(), Timeout(d)).
+func JSONErrors() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), jsonErrorsKey, true)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// jsonErrorsEnabled reports whether JSONErrors wraps the request.
+func jsonErrorsEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(jsonErrorsKey).(bool)
+
+	return enabled
+}
+
+// writeMiddlewareError responds with status and message using WriteError when JSONErrors
+// is enabled for the request and http.Error otherwise.
+func writeMiddlewareError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if jsonErrorsEnabled(r.Context()) {
+		WriteError(w, r, status, message)
+
+		return
+	}
+
+	http.Error(w, message, status)
+}
+
+// errorBody returns the JSON ErrorResponse for status and message, terminated by a newline.
+func errorBody(ctx context.Context, status int, message string) []byte {
+	body, err := json.Marshal(ErrorResponse{Error: message, Status: status, RequestID: GetRequestID(ctx)})
+	if err != nil {
+		return []byte("{}\n")
+	}
+
+	return append(body, '\n')
+}
+
+// jsonTimeoutWriter labels the JSON body that http.TimeoutHandler writes on timeout.
+// TimeoutHandler writes its error body straight to the underlying writer without a
+// Content-Type, so the status is held until the first Write: if that write is exactly
+// the expected JSON error body and no Content-Type is set, the JSON headers are added.
+type jsonTimeoutWriter struct {
+	http.ResponseWriter
+
+	body       []byte
+	status     int
+	headerSent bool
+}
+
+func (w *jsonTimeoutWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *jsonTimeoutWriter) Write(b []byte) (int, error) {
+	if !w.headerSent && w.status == http.StatusServiceUnavailable && bytes.Equal(b, w.body) &&
+		w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+	}
+
+	w.flushHeader()
+
+	return w.ResponseWriter.Write(b)
+}
+
+// flushHeader sends the held status, if any, once.
+func (w *jsonTimeoutWriter) flushHeader() {
+	if w.headerSent || w.status == 0 {
+		return
+	}
+
+	w.headerSent = true
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *jsonTimeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeErrorResponse asserts a JSON error response and returns its body.
+func decodeErrorResponse(t *testing.T, rec *httptest.ResponseRecorder, status int) ErrorResponse {
+	t.Helper()
+
+	require.Equal(t, status, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+
+	var body ErrorResponse
+
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), "body: %q", rec.Body.String())
+	assert.Equal(t, status, body.Status)
+
+	return body
+}
+
+// requestWithID returns a GET request carrying an incoming X-Request-ID.
+func requestWithID(id string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, id)
+
+	return req
+}
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "42")
+		WriteError(w, r, http.StatusRequestEntityTooLarge, "Request Entity Too Large")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, requestWithID("req-413"))
+
+	body := decodeErrorResponse(t, rec, http.StatusRequestEntityTooLarge)
+	assert.Equal(t, "Request Entity Too Large", body.Error)
+	assert.Equal(t, "req-413", body.RequestID)
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+}
+
+func TestWriteError_WithoutRequestID(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusBadRequest, "bad")
+
+	decodeErrorResponse(t, rec, http.StatusBadRequest)
+	assert.JSONEq(t, `{"error":"bad","status":400}`, rec.Body.String())
+}
+
+func TestJSONErrors_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	handler := Chain(RequestID(), JSONErrors(), RateLimit(1, 1))(okHandler())
+
+	handler.ServeHTTP(httptest.NewRecorder(), requestWithID("first"))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, requestWithID("req-429"))
+
+	body := decodeErrorResponse(t, rec, http.StatusTooManyRequests)
+	assert.Equal(t, "Too Many Requests", body.Error)
+	assert.Equal(t, "req-429", body.RequestID)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+}
+
+func TestJSONErrors_Timeout(t *testing.T) {
+	t.Parallel()
+
+	handler := Chain(RequestID(), JSONErrors(), Timeout(10*time.Millisecond))(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, requestWithID("req-503"))
+
+	body := decodeErrorResponse(t, rec, http.StatusServiceUnavailable)
+	assert.Equal(t, "Service Unavailable", body.Error)
+	assert.Equal(t, "req-503", body.RequestID)
+}
+
+func TestJSONErrors_TimeoutLeavesHandlerResponse(t *testing.T) {
+	t.Parallel()
+
+	handler := Chain(RequestID(), JSONErrors(), Timeout(time.Second))(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("down for maintenance"))
+		}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, requestWithID("req-ok"))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "down for maintenance", rec.Body.String())
+	assert.NotEqual(t, "application/json", rec.Header().Get("Content-Type"),
+		"the handler's own 503 must not be labelled as a JSON error")
+}
+
+func TestJSONErrors_Recovery(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	setupTestLogger(t)
+
+	handler := Chain(RequestID(), JSONErrors(), Recovery())(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("boom")
+		}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, requestWithID("req-500"))
+
+	body := decodeErrorResponse(t, rec, http.StatusInternalServerError)
+	assert.Equal(t, "Internal Server Error", body.Error)
+	assert.Equal(t, "req-500", body.RequestID)
+}
+
+func TestJSONErrors_DisabledKeepsPlainText(t *testing.T) {
+	t.Parallel()
+
+	handler := Chain(RequestID(), RateLimit(1, 1))(okHandler())
+
+	handler.ServeHTTP(httptest.NewRecorder(), requestWithID("first"))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, requestWithID("second"))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Too Many Requests\n", rec.Body.String())
+}
@@ -0,0 +1,36 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/0xalexb/hjarta-di/listener/middleware"
+)
+
+// Some routers answer or rewrite OPTIONS requests before the middleware chain and drop
+// the Access-Control-Request-Method header. Treating every OPTIONS request that reaches
+// CORS as a preflight keeps preflights working behind them.
+func ExampleWithCORSPreflightDetection() {
+	handler := middleware.CORS(
+		middleware.WithAllowedOrigins("https://app.example.com"),
+		middleware.WithCORSPreflightDetection(func(r *http.Request) bool {
+			return r.Method == http.MethodOptions
+		}),
+	)(http.NotFoundHandler())
+
+	// A preflight whose Access-Control-Request-Method header was stripped by the router.
+	req := httptest.NewRequest(http.MethodOptions, "/api/items", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	fmt.Println(rec.Code)
+	fmt.Println(rec.Header().Get("Access-Control-Allow-Origin"))
+	fmt.Println(rec.Header().Get("Access-Control-Allow-Methods"))
+	// Output:
+	// 204
+	// https://app.example.com
+	// GET, HEAD, POST
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultHedgeThreshold   = 100 * time.Millisecond
+	defaultHedgeMaxAttempts = 2
+)
+
+// hedgeConfig holds configuration for the Hedge middleware.
+type hedgeConfig struct {
+	maxAttempts int
+}
+
+// HedgeOption configures the Hedge middleware.
+type HedgeOption func(*hedgeConfig)
+
+// WithHedgeMaxAttempts sets the maximum number of concurrent executions of a request,
+// including the original one (default 2). A value of 1 disables hedging.
+// Values below 1 fall back to the default with a warning log.
+func WithHedgeMaxAttempts(n int) HedgeOption {
+	return func(c *hedgeConfig) {
+		c.maxAttempts = n
+	}
+}
+
+// hedgeResult is the outcome of one attempt: its buffered response, or the value
+// it panicked with.
+type hedgeResult struct {
+	res      *flightResponse
+	panicked any
+}
+
+// Hedge returns a middleware that reduces tail latency by running a GET or HEAD request
+// again when the handler has not responded within threshold. Each further attempt
+// starts threshold after the previous one, up to the WithHedgeMaxAttempts limit. The
+// first attempt to finish wins: its buffered status, headers and body are sent and the
+// contexts of the other attempts are cancelled. Other methods bypass hedging.
+//
+// Only use it for idempotent handlers, since a request may run more than once. Each
+// attempt gets a clone of the request whose context is cancelled once a winner is
+// chosen; responses are buffered in full, which makes the middleware unsuitable for
+// streaming. If the winning attempt
+// panics, the panic is re-raised in the request goroutine so Recovery can handle it.
+// If threshold is not positive, it defaults to 100ms with a warning log.
+func Hedge(threshold time.Duration, opts ...HedgeOption) func(http.Handler) http.Handler {
+	if threshold <= 0 {
+		slog.Warn("middleware: threshold must be positive, using default",
+			"provided", threshold, "default", defaultHedgeThreshold)
+
+		threshold = defaultHedgeThreshold
+	}
+
+	cfg := hedgeConfig{maxAttempts: defaultHedgeMaxAttempts}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	if cfg.maxAttempts < 1 {
+		slog.Warn("middleware: maxAttempts must be positive, using default",
+			"provided", cfg.maxAttempts, "default", defaultHedgeMaxAttempts)
+
+		cfg.maxAttempts = defaultHedgeMaxAttempts
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.maxAttempts == 1 || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			result, ok := hedgeRequest(next, r, threshold, cfg.maxAttempts)
+			if !ok {
+				// The client went away before any attempt finished.
+				return
+			}
+
+			if result.panicked != nil {
+				panic(result.panicked)
+			}
+
+			result.res.replay(w)
+		})
+	}
+}
+
+// hedgeRequest runs attempts of r against next, starting a new one every threshold
+// until maxAttempts are running, and returns the first result. It reports false if the
+// request context is done first. All attempts are cancelled on return.
+func hedgeRequest(next http.Handler, r *http.Request, threshold time.Duration, maxAttempts int) (hedgeResult, bool) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// Buffered so attempts that lose the race never block.
+	results := make(chan hedgeResult, maxAttempts)
+
+	launch := func() {
+		req := r.Clone(ctx)
+
+		go func() {
+			results <- runAttempt(next, req)
+		}()
+	}
+
+	launch()
+
+	launched := 1
+
+	timer := time.NewTimer(threshold)
+	defer timer.Stop()
+
+	for {
+		select {
+		case result := <-results:
+			return result, true
+		case <-timer.C:
+			launch()
+
+			launched++
+			if launched < maxAttempts {
+				timer.Reset(threshold)
+			}
+		case <-r.Context().Done():
+			return hedgeResult{}, false
+		}
+	}
+}
+
+// runAttempt serves req into a recorder, capturing a panic instead of crashing the
+// attempt's goroutine.
+func runAttempt(next http.Handler, req *http.Request) (result hedgeResult) {
+	defer func() {
+		if p := recover(); p != nil {
+			result = hedgeResult{panicked: p}
+		}
+	}()
+
+	rec := &flightRecorder{header: make(http.Header)}
+	next.ServeHTTP(rec, req)
+
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	return hedgeResult{res: &flightResponse{status: rec.status, header: rec.header, body: rec.body.Bytes()}}
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stallingHandler stalls the first `stalls` attempts until their context is cancelled,
+// counting cancellations, and answers later attempts immediately with their number.
+func stallingHandler(stalls int32, attempts, cancelled *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := attempts.Add(1)
+		if attempt <= stalls {
+			<-r.Context().Done()
+			cancelled.Add(1)
+
+			return
+		}
+
+		w.Header().Set("X-Attempt", strconv.Itoa(int(attempt)))
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("hedged"))
+	})
+}
+
+func TestHedge_SlowFirstAttempt(t *testing.T) {
+	t.Parallel()
+
+	var attempts, cancelled atomic.Int32
+
+	handler := Hedge(20 * time.Millisecond)(stallingHandler(1, &attempts, &cancelled))
+
+	for range 20 {
+		attempts.Store(0)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// The first attempt never finishes on its own, so the response must come from the hedge.
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("X-Attempt"), "response should come from the second attempt")
+		assert.Equal(t, int32(2), attempts.Load(), "exactly one hedge should have been started")
+		assert.Equal(t, "hedged", rr.Body.String())
+	}
+
+	assert.Eventually(t, func() bool { return cancelled.Load() == 20 }, time.Second, time.Millisecond,
+		"losing attempts should be cancelled")
+}
+
+func TestHedge_FastResponseIsNotHedged(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+
+	handler := Hedge(50 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	time.Sleep(70 * time.Millisecond)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestHedge_WithHedgeMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	var attempts, cancelled atomic.Int32
+
+	handler := Hedge(10*time.Millisecond, WithHedgeMaxAttempts(3))(stallingHandler(2, &attempts, &cancelled))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, "3", rr.Header().Get("X-Attempt"))
+	assert.Eventually(t, func() bool { return cancelled.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestHedge_MaxAttemptsLimitsHedges(t *testing.T) {
+	t.Parallel()
+
+	var attempts, cancelled atomic.Int32
+
+	// Three stalled attempts but at most two run, so only the client giving up ends the request.
+	handler := Hedge(5 * time.Millisecond)(stallingHandler(3, &attempts, &cancelled))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, cancel := context.WithTimeout(req.Context(), 50*time.Millisecond)
+	t.Cleanup(cancel)
+
+	handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+	assert.Equal(t, int32(2), attempts.Load())
+	assert.Eventually(t, func() bool { return cancelled.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestHedge_NonIdempotentMethodsBypass(t *testing.T) {
+	t.Parallel()
+
+	var attempts, cancelled atomic.Int32
+
+	handler := Hedge(time.Millisecond)(stallingHandler(0, &attempts, &cancelled))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	assert.Equal(t, "1", rr.Header().Get("X-Attempt"))
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestHedge_PanicPropagates(t *testing.T) {
+	t.Parallel()
+
+	handler := Hedge(time.Second)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestHedge_InvalidArguments(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Hedge(0, WithHedgeMaxAttempts(0))(okHandler())
+
+	require.Len(t, h.records, 2)
+	assert.Equal(t, int64(defaultHedgeMaxAttempts), h.records[1].Attrs["default"])
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,349 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set to "true" on responses replayed from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	defaultIdempotencyTTL = 24 * time.Hour
+
+	defaultIdempotencyMaxBodySize = 1 << 20 // 1MB
+
+	// maxIdempotencyKeyLength is the maximum accepted length of an idempotency key.
+	maxIdempotencyKeyLength = 256
+
+	// memoryIdempotencySweepInterval is how often MemoryIdempotencyStore.Set drops
+	// expired entries.
+	memoryIdempotencySweepInterval = time.Minute
+)
+
+// IdempotentResponse is a recorded response replayed for repeated idempotency keys.
+type IdempotentResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore persists recorded responses by key. Back it with memory (see
+// NewMemoryIdempotencyStore) or a shared cache such as Redis when running several
+// instances. Get reports false for unknown or expired keys.
+type IdempotencyStore interface {
+	Get(ctx context.Context, key string) (*IdempotentResponse, bool, error)
+	Set(ctx context.Context, key string, res *IdempotentResponse, ttl time.Duration) error
+}
+
+// idempotencyConfig holds configuration for the Idempotency middleware.
+type idempotencyConfig struct {
+	ttl         time.Duration
+	maxBodySize int
+	keyScope    func(r *http.Request) string
+}
+
+// IdempotencyOption configures the Idempotency middleware.
+type IdempotencyOption func(*idempotencyConfig)
+
+// WithIdempotencyTTL sets how long recorded responses are kept (default 24h).
+// Non-positive values fall back to the default with a warning log.
+func WithIdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithIdempotencyMaxBodySize sets the largest response body that is recorded
+// (default 1MB). Larger responses are sent normally but not recorded.
+// Non-positive values fall back to the default with a warning log.
+func WithIdempotencyMaxBodySize(n int) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.maxBodySize = n
+	}
+}
+
+// WithIdempotencyKeyScope scopes idempotency keys with fn's result for the request,
+// typically the authenticated client or tenant ID, so clients choosing the same key do
+// not get each other's responses. By default keys are shared by all clients.
+// A nil fn keeps the default.
+func WithIdempotencyKeyScope(fn func(r *http.Request) string) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		if fn != nil {
+			c.keyScope = fn
+		}
+	}
+}
+
+// Idempotency returns a middleware that makes POST, PUT and PATCH requests carrying an
+// Idempotency-Key header safe to retry. The first request with a key runs the handler
+// and its response is recorded in store; a later request with the same method, path
+// and key gets the recorded status, headers and body replayed, with the
+// Idempotent-Replayed header set, without running the handler. Only headers the
+// handler set are recorded: those already set by outer middlewares, such as CORS
+// headers, and X-Request-ID are left to the replaying request. Keys are not scoped per
+// client unless WithIdempotencyKeyScope is used.
+//
+// Responses with a 5xx status or a body larger than WithIdempotencyMaxBodySize are not
+// recorded, so failed requests can be retried.
+// Keys longer than 256 characters or with non-printable characters are rejected with
+// 400 Bad Request (a JSON ErrorResponse inside JSONErrors). Requests without a key and
+// other methods pass through. Store errors are logged and the request is handled as
+// if the key were new. While a request is running, the key is reserved: a concurrent
+// request with the same method, path and key gets 409 Conflict instead of running the
+// handler again. Reservations are kept in the middleware instance, so they coordinate
+// requests within one process only.
+// If store is nil, the middleware passes every request through with a warning log.
+func Idempotency(store IdempotencyStore, opts ...IdempotencyOption) func(http.Handler) http.Handler {
+	cfg := idempotencyConfig{ttl: defaultIdempotencyTTL, maxBodySize: defaultIdempotencyMaxBodySize}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	if cfg.ttl <= 0 {
+		slog.Warn("middleware: idempotency TTL must be positive, using default",
+			"provided", cfg.ttl, "default", defaultIdempotencyTTL)
+
+		cfg.ttl = defaultIdempotencyTTL
+	}
+
+	if cfg.maxBodySize <= 0 {
+		slog.Warn("middleware: maxBodySize must be positive, using default",
+			"provided", cfg.maxBodySize, "default", defaultIdempotencyMaxBodySize)
+
+		cfg.maxBodySize = defaultIdempotencyMaxBodySize
+	}
+
+	if store == nil {
+		slog.Warn("middleware: idempotency store is nil, idempotency keys are ignored")
+	}
+
+	var inFlight idempotencyReservations
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if store == nil || key == "" || !isWriteMethod(r.Method) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength || !isPrintableASCII(key) {
+				writeMiddlewareError(w, r, http.StatusBadRequest, "invalid "+IdempotencyKeyHeader+" header")
+
+				return
+			}
+
+			storeKey := r.Method + " " + r.URL.Path + " " + key
+			if cfg.keyScope != nil {
+				storeKey = cfg.keyScope(r) + " " + storeKey
+			}
+
+			if !inFlight.reserve(storeKey) {
+				writeMiddlewareError(w, r, http.StatusConflict, "request with this "+IdempotencyKeyHeader+" is in progress")
+
+				return
+			}
+
+			defer inFlight.release(storeKey)
+
+			res, found, err := store.Get(r.Context(), storeKey)
+			if err != nil {
+				slog.Error("middleware: idempotency store lookup failed", "key", key, "error", err)
+			}
+
+			if found && res != nil {
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				(&flightResponse{status: res.Status, header: res.Header, body: res.Body}).replay(w)
+
+				return
+			}
+
+			rec := &idempotencyRecorder{ResponseWriter: w, before: w.Header().Clone(), maxBodySize: cfg.maxBodySize}
+			next.ServeHTTP(rec, r)
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+				rec.header = rec.handlerHeader()
+			}
+
+			if rec.status >= http.StatusInternalServerError || rec.overflow {
+				return
+			}
+
+			err = store.Set(r.Context(), storeKey,
+				&IdempotentResponse{Status: rec.status, Header: rec.header, Body: rec.body}, cfg.ttl)
+			if err != nil {
+				slog.Error("middleware: idempotency store update failed", "key", key, "error", err)
+			}
+		})
+	}
+}
+
+// idempotencyReservations tracks the keys of requests that are running.
+type idempotencyReservations struct {
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+// reserve marks key as running, reporting false if it already was.
+func (res *idempotencyReservations) reserve(key string) bool {
+	res.mu.Lock()
+	defer res.mu.Unlock()
+
+	if _, ok := res.keys[key]; ok {
+		return false
+	}
+
+	if res.keys == nil {
+		res.keys = make(map[string]struct{})
+	}
+
+	res.keys[key] = struct{}{}
+
+	return true
+}
+
+func (res *idempotencyReservations) release(key string) {
+	res.mu.Lock()
+	defer res.mu.Unlock()
+
+	delete(res.keys, key)
+}
+
+// idempotencyRecorder passes the response through while keeping a copy of it, up to
+// maxBodySize bytes of body. before holds the headers set before the handler ran.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+
+	before      http.Header
+	status      int
+	header      http.Header
+	body        []byte
+	maxBodySize int
+	overflow    bool
+}
+
+func (rec *idempotencyRecorder) WriteHeader(code int) {
+	if rec.status == 0 {
+		rec.status = code
+		rec.header = rec.handlerHeader()
+	}
+
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// handlerHeader returns a copy of the headers the handler added or changed, leaving
+// out the request ID, which belongs to each request.
+func (rec *idempotencyRecorder) handlerHeader() http.Header {
+	header := make(http.Header)
+
+	for key, values := range rec.Header() {
+		if key == RequestIDHeader || slices.Equal(values, rec.before[key]) {
+			continue
+		}
+
+		header[key] = slices.Clone(values)
+	}
+
+	return header
+}
+
+func (rec *idempotencyRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+
+	if !rec.overflow {
+		if len(rec.body)+len(b) > rec.maxBodySize {
+			rec.overflow = true
+			rec.body = nil
+		} else {
+			rec.body = append(rec.body, b...)
+		}
+	}
+
+	return rec.ResponseWriter.Write(b) //nolint:wrapcheck
+}
+
+// Unwrap returns the underlying ResponseWriter, allowing http.ResponseController
+// to access interfaces like http.Flusher through the wrapper chain.
+func (rec *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// memoryIdempotencyEntry is a recorded response with its expiry time.
+type memoryIdempotencyEntry struct {
+	res       *IdempotentResponse
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore. Expired entries are
+// dropped when looked up, and swept by Set at most once a minute.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+	timeNow   func() time.Time
+}
+
+// NewMemoryIdempotencyStore returns an empty in-memory IdempotencyStore, suitable for
+// a single instance or for tests.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries: make(map[string]memoryIdempotencyEntry),
+		timeNow: time.Now,
+	}
+}
+
+// Get returns the response stored under key, if it has not expired.
+func (s *MemoryIdempotencyStore) Get(_ context.Context, key string) (*IdempotentResponse, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if !s.timeNow().Before(entry.expiresAt) {
+		delete(s.entries, key)
+
+		return nil, false, nil
+	}
+
+	return entry.res, true, nil
+}
+
+// Set stores res under key for ttl, dropping expired entries if the last sweep was
+// over a minute ago.
+func (s *MemoryIdempotencyStore) Set(_ context.Context, key string, res *IdempotentResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.timeNow()
+
+	if now.Sub(s.lastSweep) >= memoryIdempotencySweepInterval {
+		s.lastSweep = now
+
+		maps.DeleteFunc(s.entries, func(_ string, entry memoryIdempotencyEntry) bool {
+			return !now.Before(entry.expiresAt)
+		})
+	}
+
+	s.entries[key] = memoryIdempotencyEntry{res: res, expiresAt: now.Add(ttl)}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCreateHandler responds 201 with a body numbering how often it ran.
+func countingCreateHandler(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		*calls++
+
+		w.Header().Set("X-Order", fmt.Sprintf("order-%d", *calls))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(fmt.Sprintf("created %d", *calls)))
+	})
+}
+
+func idempotentRequest(method, path, key string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	return req
+}
+
+func TestIdempotency_FirstRequestRecordsResponse(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	store := NewMemoryIdempotencyStore()
+	handler := Idempotency(store)(countingCreateHandler(&calls))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "created 1", rr.Body.String())
+	assert.Empty(t, rr.Header().Get(IdempotentReplayedHeader))
+
+	res, found, err := store.Get(context.Background(), "POST /orders key-1")
+	require.NoError(t, err)
+	require.True(t, found, "first response should be recorded")
+	assert.Equal(t, http.StatusCreated, res.Status)
+	assert.Equal(t, "order-1", res.Header.Get("X-Order"))
+	assert.Equal(t, "created 1", string(res.Body))
+}
+
+func TestIdempotency_ReplaysDuplicate(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := Idempotency(NewMemoryIdempotencyStore())(countingCreateHandler(&calls))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+
+	assert.Equal(t, 1, calls, "duplicate should not run the handler")
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, "created 1", second.Body.String())
+	assert.Equal(t, "order-1", second.Header().Get("X-Order"))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_KeysAreScopedToMethodAndPath(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := Idempotency(NewMemoryIdempotencyStore())(countingCreateHandler(&calls))
+
+	for _, req := range []*http.Request{
+		idempotentRequest(http.MethodPost, "/orders", "key-1"),
+		idempotentRequest(http.MethodPut, "/orders", "key-1"),
+		idempotentRequest(http.MethodPost, "/payments", "key-1"),
+		idempotentRequest(http.MethodPost, "/orders", "key-2"),
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, 4, calls)
+}
+
+func TestIdempotency_PassesThrough(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		req  func() *http.Request
+	}{
+		{name: "missing key", req: func() *http.Request {
+			return idempotentRequest(http.MethodPost, "/orders", "")
+		}},
+		{name: "GET request", req: func() *http.Request {
+			return idempotentRequest(http.MethodGet, "/orders", "key-1")
+		}},
+		{name: "DELETE request", req: func() *http.Request {
+			return idempotentRequest(http.MethodDelete, "/orders", "key-1")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := 0
+			handler := Idempotency(NewMemoryIdempotencyStore())(countingCreateHandler(&calls))
+
+			handler.ServeHTTP(httptest.NewRecorder(), tt.req())
+			handler.ServeHTTP(httptest.NewRecorder(), tt.req())
+
+			assert.Equal(t, 2, calls)
+		})
+	}
+}
+
+func TestIdempotency_ServerErrorsAreNotRecorded(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := Idempotency(NewMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	for range 2 {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+		assert.Equal(t, http.StatusBadGateway, rr.Code)
+	}
+
+	assert.Equal(t, 2, calls, "failed requests should be retried")
+}
+
+func TestIdempotency_InvalidKey(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := Idempotency(NewMemoryIdempotencyStore())(countingCreateHandler(&calls))
+
+	for _, key := range []string{strings.Repeat("k", maxIdempotencyKeyLength+1), "key\x01"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, idempotentRequest(http.MethodPost, "/orders", key))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+
+	assert.Zero(t, calls)
+}
+
+func TestIdempotency_ExpiredEntryRunsAgain(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryIdempotencyStore()
+	store.timeNow = func() time.Time { return now }
+
+	calls := 0
+	handler := Idempotency(store, WithIdempotencyTTL(time.Minute))(countingCreateHandler(&calls))
+
+	handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest(http.MethodPost, "/orders", "key-1"))
+
+	now = now.Add(time.Minute)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "created 2", rr.Body.String())
+}
+
+func TestIdempotency_InvalidArguments(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Idempotency(nil, WithIdempotencyTTL(-time.Second), WithIdempotencyMaxBodySize(0), nil)(okHandler())
+
+	require.Len(t, h.records, 3)
+	assert.Equal(t, defaultIdempotencyTTL, h.records[0].Attrs["default"])
+	assert.Equal(t, int64(defaultIdempotencyMaxBodySize), h.records[1].Attrs["default"])
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestIdempotency_ConcurrentDuplicateConflicts(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+
+	handler := Idempotency(NewMemoryIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		close(started)
+		<-release
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		handler.ServeHTTP(first, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+	}()
+
+	<-started
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+	assert.Equal(t, http.StatusConflict, rr.Code, "duplicate should not run while the first is in flight")
+
+	close(release)
+	<-done
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "true", rr.Header().Get(IdempotentReplayedHeader), "finished request should be replayed")
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_LargeBodyNotRecorded(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	store := NewMemoryIdempotencyStore()
+	handler := Idempotency(store, WithIdempotencyMaxBodySize(4))(countingCreateHandler(&calls))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, idempotentRequest(http.MethodPost, "/orders", "key-1"))
+	assert.Equal(t, "created 1", rr.Body.String(), "large bodies are still sent")
+
+	_, found, err := store.Get(context.Background(), "POST /orders key-1")
+	require.NoError(t, err)
+	assert.False(t, found, "bodies over the limit should not be recorded")
+}
+
+func TestIdempotency_RecordsOnlyHandlerHeaders(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	store := NewMemoryIdempotencyStore()
+	handler := Chain(
+		RequestID(),
+		CORS(WithAllowedOrigins("https://a.example", "https://b.example")),
+		Idempotency(store),
+	)(countingCreateHandler(&calls))
+
+	send := func(origin string) *httptest.ResponseRecorder {
+		req := idempotentRequest(http.MethodPost, "/orders", "key-1")
+		req.Header.Set("Origin", origin)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	first := send("https://a.example")
+	second := send("https://b.example")
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "order-1", second.Header().Get("X-Order"), "handler headers should be replayed")
+	assert.Equal(t, "https://b.example", second.Header().Get("Access-Control-Allow-Origin"),
+		"outer middleware headers should belong to the replaying request")
+	assert.NotEqual(t, first.Header().Get(RequestIDHeader), second.Header().Get(RequestIDHeader))
+
+	res, found, err := store.Get(context.Background(), "POST /orders key-1")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, http.Header{"X-Order": {"order-1"}}, res.Header)
+}
+
+func TestIdempotency_WithIdempotencyKeyScope(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := Idempotency(NewMemoryIdempotencyStore(), WithIdempotencyKeyScope(func(r *http.Request) string {
+		return r.Header.Get("X-Client")
+	}))(countingCreateHandler(&calls))
+
+	for _, client := range []string{"alice", "bob", "alice"} {
+		req := idempotentRequest(http.MethodPost, "/orders", "key-1")
+		req.Header.Set("X-Client", client)
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, 2, calls, "each client should get its own key space")
+}
+
+func TestMemoryIdempotencyStore_SweepsOnInterval(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryIdempotencyStore()
+	store.timeNow = func() time.Time { return now }
+
+	ctx := context.Background()
+	res := &IdempotentResponse{Status: http.StatusCreated}
+
+	require.NoError(t, store.Set(ctx, "a", res, time.Second))
+
+	now = now.Add(2 * time.Second)
+	require.NoError(t, store.Set(ctx, "b", res, time.Hour))
+	assert.Len(t, store.entries, 2, "expired entries should wait for the next sweep")
+
+	now = now.Add(memoryIdempotencySweepInterval)
+	require.NoError(t, store.Set(ctx, "c", res, time.Hour))
+	assert.Len(t, store.entries, 2, "the sweep should drop the expired entry")
+	assert.NotContains(t, store.entries, "a")
+}
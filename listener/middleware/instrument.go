@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// NamedMiddleware pairs a middleware with a name used when reporting telemetry.
+type NamedMiddleware struct {
+	Name       string
+	Middleware func(http.Handler) http.Handler
+}
+
+// instrumentKey identifies the downstream duration slot of one instrumented middleware.
+// Each entry allocates its own key, so nested or repeated chains never collide.
+type instrumentKey struct {
+	name string
+}
+
+// Instrument returns a middleware that reports how long the wrapped handler takes,
+// including everything downstream of it. Place it directly before a middleware in a
+// Chain to time that middleware and the rest of the chain. If histogram is nil, the
+// middleware is a no-op pass-through and a warning is logged with the given name.
+func Instrument(name string, histogram func(duration time.Duration)) func(http.Handler) http.Handler {
+	if histogram == nil {
+		slog.Warn("middleware: Instrument histogram is nil, skipping instrumentation", "name", name)
+
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			defer func() {
+				histogram(time.Since(start))
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// InstrumentedChain composes middlewares like Chain and reports the time spent in
+// each one to observe, tagged with its name. The reported duration excludes time spent
+// downstream of the middleware (later middlewares and the final handler), so it isolates
+// the latency added by that middleware alone. Entries with a nil Middleware are skipped;
+// a nil observe makes it equivalent to Chain.
+func InstrumentedChain(
+	middlewares []NamedMiddleware,
+	observe func(name string, dur time.Duration),
+) func(http.Handler) http.Handler {
+	wrapped := make([]func(http.Handler) http.Handler, 0, len(middlewares))
+
+	for _, entry := range middlewares {
+		if entry.Middleware == nil {
+			continue
+		}
+
+		if observe == nil {
+			wrapped = append(wrapped, entry.Middleware)
+
+			continue
+		}
+
+		wrapped = append(wrapped, instrumentExclusive(entry, observe))
+	}
+
+	return Chain(wrapped...)
+}
+
+// instrumentExclusive wraps a single middleware so that the time spent in next is
+// subtracted from its reported duration.
+func instrumentExclusive(
+	entry NamedMiddleware,
+	observe func(name string, dur time.Duration),
+) func(http.Handler) http.Handler {
+	key := &instrumentKey{name: entry.Name}
+
+	return func(next http.Handler) http.Handler {
+		inner := entry.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			defer func() {
+				if downstream, ok := r.Context().Value(key).(*atomic.Int64); ok {
+					downstream.Add(int64(time.Since(start)))
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		}))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Atomic because middlewares such as Timeout may run next in another goroutine.
+			var downstream atomic.Int64
+
+			start := time.Now()
+
+			defer func() {
+				observe(entry.Name, time.Since(start)-time.Duration(downstream.Load()))
+			}()
+
+			inner.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), key, &downstream)))
+		})
+	}
+}
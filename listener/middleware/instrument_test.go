@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sleepMiddleware returns a middleware that sleeps for d before calling next.
+func sleepMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(d)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestInstrument_ReportsDuration(t *testing.T) {
+	t.Parallel()
+
+	var observed []time.Duration
+
+	handler := Chain(
+		Instrument("slow", func(d time.Duration) { observed = append(observed, d) }),
+		sleepMiddleware(5*time.Millisecond),
+	)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, observed, 1)
+	assert.GreaterOrEqual(t, observed[0], 5*time.Millisecond)
+}
+
+func TestInstrument_NilHistogram(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	called := false
+	handler := Instrument("noop", nil)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		called = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, called)
+	require.Len(t, h.records, 1)
+	assert.Equal(t, "noop", h.records[0].Attrs["name"])
+}
+
+func TestInstrumentedChain_ObservesEachMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		observed = map[string]time.Duration{}
+		order    []string
+	)
+
+	observe := func(name string, dur time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		observed[name] = dur
+		order = append(order, name)
+	}
+
+	handler := InstrumentedChain([]NamedMiddleware{
+		{Name: "fast", Middleware: sleepMiddleware(time.Millisecond)},
+		{Name: "slow", Middleware: sleepMiddleware(20 * time.Millisecond)},
+		{Name: "skipped", Middleware: nil},
+		{Name: "recovery", Middleware: Recovery()},
+	}, observe)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	// Innermost middlewares finish first.
+	assert.Equal(t, []string{"recovery", "slow", "fast"}, order)
+
+	for _, name := range []string{"fast", "slow", "recovery"} {
+		assert.Positive(t, observed[name], "duration for %s should be positive", name)
+	}
+
+	// Durations exclude downstream time: "fast" must not include the slow middleware or the handler.
+	assert.GreaterOrEqual(t, observed["slow"], 20*time.Millisecond)
+	assert.Less(t, observed["fast"], 20*time.Millisecond)
+	assert.Less(t, observed["recovery"], 20*time.Millisecond)
+}
+
+func TestInstrumentedChain_ShortCircuitAttributesAllTime(t *testing.T) {
+	t.Parallel()
+
+	observed := map[string]time.Duration{}
+
+	reject := func(_ http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			time.Sleep(2 * time.Millisecond)
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+
+	handler := InstrumentedChain([]NamedMiddleware{
+		{Name: "reject", Middleware: reject},
+		{Name: "never", Middleware: sleepMiddleware(time.Millisecond)},
+	}, func(name string, dur time.Duration) {
+		observed[name] = dur
+	})(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("handler should not be called")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.GreaterOrEqual(t, observed["reject"], 2*time.Millisecond)
+	assert.NotContains(t, observed, "never")
+}
+
+func TestInstrumentedChain_NilObserve(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	handler := InstrumentedChain([]NamedMiddleware{
+		{Name: "recovery", Middleware: Recovery()},
+	}, nil)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		called = true
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, called)
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// errInvalidCIDR is reported when an IPFilter option is given an entry that is neither
+// a CIDR nor an IP address.
+var errInvalidCIDR = errors.New("invalid CIDR or IP address")
+
+// ipFilterConfig holds configuration for the IPFilter middleware.
+type ipFilterConfig struct {
+	allow   []netip.Prefix
+	deny    []netip.Prefix
+	trusted []netip.Prefix
+	errs    []error
+}
+
+// IPFilterOption configures the IPFilter middleware.
+type IPFilterOption func(*ipFilterConfig)
+
+// WithAllow adds CIDRs (e.g. "10.0.0.0/8", "2001:db8::/32") or single IP addresses to
+// the allowlist. Once any entry is allowed, clients outside the allowlist are blocked.
+func WithAllow(cidrs ...string) IPFilterOption {
+	return func(c *ipFilterConfig) {
+		c.allow = c.appendPrefixes(c.allow, cidrs)
+	}
+}
+
+// WithDeny adds CIDRs or single IP addresses to the denylist. Denied clients are
+// blocked even if they are also allowed.
+func WithDeny(cidrs ...string) IPFilterOption {
+	return func(c *ipFilterConfig) {
+		c.deny = c.appendPrefixes(c.deny, cidrs)
+	}
+}
+
+// WithTrustedProxies adds CIDRs or single IP addresses of reverse proxies whose
+// X-Forwarded-For and X-Real-IP headers are believed. Without it, the client IP is
+// always the connection's remote address, since the headers can be set by anyone.
+func WithTrustedProxies(cidrs ...string) IPFilterOption {
+	return func(c *ipFilterConfig) {
+		c.trusted = c.appendPrefixes(c.trusted, cidrs)
+	}
+}
+
+// appendPrefixes parses cidrs and appends them to prefixes, recording invalid entries.
+func (c *ipFilterConfig) appendPrefixes(prefixes []netip.Prefix, cidrs []string) []netip.Prefix {
+	for _, cidr := range cidrs {
+		prefix, err := parsePrefix(cidr)
+		if err != nil {
+			c.errs = append(c.errs, err)
+
+			continue
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes
+}
+
+// IPFilter returns a middleware that restricts access by client IP address. Clients
+// matching WithDeny get 403 Forbidden (a JSON ErrorResponse inside JSONErrors); when
+// WithAllow entries are configured, so do clients matching none of them. Deny takes
+// precedence over allow. Requests whose client IP cannot be determined are blocked.
+//
+// The client IP is the remote address of the connection, unless it belongs to a proxy
+// given with WithTrustedProxies: then X-Forwarded-For is read from right to left and
+// the first address that is not a trusted proxy is used, falling back to X-Real-IP.
+// IPv4-mapped IPv6 addresses are matched as IPv4.
+// If any CIDR is invalid, every request is rejected with 403 and an error is logged,
+// so a typo cannot silently open the listener.
+func IPFilter(opts ...IPFilterOption) func(http.Handler) http.Handler {
+	var cfg ipFilterConfig
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	invalid := len(cfg.errs) > 0
+	if invalid {
+		slog.Error("middleware: invalid IP filter configuration, rejecting requests", "error", errors.Join(cfg.errs...))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if invalid || !cfg.permits(r) {
+				writeMiddlewareError(w, r, http.StatusForbidden, http.StatusText(http.StatusForbidden))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// permits reports whether the client of r passes the deny and allow lists.
+func (c *ipFilterConfig) permits(r *http.Request) bool {
+	addr, ok := c.clientAddr(r)
+	if !ok {
+		return false
+	}
+
+	if containsAddr(c.deny, addr) {
+		return false
+	}
+
+	return len(c.allow) == 0 || containsAddr(c.allow, addr)
+}
+
+// clientAddr returns the client IP of r, honoring forwarding headers only when the
+// remote address is a trusted proxy.
+func (c *ipFilterConfig) clientAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	remote, err := parseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	if !containsAddr(c.trusted, remote) {
+		return remote, true
+	}
+
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := parseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				return netip.Addr{}, false
+			}
+
+			if !containsAddr(c.trusted, hop) || i == 0 {
+				return hop, true
+			}
+		}
+	}
+
+	if xri := r.Header.Get("X-Real-IP"); xri != "" {
+		addr, err := parseAddr(strings.TrimSpace(xri))
+
+		return addr, err == nil
+	}
+
+	return remote, true
+}
+
+// parseAddr parses an IP address, unmapping IPv4-mapped IPv6 addresses.
+func parseAddr(s string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("parsing IP address: %w", err)
+	}
+
+	return addr.Unmap(), nil
+}
+
+// parsePrefix parses a CIDR or a single IP address, which becomes a full-length prefix.
+func parsePrefix(cidr string) (netip.Prefix, error) {
+	cidr = strings.TrimSpace(cidr)
+
+	if !strings.Contains(cidr, "/") {
+		addr, err := parseAddr(cidr)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("%w %q", errInvalidCIDR, cidr)
+		}
+
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%w %q: %w", errInvalidCIDR, cidr, err)
+	}
+
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+
+	return prefix.Masked(), nil
+}
+
+// containsAddr reports whether any of prefixes contains addr.
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
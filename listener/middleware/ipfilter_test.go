@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requestFrom(remoteAddr string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.RemoteAddr = remoteAddr
+
+	return req
+}
+
+func TestIPFilter(t *testing.T) {
+	t.Parallel()
+
+	handler := IPFilter(
+		WithAllow("10.0.0.0/8", "192.168.1.10", "2001:db8::/32"),
+		WithDeny("10.0.5.0/24", "2001:db8:bad::/48"),
+	)(okHandler())
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		want       int
+	}{
+		{name: "allowed CIDR", remoteAddr: "10.1.2.3:1234", want: http.StatusOK},
+		{name: "allowed single IP", remoteAddr: "192.168.1.10:1234", want: http.StatusOK},
+		{name: "outside allowlist", remoteAddr: "192.168.1.11:1234", want: http.StatusForbidden},
+		{name: "deny takes precedence", remoteAddr: "10.0.5.7:1234", want: http.StatusForbidden},
+		{name: "allowed IPv6", remoteAddr: "[2001:db8:1::1]:1234", want: http.StatusOK},
+		{name: "denied IPv6", remoteAddr: "[2001:db8:bad::1]:1234", want: http.StatusForbidden},
+		{name: "IPv6 outside allowlist", remoteAddr: "[2001:db9::1]:1234", want: http.StatusForbidden},
+		{name: "IPv4-mapped IPv6", remoteAddr: "[::ffff:10.1.2.3]:1234", want: http.StatusOK},
+		{name: "unparseable remote address", remoteAddr: "not-an-ip", want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, requestFrom(tt.remoteAddr))
+
+			assert.Equal(t, tt.want, rr.Code)
+		})
+	}
+}
+
+func TestIPFilter_DenyOnly(t *testing.T) {
+	t.Parallel()
+
+	handler := IPFilter(WithDeny("203.0.113.0/24"))(okHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, requestFrom("198.51.100.1:1234"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, requestFrom("203.0.113.9:1234"))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestIPFilter_TrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	handler := IPFilter(WithAllow("198.51.100.0/24"), WithTrustedProxies("10.0.0.0/8"))(okHandler())
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     string
+		value      string
+		want       int
+	}{
+		{
+			name:       "client behind trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			header:     "X-Forwarded-For",
+			value:      "198.51.100.7",
+			want:       http.StatusOK,
+		},
+		{
+			name:       "spoofed leftmost entry is ignored",
+			remoteAddr: "10.0.0.1:1234",
+			header:     "X-Forwarded-For",
+			value:      "198.51.100.7, 203.0.113.5, 10.0.0.2",
+			want:       http.StatusForbidden,
+		},
+		{
+			name:       "chain of trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			header:     "X-Forwarded-For",
+			value:      "203.0.113.5, 198.51.100.7, 10.0.0.2",
+			want:       http.StatusOK,
+		},
+		{
+			name:       "X-Real-IP from trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			header:     "X-Real-IP",
+			value:      "198.51.100.7",
+			want:       http.StatusOK,
+		},
+		{
+			name:       "headers from untrusted client are ignored",
+			remoteAddr: "203.0.113.5:1234",
+			header:     "X-Forwarded-For",
+			value:      "198.51.100.7",
+			want:       http.StatusForbidden,
+		},
+		{
+			name:       "unparseable forwarded address",
+			remoteAddr: "10.0.0.1:1234",
+			header:     "X-Forwarded-For",
+			value:      "unknown",
+			want:       http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := requestFrom(tt.remoteAddr)
+			req.Header.Set(tt.header, tt.value)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.want, rr.Code)
+		})
+	}
+}
+
+func TestIPFilter_JSONError(t *testing.T) {
+	t.Parallel()
+
+	handler := Chain(RequestID(), JSONErrors(), IPFilter(WithDeny("203.0.113.0/24")))(okHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, requestFrom("203.0.113.9:1234"))
+
+	resp := decodeErrorResponse(t, rr, http.StatusForbidden)
+	assert.Equal(t, http.StatusText(http.StatusForbidden), resp.Error)
+}
+
+func TestIPFilter_InvalidCIDRRejectsAll(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := IPFilter(WithAllow("10.0.0.0/8"), WithDeny("10.0.0.0/33", "bogus"))(okHandler())
+
+	require.Len(t, h.records, 1)
+	assert.ErrorIs(t, h.records[0].Attrs["error"].(error), errInvalidCIDR) //nolint:forcetypeassert
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, requestFrom("10.1.2.3:1234"))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -252,7 +252,8 @@ func applyIPRateLimitDefaults(cfg *perIPRateLimitConfig) {
 // PerIPRateLimit returns a middleware that enforces per-IP rate limiting using a
 // sliding window counter algorithm. Each unique client IP (or custom key via
 // WithKeyFunc) gets independent rate tracking. When a key exceeds its limit,
-// the middleware responds with 429 Too Many Requests and a Retry-After header.
+// the middleware responds with 429 Too Many Requests and a Retry-After header
+// (a JSON ErrorResponse inside JSONErrors).
 //
 // The sliding window algorithm interpolates between the previous and current
 // window counts for smoother rate limiting than fixed windows.
@@ -299,7 +300,7 @@ func PerIPRateLimit(opts ...PerIPRateLimitOption) func(http.Handler) http.Handle
 
 			if !allowed {
 				w.Header().Set("Retry-After", strconv.Itoa(max(int(retryAfter.Seconds()), 1)))
-				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				writeMiddlewareError(w, r, http.StatusTooManyRequests, "Too Many Requests")
 
 				return
 			}
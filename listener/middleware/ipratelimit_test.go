@@ -678,3 +678,19 @@ func TestPerIPRateLimit_NilOption(t *testing.T) { //nolint:paralleltest // share
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
+
+func TestPerIPRateLimit_JSONError(t *testing.T) { //nolint:paralleltest // shared state
+	handler := Chain(RequestID(), JSONErrors(), PerIPRateLimit(WithRateLimit(1, time.Second)))(okHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = testAddr1
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	resp := decodeErrorResponse(t, rr, http.StatusTooManyRequests)
+	assert.Equal(t, "Too Many Requests", resp.Error)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+}
@@ -0,0 +1,422 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const defaultSchemaMaxBodySize int64 = 1 << 20 // 1MB
+
+// JSONSchema is a compiled JSON Schema that validates decoded JSON documents.
+// Validate returns a human-readable description of each violation, or nil if
+// the document is valid.
+type JSONSchema interface {
+	Validate(doc any) []string
+}
+
+// JSONSchemaCompiler compiles a raw JSON Schema document into a JSONSchema.
+// Implement it to plug in a full-featured schema library.
+type JSONSchemaCompiler func(schema []byte) (JSONSchema, error)
+
+// jsonSchemaConfig holds configuration for the ValidateJSONSchema middleware.
+type jsonSchemaConfig struct {
+	compiler    JSONSchemaCompiler
+	maxBodySize int64
+}
+
+// JSONSchemaOption configures the ValidateJSONSchema middleware.
+type JSONSchemaOption func(*jsonSchemaConfig)
+
+// WithSchemaCompiler replaces the built-in schema compiler, allowing a full
+// JSON Schema implementation to be used without this package depending on it.
+// A nil compiler keeps the built-in one.
+func WithSchemaCompiler(compiler JSONSchemaCompiler) JSONSchemaOption {
+	return func(c *jsonSchemaConfig) {
+		if compiler != nil {
+			c.compiler = compiler
+		}
+	}
+}
+
+// WithSchemaMaxBodySize sets the maximum number of body bytes read for validation
+// (default 1MB). Larger bodies are rejected with 413 Request Entity Too Large.
+// If n is not positive, it defaults to 1MB with a warning log.
+func WithSchemaMaxBodySize(n int64) JSONSchemaOption {
+	return func(c *jsonSchemaConfig) {
+		c.maxBodySize = n
+	}
+}
+
+var (
+	errInvalidJSONSchema     = errors.New("invalid JSON schema")
+	errUnsupportedJSONSchema = errors.New("unsupported JSON schema keywords")
+)
+
+// supportedSchemaKeywords lists the keywords the built-in compiler understands.
+// Annotations that do not affect validation are accepted and ignored.
+var supportedSchemaKeywords = map[string]bool{
+	"type": true, "enum": true, "properties": true, "required": true,
+	"additionalProperties": true, "items": true, "minimum": true, "maximum": true,
+	"minLength": true, "maxLength": true, "minItems": true, "maxItems": true,
+	"$schema": true, "$id": true, "$comment": true, "title": true,
+	"description": true, "default": true, "examples": true,
+}
+
+// schemaErrorResponse is the JSON body returned when validation fails.
+type schemaErrorResponse struct {
+	Error   string   `json:"error"`
+	Details []string `json:"details,omitempty"`
+}
+
+// ValidateJSONSchema returns a middleware that validates request bodies of write
+// methods (POST, PUT, PATCH) against a JSON Schema before they reach the handler.
+// The schema is compiled once at construction. Requests whose body is not valid
+// JSON or does not match the schema are rejected with 400 Bad Request and a JSON
+// body listing the validation errors. The body is restored so the handler can
+// still read it.
+//
+// Bodies larger than WithSchemaMaxBodySize (default 1MB) are rejected with
+// 413 Request Entity Too Large.
+//
+// The built-in compiler supports a practical subset of JSON Schema: type, enum,
+// properties, required, additionalProperties (boolean), items, minimum, maximum,
+// minLength, maxLength, minItems, and maxItems, plus the $schema, $id, $comment,
+// title, description, default and examples annotations. Schemas using any other
+// keyword fail to compile, so a constraint is never silently skipped. Use
+// WithSchemaCompiler to plug in a complete implementation.
+//
+// If the schema fails to compile, an error is logged and every write request is
+// rejected with 500 Internal Server Error (a JSON ErrorResponse inside JSONErrors)
+// rather than passing unvalidated input.
+func ValidateJSONSchema(schema []byte, opts ...JSONSchemaOption) func(http.Handler) http.Handler {
+	cfg := jsonSchemaConfig{compiler: compileJSONSchema, maxBodySize: defaultSchemaMaxBodySize}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	if cfg.maxBodySize <= 0 {
+		slog.Warn("middleware: maxBodySize must be positive, using default",
+			"provided", cfg.maxBodySize, "default", defaultSchemaMaxBodySize)
+
+		cfg.maxBodySize = defaultSchemaMaxBodySize
+	}
+
+	compiled, compileErr := cfg.compiler(schema)
+	if compileErr != nil {
+		slog.Error("middleware: failed to compile JSON schema, rejecting write requests", "error", compileErr)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isWriteMethod(r.Method) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if compileErr != nil {
+				writeMiddlewareError(w, r, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, cfg.maxBodySize+1))
+			if err != nil {
+				writeSchemaError(w, "failed to read request body", nil)
+
+				return
+			}
+
+			if int64(len(body)) > cfg.maxBodySize {
+				writeMiddlewareError(w, r, http.StatusRequestEntityTooLarge, "request body too large")
+
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			var doc any
+
+			err = json.Unmarshal(body, &doc)
+			if err != nil {
+				writeSchemaError(w, "request body is not valid JSON", []string{err.Error()})
+
+				return
+			}
+
+			if violations := compiled.Validate(doc); len(violations) > 0 {
+				writeSchemaError(w, "request body does not match schema", violations)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isWriteMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+func writeSchemaError(w http.ResponseWriter, message string, details []string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusBadRequest)
+
+	_ = json.NewEncoder(w).Encode(schemaErrorResponse{Error: message, Details: details})
+}
+
+// jsonSchemaNode is the built-in compiled representation of a (sub)schema.
+type jsonSchemaNode struct {
+	Types                []string                   `json:"-"`
+	RawType              json.RawMessage            `json:"type"`
+	Enum                 []any                      `json:"enum"`
+	Properties           map[string]*jsonSchemaNode `json:"properties"`
+	Required             []string                   `json:"required"`
+	AdditionalProperties *bool                      `json:"additionalProperties"`
+	Items                *jsonSchemaNode            `json:"items"`
+	Minimum              *float64                   `json:"minimum"`
+	Maximum              *float64                   `json:"maximum"`
+	MinLength            *int                       `json:"minLength"`
+	MaxLength            *int                       `json:"maxLength"`
+	MinItems             *int                       `json:"minItems"`
+	MaxItems             *int                       `json:"maxItems"`
+}
+
+// compileJSONSchema is the default JSONSchemaCompiler.
+func compileJSONSchema(schema []byte) (JSONSchema, error) {
+	err := checkSchemaKeywords("$", schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var root jsonSchemaNode
+
+	err = json.Unmarshal(schema, &root)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidJSONSchema, err)
+	}
+
+	err = root.resolveTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	return &root, nil
+}
+
+// checkSchemaKeywords rejects keywords the built-in compiler does not support in the
+// (sub)schema at path, recursing into properties and items.
+func checkSchemaKeywords(path string, schema []byte) error {
+	var node map[string]json.RawMessage
+
+	err := json.Unmarshal(schema, &node)
+	if err != nil {
+		return fmt.Errorf("%w at %s: %w", errInvalidJSONSchema, path, err)
+	}
+
+	var unsupported []string
+
+	for keyword := range node {
+		if !supportedSchemaKeywords[keyword] {
+			unsupported = append(unsupported, keyword)
+		}
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+
+		return fmt.Errorf("%w at %s: %s", errUnsupportedJSONSchema, path, strings.Join(unsupported, ", "))
+	}
+
+	if raw, ok := node["properties"]; ok {
+		var properties map[string]json.RawMessage
+
+		err = json.Unmarshal(raw, &properties)
+		if err != nil {
+			return fmt.Errorf("%w at %s.properties: %w", errInvalidJSONSchema, path, err)
+		}
+
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			if string(properties[name]) == "null" {
+				continue
+			}
+
+			if err := checkSchemaKeywords(path+".properties."+name, properties[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if raw, ok := node["items"]; ok && string(raw) != "null" {
+		return checkSchemaKeywords(path+".items", raw)
+	}
+
+	return nil
+}
+
+// resolveTypes normalizes the "type" keyword, which may be a string or an array of strings.
+func (n *jsonSchemaNode) resolveTypes() error {
+	if len(n.RawType) > 0 {
+		var single string
+		if err := json.Unmarshal(n.RawType, &single); err == nil {
+			n.Types = []string{single}
+		} else if err := json.Unmarshal(n.RawType, &n.Types); err != nil {
+			return fmt.Errorf("%w: type must be a string or array of strings", errInvalidJSONSchema)
+		}
+	}
+
+	for _, prop := range n.Properties {
+		if prop == nil {
+			continue
+		}
+
+		if err := prop.resolveTypes(); err != nil {
+			return err
+		}
+	}
+
+	if n.Items != nil {
+		return n.Items.resolveTypes()
+	}
+
+	return nil
+}
+
+// Validate implements JSONSchema.
+func (n *jsonSchemaNode) Validate(doc any) []string {
+	return n.validate("$", doc, nil)
+}
+
+//nolint:cyclop,gocognit,funlen // one branch per supported keyword.
+func (n *jsonSchemaNode) validate(path string, value any, errs []string) []string {
+	if len(n.Types) > 0 && !slices.ContainsFunc(n.Types, func(t string) bool { return matchesJSONType(t, value) }) {
+		return append(errs, fmt.Sprintf("%s: expected type %v, got %s", path, n.Types, jsonTypeOf(value)))
+	}
+
+	if len(n.Enum) > 0 && !slices.ContainsFunc(n.Enum, func(e any) bool { return jsonEqual(e, value) }) {
+		errs = append(errs, fmt.Sprintf("%s: value is not one of the allowed values", path))
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		for _, name := range n.Required {
+			if _, ok := typed[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			prop, ok := n.Properties[key]
+
+			switch {
+			case ok && prop != nil:
+				errs = prop.validate(path+"."+key, typed[key], errs)
+			case !ok && n.AdditionalProperties != nil && !*n.AdditionalProperties:
+				errs = append(errs, fmt.Sprintf("%s: additional property %q is not allowed", path, key))
+			}
+		}
+	case []any:
+		if n.MinItems != nil && len(typed) < *n.MinItems {
+			errs = append(errs, fmt.Sprintf("%s: expected at least %d items", path, *n.MinItems))
+		}
+
+		if n.MaxItems != nil && len(typed) > *n.MaxItems {
+			errs = append(errs, fmt.Sprintf("%s: expected at most %d items", path, *n.MaxItems))
+		}
+
+		if n.Items != nil {
+			for i, item := range typed {
+				errs = n.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(typed)
+
+		if n.MinLength != nil && length < *n.MinLength {
+			errs = append(errs, fmt.Sprintf("%s: expected length >= %d", path, *n.MinLength))
+		}
+
+		if n.MaxLength != nil && length > *n.MaxLength {
+			errs = append(errs, fmt.Sprintf("%s: expected length <= %d", path, *n.MaxLength))
+		}
+	case float64:
+		if n.Minimum != nil && typed < *n.Minimum {
+			errs = append(errs, fmt.Sprintf("%s: expected value >= %v", path, *n.Minimum))
+		}
+
+		if n.Maximum != nil && typed > *n.Maximum {
+			errs = append(errs, fmt.Sprintf("%s: expected value <= %v", path, *n.Maximum))
+		}
+	}
+
+	return errs
+}
+
+func matchesJSONType(schemaType string, value any) bool {
+	actual := jsonTypeOf(value)
+
+	if schemaType == "number" && actual == "integer" {
+		return true
+	}
+
+	return schemaType == actual
+}
+
+func jsonTypeOf(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if typed == math.Trunc(typed) && !math.IsInf(typed, 0) {
+			return "integer"
+		}
+
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func jsonEqual(a, b any) bool {
+	left, errLeft := json.Marshal(a)
+	right, errRight := json.Marshal(b)
+
+	return errLeft == nil && errRight == nil && bytes.Equal(left, right)
+}
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUserSchema = `{
+  "type": "object",
+  "required": ["name", "age"],
+  "additionalProperties": false,
+  "properties": {
+    "name": {"type": "string", "minLength": 1},
+    "age": {"type": "integer", "minimum": 0, "maximum": 150},
+    "tags": {"type": "array", "items": {"type": "string"}}
+  }
+}`
+
+func newSchemaHandler(t *testing.T, opts ...JSONSchemaOption) (http.Handler, *string) {
+	t.Helper()
+
+	var received string
+
+	handler := ValidateJSONSchema([]byte(testUserSchema), opts...)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received = string(body)
+
+			w.WriteHeader(http.StatusCreated)
+		}),
+	)
+
+	return handler, &received
+}
+
+func decodeSchemaError(t *testing.T, rr *httptest.ResponseRecorder) schemaErrorResponse {
+	t.Helper()
+
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var resp schemaErrorResponse
+
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	require.NoError(t, err)
+
+	return resp
+}
+
+func TestValidateJSONSchema_ValidBody(t *testing.T) {
+	t.Parallel()
+
+	handler, received := newSchemaHandler(t)
+
+	body := `{"name":"alice","age":30,"tags":["admin"]}`
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, body, *received, "handler should still be able to read the body")
+}
+
+func TestValidateJSONSchema_SchemaViolation(t *testing.T) {
+	t.Parallel()
+
+	handler, received := newSchemaHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/users/1",
+		strings.NewReader(`{"name":"","age":200,"tags":[1],"extra":true}`))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, *received, "handler should not be called")
+
+	resp := decodeSchemaError(t, rr)
+	assert.Equal(t, "request body does not match schema", resp.Error)
+	assert.Contains(t, resp.Details, "$.name: expected length >= 1")
+	assert.Contains(t, resp.Details, "$.age: expected value <= 150")
+	assert.Contains(t, resp.Details, "$.tags[0]: expected type [string], got integer")
+	assert.Contains(t, resp.Details, `$: additional property "extra" is not allowed`)
+}
+
+func TestValidateJSONSchema_MissingRequired(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := newSchemaHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(`{"name":"bob"}`))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	resp := decodeSchemaError(t, rr)
+	assert.Equal(t, []string{`$: missing required property "age"`}, resp.Details)
+}
+
+func TestValidateJSONSchema_NonJSONBody(t *testing.T) {
+	t.Parallel()
+
+	handler, received := newSchemaHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("name=alice"))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, *received)
+
+	resp := decodeSchemaError(t, rr)
+	assert.Equal(t, "request body is not valid JSON", resp.Error)
+	assert.NotEmpty(t, resp.Details)
+}
+
+func TestValidateJSONSchema_ReadMethodsBypass(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := newSchemaHandler(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users", strings.NewReader("not json"))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+type rejectAllSchema struct{}
+
+func (rejectAllSchema) Validate(any) []string { return []string{"custom: rejected"} }
+
+func TestValidateJSONSchema_CustomCompiler(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := newSchemaHandler(t, WithSchemaCompiler(func([]byte) (JSONSchema, error) {
+		return rejectAllSchema{}, nil
+	}))
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"alice","age":30}`))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, []string{"custom: rejected"}, decodeSchemaError(t, rr).Details)
+}
+
+func TestValidateJSONSchema_CompileErrorRejectsWrites(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler, received := newSchemaHandler(t, WithSchemaCompiler(func([]byte) (JSONSchema, error) {
+		return nil, errors.New("broken schema")
+	}))
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, "middleware: failed to compile JSON schema, rejecting write requests", h.records[0].Message)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"alice","age":30}`))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Empty(t, *received)
+}
+
+func TestValidateJSONSchema_CompileErrorJSON(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	setupTestLogger(t)
+
+	handler := Chain(RequestID(), JSONErrors(), ValidateJSONSchema([]byte(`{"type": 5}`)))(okHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`)))
+
+	resp := decodeErrorResponse(t, rr, http.StatusInternalServerError)
+	assert.Equal(t, http.StatusText(http.StatusInternalServerError), resp.Error)
+}
+
+func TestCompileJSONSchema_InvalidType(t *testing.T) {
+	t.Parallel()
+
+	_, err := compileJSONSchema([]byte(`{"type": 5}`))
+	require.ErrorIs(t, err, errInvalidJSONSchema)
+}
+
+func TestCompileJSONSchema_UnsupportedKeywords(t *testing.T) {
+	t.Parallel()
+
+	_, err := compileJSONSchema([]byte(`{"type": "object", "oneOf": [], "properties": {"id": {"type": "string"}}}`))
+	require.ErrorIs(t, err, errUnsupportedJSONSchema)
+	assert.Contains(t, err.Error(), "oneOf")
+
+	_, err = compileJSONSchema([]byte(`{"type": "object", "properties": {"email": {"type": "string", "format": "email", "pattern": "@"}}}`))
+	require.ErrorIs(t, err, errUnsupportedJSONSchema)
+	assert.Contains(t, err.Error(), "$.properties.email: format, pattern")
+
+	_, err = compileJSONSchema([]byte(`{"type": "array", "items": {"$ref": "#/defs/item"}}`))
+	require.ErrorIs(t, err, errUnsupportedJSONSchema)
+	assert.Contains(t, err.Error(), "$.items: $ref")
+
+	_, err = compileJSONSchema([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "User", "type": "object"}`))
+	require.NoError(t, err, "annotations should be accepted")
+}
+
+func TestValidateJSONSchema_BodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	handler, received := newSchemaHandler(t, WithSchemaMaxBodySize(16))
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"alice","age":30}`))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Empty(t, *received)
+}
+
+func TestValidateJSONSchema_NilCompilerKeepsDefault(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := newSchemaHandler(t, WithSchemaCompiler(nil))
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"alice","age":200}`))
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+type listenerNameKeyType struct{}
+
+var listenerNameKey = listenerNameKeyType{} //nolint:gochecknoglobals
+
+// InjectListenerName returns a middleware that stores the name of the listener
+// serving the request in the request context. Downstream handlers can retrieve
+// it via GetListenerName. listener.NewModule applies it automatically.
+func InjectListenerName(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), listenerNameKey, name)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetListenerName retrieves the listener name from the context.
+// It returns an empty string if no listener name is set.
+func GetListenerName(ctx context.Context) string {
+	val, ok := ctx.Value(listenerNameKey).(string)
+	if !ok {
+		return ""
+	}
+
+	return val
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInjectListenerName_SetsNameInContext(t *testing.T) {
+	t.Parallel()
+
+	var got string
+
+	handler := InjectListenerName("admin")(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = GetListenerName(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "admin", got)
+}
+
+func TestGetListenerName_EmptyWhenNotSet(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, GetListenerName(context.Background()))
+}
@@ -2,19 +2,33 @@ package middleware
 
 import (
 	"bufio"
+	"bytes"
+	"io"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// statusWriter wraps http.ResponseWriter to capture the status code.
+// statusWriter wraps http.ResponseWriter to capture the status code and body size.
 type statusWriter struct {
 	http.ResponseWriter
 
 	status   int
+	bytes    int64
 	written  bool
 	hijacked bool
+
+	// onHijack, if set, makes Hijack return a connection that counts the bytes
+	// transferred. It is called at hijack time with the response status and returns
+	// the function to call once the connection is closed.
+	onHijack func(status int) func(conn *countingConn)
+	// summaryPending reports that the connection was wrapped for onHijack.
+	summaryPending bool
 }
 
 func (w *statusWriter) WriteHeader(code int) {
@@ -32,7 +46,10 @@ func (w *statusWriter) Write(b []byte) (int, error) {
 		w.written = true
 	}
 
-	return w.ResponseWriter.Write(b) //nolint:wrapcheck
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+
+	return n, err //nolint:wrapcheck
 }
 
 // Hijack implements http.Hijacker by delegating to the underlying ResponseWriter
@@ -43,11 +60,72 @@ func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	rc := http.NewResponseController(w.ResponseWriter)
 
 	conn, buf, err := rc.Hijack()
-	if err == nil {
-		w.hijacked = true
+	if err != nil {
+		return conn, buf, err //nolint:wrapcheck
+	}
+
+	w.hijacked = true
+
+	if w.onHijack == nil || conn == nil || buf == nil {
+		return conn, buf, nil
 	}
 
-	return conn, buf, err //nolint:wrapcheck
+	w.resolveStatus()
+	w.summaryPending = true
+
+	onClose := w.onHijack(w.status)
+	counted := &countingConn{Conn: conn, hijackedAt: time.Now()}
+	counted.onClose = func() { onClose(counted) }
+
+	return counted, counted.wrapBuffer(buf), nil
+}
+
+// countingConn wraps a hijacked connection, counting the bytes read and written
+// through it and running onClose once when it is closed.
+type countingConn struct {
+	net.Conn
+
+	bytesRead    atomic.Int64
+	bytesWritten atomic.Int64
+	hijackedAt   time.Time
+	onClose      func()
+	closeOnce    sync.Once
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.bytesRead.Add(int64(n))
+
+	return n, err //nolint:wrapcheck
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.bytesWritten.Add(int64(n))
+
+	return n, err //nolint:wrapcheck
+}
+
+func (c *countingConn) Close() error {
+	err := c.Conn.Close()
+
+	c.closeOnce.Do(c.onClose)
+
+	return err //nolint:wrapcheck
+}
+
+// wrapBuffer returns a ReadWriter equivalent to buf that reads and writes through c.
+// Data the server had already buffered is kept and counted as read; pending output
+// is flushed to the underlying connection first.
+func (c *countingConn) wrapBuffer(buf *bufio.ReadWriter) *bufio.ReadWriter {
+	_ = buf.Flush()
+
+	buffered, _ := buf.Peek(buf.Reader.Buffered())
+	c.bytesRead.Add(int64(len(buffered)))
+
+	reader := io.MultiReader(bytes.NewReader(bytes.Clone(buffered)), c)
+
+	return bufio.NewReadWriter(bufio.NewReader(reader), bufio.NewWriter(c))
 }
 
 // Flush delegates to the underlying ResponseWriter via http.ResponseController,
@@ -62,55 +140,236 @@ func (w *statusWriter) Flush() {
 	}
 }
 
+// resolveStatus sets the status for handlers that never wrote a response:
+// 101 Switching Protocols if the connection was hijacked, 200 OK otherwise.
+func (w *statusWriter) resolveStatus() {
+	if w.status != 0 {
+		return
+	}
+
+	if w.hijacked {
+		w.status = http.StatusSwitchingProtocols
+	} else {
+		w.status = http.StatusOK
+	}
+}
+
 // Unwrap returns the underlying ResponseWriter, allowing http.ResponseController
 // to access interfaces like http.Flusher and http.Hijacker through the wrapper chain.
 func (w *statusWriter) Unwrap() http.ResponseWriter {
 	return w.ResponseWriter
 }
 
+// loggingConfig holds configuration for the Logging middleware.
+type loggingConfig struct {
+	userAgent     bool
+	referer       bool
+	query         bool
+	contentType   bool
+	hijackSummary bool
+	redactKeys    map[string]struct{}
+}
+
+// redactedValue replaces the values of redacted query parameters.
+const redactedValue = "[REDACTED]"
+
+// LoggingOption configures the Logging middleware.
+type LoggingOption func(*loggingConfig)
+
+// WithUserAgent adds the request's User-Agent header as the "user_agent" attribute.
+// Off by default to avoid high-cardinality noise. Omitted when the header is empty.
+func WithUserAgent() LoggingOption {
+	return func(c *loggingConfig) {
+		c.userAgent = true
+	}
+}
+
+// WithReferer adds the request's Referer header as the "referer" attribute.
+// Off by default to avoid high-cardinality noise. Omitted when the header is empty.
+func WithReferer() LoggingOption {
+	return func(c *loggingConfig) {
+		c.referer = true
+	}
+}
+
+// WithQuery adds the request's raw query string as the "query" attribute, with the
+// values of the parameters named in redactKeys (matched case-insensitively, e.g.
+// "token", "password") replaced by "[REDACTED]". Off by default because query strings
+// can carry secrets. Omitted when the query is empty.
+func WithQuery(redactKeys ...string) LoggingOption {
+	return func(c *loggingConfig) {
+		c.query = true
+
+		if c.redactKeys == nil {
+			c.redactKeys = make(map[string]struct{}, len(redactKeys))
+		}
+
+		for _, key := range redactKeys {
+			c.redactKeys[strings.ToLower(key)] = struct{}{}
+		}
+	}
+}
+
+// WithLogContentType adds the request's Content-Type header as the "req_content_type"
+// attribute and the response's Content-Type header, as set by the handler, as the
+// "resp_content_type" attribute. Off by default. Each is omitted when empty.
+func WithLogContentType() LoggingOption {
+	return func(c *loggingConfig) {
+		c.contentType = true
+	}
+}
+
+// WithHijackSummary defers the log of hijacked requests, such as WebSocket upgrades,
+// until the hijacked connection is closed. That single summary log adds "bytes_read"
+// and "bytes_written" (bytes transferred over the connection after the hijack) and
+// "conn_duration" (time from the hijack to the close), and its "duration" covers the
+// whole request. No log is written for a hijacked connection that is never closed.
+func WithHijackSummary() LoggingOption {
+	return func(c *loggingConfig) {
+		c.hijackSummary = true
+	}
+}
+
+// redactQuery returns rawQuery with the values of parameters in redactKeys replaced.
+// Parameter order and encoding of the other parameters are preserved.
+func redactQuery(rawQuery string, redactKeys map[string]struct{}) string {
+	if len(redactKeys) == 0 {
+		return rawQuery
+	}
+
+	params := strings.Split(rawQuery, "&")
+
+	for i, param := range params {
+		rawKey, _, _ := strings.Cut(param, "=")
+
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+
+		if _, ok := redactKeys[strings.ToLower(key)]; ok {
+			params[i] = rawKey + "=" + redactedValue
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
 // Logging returns a middleware that logs request/response details via global slog.
-// It logs method, path, status code, duration, and request ID (if available).
+// It logs method, path, status code, duration, request ID and queue delay (see
+// RequestStartTime), if available.
 // Log level is Info for 2xx/3xx, Warn for 4xx, Error for 5xx.
-func Logging() func(http.Handler) http.Handler {
+//
+// Options:
+//   - WithUserAgent() - include the User-Agent header
+//   - WithReferer() - include the Referer header
+//   - WithQuery(redactKeys...) - include the query string with sensitive values redacted
+//   - WithLogContentType() - include the request and response Content-Type headers
+//   - WithHijackSummary() - log hijacked connections once they close, with byte counts
+func Logging(opts ...LoggingOption) func(http.Handler) http.Handler {
+	cfg := loggingConfig{}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 
 			sw := &statusWriter{ResponseWriter: w}
 
-			next.ServeHTTP(sw, r)
+			if cfg.hijackSummary {
+				sw.onHijack = func(status int) func(*countingConn) {
+					respType := sw.Header().Get("Content-Type")
+
+					return func(conn *countingConn) {
+						attrs := append(cfg.requestAttrs(r, status, time.Since(start), respType),
+							slog.Int64("bytes_read", conn.bytesRead.Load()),
+							slog.Int64("bytes_written", conn.bytesWritten.Load()),
+							slog.Duration("conn_duration", time.Since(conn.hijackedAt)),
+						)
 
-			if sw.status == 0 {
-				if sw.hijacked {
-					sw.status = http.StatusSwitchingProtocols
-				} else {
-					sw.status = http.StatusOK
+						logRequest(status, attrs)
+					}
 				}
 			}
 
-			duration := time.Since(start)
-
-			attrs := []any{
-				slog.String("method", r.Method),
-				slog.String("path", r.URL.Path),
-				slog.Int("status", sw.status),
-				slog.Duration("duration", duration),
-			}
+			next.ServeHTTP(sw, r)
 
-			if reqID := GetRequestID(r.Context()); reqID != "" {
-				attrs = append(attrs, slog.String("request_id", reqID))
+			if sw.summaryPending {
+				return
 			}
 
-			msg := "http request"
+			sw.resolveStatus()
 
-			switch {
-			case sw.status >= http.StatusInternalServerError:
-				slog.Error(msg, attrs...) //nolint:gosec // G706: msg is a hardcoded constant, not user input.
-			case sw.status >= http.StatusBadRequest:
-				slog.Warn(msg, attrs...) //nolint:gosec // G706: msg is a hardcoded constant, not user input.
-			default:
-				slog.Info(msg, attrs...) //nolint:gosec // G706: msg is a hardcoded constant, not user input.
-			}
+			logRequest(sw.status, cfg.requestAttrs(r, sw.status, time.Since(start), sw.Header().Get("Content-Type")))
 		})
 	}
 }
+
+// requestAttrs returns the log attributes describing r and its response, given the
+// response status, the request duration and the response content type.
+func (cfg *loggingConfig) requestAttrs(r *http.Request, status int, duration time.Duration, respType string) []any {
+	attrs := []any{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", status),
+		slog.Duration("duration", duration),
+	}
+
+	if reqID := GetRequestID(r.Context()); reqID != "" {
+		attrs = append(attrs, slog.String("request_id", reqID))
+	}
+
+	if delay, ok := GetQueueDelay(r.Context()); ok {
+		attrs = append(attrs, slog.Duration("queue_delay", delay))
+	}
+
+	if ua := r.UserAgent(); cfg.userAgent && ua != "" {
+		attrs = append(attrs, slog.String("user_agent", ua))
+	}
+
+	if referer := r.Referer(); cfg.referer && referer != "" {
+		attrs = append(attrs, slog.String("referer", referer))
+	}
+
+	if cfg.query && r.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", redactQuery(r.URL.RawQuery, cfg.redactKeys)))
+	}
+
+	if cfg.contentType {
+		attrs = appendContentTypes(attrs, r.Header.Get("Content-Type"), respType)
+	}
+
+	return attrs
+}
+
+// logRequest writes the request log at the level matching status.
+func logRequest(status int, attrs []any) {
+	msg := "http request"
+
+	switch {
+	case status >= http.StatusInternalServerError:
+		slog.Error(msg, attrs...) //nolint:gosec // G706: msg is a hardcoded constant, not user input.
+	case status >= http.StatusBadRequest:
+		slog.Warn(msg, attrs...) //nolint:gosec // G706: msg is a hardcoded constant, not user input.
+	default:
+		slog.Info(msg, attrs...) //nolint:gosec // G706: msg is a hardcoded constant, not user input.
+	}
+}
+
+// appendContentTypes appends the non-empty request and response content types to attrs.
+func appendContentTypes(attrs []any, reqType, respType string) []any {
+	if reqType != "" {
+		attrs = append(attrs, slog.String("req_content_type", reqType))
+	}
+
+	if respType != "" {
+		attrs = append(attrs, slog.String("resp_content_type", respType))
+	}
+
+	return attrs
+}
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"bufio"
 	"context"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -197,3 +200,274 @@ func TestLogging_ImplicitOKStatus(t *testing.T) { //nolint:paralleltest // modif
 	assert.Equal(t, int64(http.StatusOK), h.records[0].Attrs["status"])
 	assert.Equal(t, slog.LevelInfo, h.records[0].Level)
 }
+
+func TestLogging_UserAgentAndReferer(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	tests := []struct {
+		name        string
+		opts        []LoggingOption
+		userAgent   string
+		referer     string
+		wantUA      any
+		wantReferer any
+	}{
+		{
+			name:        "disabled by default",
+			opts:        nil,
+			userAgent:   "curl/8.0",
+			referer:     "https://example.com/",
+			wantUA:      nil,
+			wantReferer: nil,
+		},
+		{
+			name:        "enabled",
+			opts:        []LoggingOption{WithUserAgent(), WithReferer()},
+			userAgent:   "curl/8.0",
+			referer:     "https://example.com/",
+			wantUA:      "curl/8.0",
+			wantReferer: "https://example.com/",
+		},
+		{
+			name:        "only user agent",
+			opts:        []LoggingOption{WithUserAgent()},
+			userAgent:   "curl/8.0",
+			referer:     "https://example.com/",
+			wantUA:      "curl/8.0",
+			wantReferer: nil,
+		},
+		{
+			name:        "enabled with empty headers",
+			opts:        []LoggingOption{WithUserAgent(), WithReferer()},
+			userAgent:   "",
+			referer:     "",
+			wantUA:      nil,
+			wantReferer: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := setupTestLogger(t)
+			handler := Logging(tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("User-Agent", tt.userAgent)
+			req.Header.Set("Referer", tt.referer)
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Len(t, h.records, 1)
+
+			ua, hasUA := h.records[0].Attrs["user_agent"]
+			referer, hasReferer := h.records[0].Attrs["referer"]
+
+			assert.Equal(t, tt.wantUA != nil, hasUA)
+			assert.Equal(t, tt.wantReferer != nil, hasReferer)
+			assert.Equal(t, tt.wantUA, ua)
+			assert.Equal(t, tt.wantReferer, referer)
+		})
+	}
+}
+
+func TestLogging_WithLogContentType(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	tests := []struct {
+		name         string
+		opts         []LoggingOption
+		reqType      string
+		respType     string
+		wantReqType  any
+		wantRespType any
+	}{
+		{
+			name:         "disabled by default",
+			reqType:      "application/json",
+			respType:     "application/xml",
+			wantReqType:  nil,
+			wantRespType: nil,
+		},
+		{
+			name:         "enabled",
+			opts:         []LoggingOption{WithLogContentType()},
+			reqType:      "application/json",
+			respType:     "application/xml; charset=utf-8",
+			wantReqType:  "application/json",
+			wantRespType: "application/xml; charset=utf-8",
+		},
+		{
+			name:         "enabled without request body",
+			opts:         []LoggingOption{WithLogContentType()},
+			reqType:      "",
+			respType:     "text/plain",
+			wantReqType:  nil,
+			wantRespType: "text/plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := setupTestLogger(t)
+			handler := Logging(tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.respType)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set("Content-Type", tt.reqType)
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Len(t, h.records, 1)
+
+			reqType, hasReqType := h.records[0].Attrs["req_content_type"]
+			respType, hasRespType := h.records[0].Attrs["resp_content_type"]
+
+			assert.Equal(t, tt.wantReqType != nil, hasReqType)
+			assert.Equal(t, tt.wantRespType != nil, hasRespType)
+			assert.Equal(t, tt.wantReqType, reqType)
+			assert.Equal(t, tt.wantRespType, respType)
+		})
+	}
+}
+
+func TestLogging_WithQuery(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	tests := []struct {
+		name      string
+		opts      []LoggingOption
+		target    string
+		wantQuery any
+	}{
+		{
+			name:      "disabled by default",
+			opts:      nil,
+			target:    "/search?q=go&token=secret",
+			wantQuery: nil,
+		},
+		{
+			name:      "without redaction",
+			opts:      []LoggingOption{WithQuery()},
+			target:    "/search?q=go&page=2",
+			wantQuery: "q=go&page=2",
+		},
+		{
+			name:      "redacts listed keys",
+			opts:      []LoggingOption{WithQuery("token", "password")},
+			target:    "/login?user=alice&password=hunter2&token=abc&next=%2Fhome",
+			wantQuery: "user=alice&password=[REDACTED]&token=[REDACTED]&next=%2Fhome",
+		},
+		{
+			name:      "matches keys case-insensitively and escaped",
+			opts:      []LoggingOption{WithQuery("api_key")},
+			target:    "/?API_KEY=one&api%5Fkey=two&flag",
+			wantQuery: "API_KEY=[REDACTED]&api%5Fkey=[REDACTED]&flag",
+		},
+		{
+			name:      "redacts repeated and valueless keys",
+			opts:      []LoggingOption{WithQuery("token")},
+			target:    "/?token=a&token=b&token",
+			wantQuery: "token=[REDACTED]&token=[REDACTED]&token=[REDACTED]",
+		},
+		{
+			name:      "empty query omitted",
+			opts:      []LoggingOption{WithQuery("token")},
+			target:    "/",
+			wantQuery: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := setupTestLogger(t)
+			handler := Logging(tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			require.Len(t, h.records, 1)
+
+			query, hasQuery := h.records[0].Attrs["query"]
+
+			assert.Equal(t, tt.wantQuery != nil, hasQuery)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.NotContains(t, h.records[0].Attrs["path"], "?")
+		})
+	}
+}
+
+// connHijackRecorder is a ResponseRecorder whose Hijack hands out the server end of a pipe.
+type connHijackRecorder struct {
+	*httptest.ResponseRecorder
+
+	conn net.Conn
+}
+
+func (h *connHijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.conn, bufio.NewReadWriter(bufio.NewReader(h.conn), bufio.NewWriter(h.conn)), nil
+}
+
+func TestLogging_WithHijackSummary(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	server, client := net.Pipe()
+
+	// The client reads the 7 bytes the handler sends, then answers with 3 bytes.
+	clientDone := make(chan error, 1)
+
+	go func() {
+		received := make([]byte, 7)
+
+		_, err := io.ReadFull(client, received)
+		if err == nil {
+			_, err = client.Write([]byte("abc"))
+		}
+
+		clientDone <- err
+	}()
+
+	handler := Logging(WithHijackSummary())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		conn, buf, err := http.NewResponseController(w).Hijack()
+		require.NoError(t, err)
+
+		_, err = conn.Write([]byte("hello"))
+		require.NoError(t, err)
+
+		_, err = buf.WriteString("!!")
+		require.NoError(t, err)
+		require.NoError(t, buf.Flush())
+
+		_, err = io.ReadFull(buf, make([]byte, 3))
+		require.NoError(t, err)
+
+		assert.Empty(t, h.records, "nothing should be logged before the connection closes")
+
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, conn.Close())
+		require.NoError(t, conn.Close(), "closing twice must not log twice")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	handler.ServeHTTP(&connHijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server}, req)
+
+	require.NoError(t, <-clientDone)
+	require.Len(t, h.records, 1)
+
+	attrs := h.records[0].Attrs
+	assert.Equal(t, int64(http.StatusSwitchingProtocols), attrs["status"])
+	assert.Equal(t, "/ws", attrs["path"])
+	assert.Equal(t, int64(7), attrs["bytes_written"])
+	assert.Equal(t, int64(3), attrs["bytes_read"])
+	assert.GreaterOrEqual(t, attrs["conn_duration"], 5*time.Millisecond)
+	assert.GreaterOrEqual(t, attrs["duration"], attrs["conn_duration"])
+}
+
+func TestLogging_WithHijackSummaryNotHijacked(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Logging(WithHijackSummary())(okHandler())
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, int64(http.StatusOK), h.records[0].Attrs["status"])
+	assert.NotContains(t, h.records[0].Attrs, "bytes_written")
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"container/list"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultLRUMaxEntries = 10000
+
+// lruEntry is a client's token bucket, stored as the value of an LRU list element.
+type lruEntry struct {
+	key    string
+	bucket *tokenBucket
+}
+
+// lruLimiter holds at most maxEntries token buckets, evicting the least recently used
+// one when a new client arrives at capacity. Evicted clients start over with a full bucket.
+type lruLimiter struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // front is the most recently used
+	entries    map[string]*list.Element
+
+	requestsPerSecond float64
+	burst             int
+	timeNow           func() time.Time
+}
+
+// bucket returns the token bucket for key, creating it (and evicting the least
+// recently used bucket when full) if needed, and marks it most recently used.
+func (l *lruLimiter) bucket(key string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.entries[key]; ok {
+		l.order.MoveToFront(elem)
+
+		return elem.Value.(*lruEntry).bucket //nolint:forcetypeassert // the list only holds *lruEntry
+	}
+
+	if l.order.Len() >= l.maxEntries {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key) //nolint:forcetypeassert // the list only holds *lruEntry
+	}
+
+	entry := &lruEntry{key: key, bucket: newTokenBucket(l.requestsPerSecond, l.burst, l.timeNow)}
+	l.entries[key] = l.order.PushFront(entry)
+
+	return entry.bucket
+}
+
+// len returns the number of tracked clients.
+func (l *lruLimiter) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+// NewLRURateLimiter returns a middleware that enforces a token bucket rate limit per
+// client IP (extracted as in PerIPRateLimit) while tracking at most maxEntries clients.
+// When a new client arrives and maxEntries clients are tracked, the least recently seen
+// client is evicted, so memory stays bounded without a cleanup goroutine; an evicted
+// client starts over with a full bucket. Rejected requests get 429 Too Many Requests
+// with a Retry-After header, as in RateLimit.
+// If maxEntries is not positive, it defaults to 10000 with a warning log; invalid rates
+// and bursts default to 1 with a warning log, as in RateLimit.
+func NewLRURateLimiter(maxEntries int, requestsPerSecond float64, burst int) func(http.Handler) http.Handler {
+	return lruRateLimit(newLRULimiter(maxEntries, requestsPerSecond, burst, time.Now))
+}
+
+// newLRULimiter validates the settings and returns an empty limiter using timeNow as its clock.
+func newLRULimiter(maxEntries int, requestsPerSecond float64, burst int, timeNow func() time.Time) *lruLimiter {
+	if maxEntries <= 0 {
+		slog.Warn("middleware: maxEntries must be positive, using default",
+			"provided", maxEntries, "default", defaultLRUMaxEntries)
+
+		maxEntries = defaultLRUMaxEntries
+	}
+
+	requestsPerSecond, burst = normalizeRate(requestsPerSecond, burst)
+
+	return &lruLimiter{
+		maxEntries:        maxEntries,
+		order:             list.New(),
+		entries:           make(map[string]*list.Element, maxEntries),
+		requestsPerSecond: requestsPerSecond,
+		burst:             burst,
+		timeNow:           timeNow,
+	}
+}
+
+// lruRateLimit returns the middleware for limiter, letting tests inspect its state.
+func lruRateLimit(limiter *lruLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !allowRequest(w, r, limiter.bucket(extractClientIP(r))) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frozenClock returns a clock that never advances, so buckets never refill.
+func frozenClock() func() time.Time {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return func() time.Time { return now }
+}
+
+func TestLRURateLimiter_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	limiter := newLRULimiter(2, 1, 1, frozenClock())
+	handler := lruRateLimit(limiter)(okHandler())
+
+	send := func(addr string) int {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		handler.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	// Exhaust the buckets of two clients, filling the cache.
+	for _, addr := range []string{testAddr1, testAddr2} {
+		assert.Equal(t, http.StatusOK, send(addr))
+		assert.Equal(t, http.StatusTooManyRequests, send(addr), "%s should be limited", addr)
+	}
+
+	require.Equal(t, 2, limiter.len())
+
+	// A new client evicts testAddr1, the least recently used.
+	assert.Equal(t, http.StatusOK, send(testAddr3))
+	assert.Equal(t, 2, limiter.len(), "cache must not grow beyond maxEntries")
+
+	assert.Equal(t, http.StatusTooManyRequests, send(testAddr2), "testAddr2 was not evicted")
+	assert.Equal(t, http.StatusOK, send(testAddr1), "evicted client should start with a full bucket")
+}
+
+func TestLRURateLimiter_AccessRefreshesRecency(t *testing.T) {
+	t.Parallel()
+
+	limiter := newLRULimiter(2, 1, 1, frozenClock())
+	handler := lruRateLimit(limiter)(okHandler())
+
+	send := func(addr string) int {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = addr
+		handler.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	send(testAddr1)
+	send(testAddr2)
+
+	// Touching testAddr1 makes testAddr2 the eviction candidate.
+	assert.Equal(t, http.StatusTooManyRequests, send(testAddr1))
+
+	send(testAddr3)
+
+	assert.Equal(t, http.StatusTooManyRequests, send(testAddr1), "recently used client should keep its state")
+	assert.Equal(t, http.StatusOK, send(testAddr2), "least recently used client should be evicted")
+}
+
+func TestLRURateLimiter_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	handler := NewLRURateLimiter(10, 0.5, 1)(okHandler())
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+}
+
+func TestNewLRURateLimiter_InvalidMaxEntries(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	limiter := newLRULimiter(0, 1, 1, time.Now)
+
+	assert.Equal(t, defaultLRUMaxEntries, limiter.maxEntries)
+	require.Len(t, h.records, 1)
+	assert.Equal(t, int64(0), h.records[0].Attrs["provided"])
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+const (
+	defaultMaxHeaderCount      = 100
+	defaultMaxHeaderTotalBytes = http.DefaultMaxHeaderBytes // 1MB
+)
+
+// MaxHeaders returns a middleware that rejects requests with too many header fields
+// or too large headers with 431 Request Header Fields Too Large (a JSON ErrorResponse
+// inside JSONErrors).
+// Every value counts as one field, so a header repeated three times counts three times.
+// The total size is the sum of len(name)+len(value) over all fields.
+// It complements http.Server.MaxHeaderBytes, which bounds the raw bytes read per
+// connection, with per-request limits applied after parsing.
+//
+// If maxCount is not positive, it defaults to 100 with a warning log.
+// If maxTotalBytes is not positive, it defaults to 1MB (1048576 bytes) with a warning log.
+func MaxHeaders(maxCount int, maxTotalBytes int) func(http.Handler) http.Handler {
+	if maxCount <= 0 {
+		slog.Warn("middleware: maxCount must be positive, using default",
+			"provided", maxCount, "default", defaultMaxHeaderCount)
+
+		maxCount = defaultMaxHeaderCount
+	}
+
+	if maxTotalBytes <= 0 {
+		slog.Warn("middleware: maxTotalBytes must be positive, using default",
+			"provided", maxTotalBytes, "default", defaultMaxHeaderTotalBytes)
+
+		maxTotalBytes = defaultMaxHeaderTotalBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !headersWithinLimits(r.Header, maxCount, maxTotalBytes) {
+				writeMiddlewareError(w, r, http.StatusRequestHeaderFieldsTooLarge,
+					http.StatusText(http.StatusRequestHeaderFieldsTooLarge))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// headersWithinLimits reports whether header has at most maxCount fields
+// totalling at most maxTotalBytes.
+func headersWithinLimits(header http.Header, maxCount, maxTotalBytes int) bool {
+	count := 0
+	total := 0
+
+	for name, values := range header {
+		for _, value := range values {
+			count++
+			total += len(name) + len(value)
+
+			if count > maxCount || total > maxTotalBytes {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		header     http.Header
+		wantStatus int
+	}{
+		{
+			name:       "within limits",
+			header:     http.Header{"Accept": {"text/plain"}, "X-Token": {"abc"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "exactly at count limit",
+			header: http.Header{
+				"X-A": {"1"}, "X-B": {"2"}, "X-C": {"3"}, "X-D": {"4"}, "X-E": {"5"},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "too many headers",
+			header: http.Header{
+				"X-A": {"1"}, "X-B": {"2"}, "X-C": {"3"}, "X-D": {"4"}, "X-E": {"5"}, "X-F": {"6"},
+			},
+			wantStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			name:       "repeated values count separately",
+			header:     http.Header{"X-Multi": {"1", "2", "3", "4", "5", "6"}},
+			wantStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			name:       "total size too large",
+			header:     http.Header{"X-Big": {strings.Repeat("a", 100)}},
+			wantStatus: http.StatusRequestHeaderFieldsTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := MaxHeaders(5, 64)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header = tt.header
+
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestMaxHeaders_DefaultsOnInvalidArguments(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := MaxHeaders(0, -1)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	require.Len(t, h.records, 2)
+	assert.Equal(t, int64(defaultMaxHeaderCount), h.records[0].Attrs["default"])
+	assert.Equal(t, int64(defaultMaxHeaderTotalBytes), h.records[1].Attrs["default"])
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for i := range defaultMaxHeaderCount + 1 {
+		req.Header.Set("X-Header-"+strconv.Itoa(i), "v")
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, rec.Code)
+}
+
+func TestMaxHeaders_JSONError(t *testing.T) {
+	t.Parallel()
+
+	handler := Chain(RequestID(), JSONErrors(), MaxHeaders(1, 64))(okHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header = http.Header{"X-A": {"1"}, "X-B": {"2"}}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	resp := decodeErrorResponse(t, rr, http.StatusRequestHeaderFieldsTooLarge)
+	assert.Equal(t, http.StatusText(http.StatusRequestHeaderFieldsTooLarge), resp.Error)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// defaultMaxURLLength matches the request line limit of common proxies and servers.
+const defaultMaxURLLength = 8192
+
+// MaxURLLength returns a middleware that rejects requests whose request target (the
+// path and query as sent in the request line, r.RequestURI) is longer than n bytes with
+// 414 URI Too Long (a JSON ErrorResponse inside JSONErrors). Overly long URLs are a DoS
+// vector and bloat access logs. Requests built without a RequestURI, as in tests or
+// client-side handlers, are measured by r.URL.RequestURI().
+// If n is not positive, it defaults to 8192 with a warning log.
+func MaxURLLength(n int) func(http.Handler) http.Handler {
+	if n <= 0 {
+		slog.Warn("middleware: n must be positive, using default", "provided", n, "default", defaultMaxURLLength)
+
+		n = defaultMaxURLLength
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			target := r.RequestURI
+			if target == "" {
+				target = r.URL.RequestURI()
+			}
+
+			if len(target) > n {
+				writeMiddlewareError(w, r, http.StatusRequestURITooLong, http.StatusText(http.StatusRequestURITooLong))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
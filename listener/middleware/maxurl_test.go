@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxURLLength(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{name: "within limit", target: "/users?page=2", wantStatus: http.StatusOK},
+		{name: "exactly at limit", target: "/" + strings.Repeat("a", 31), wantStatus: http.StatusOK},
+		{name: "long path", target: "/" + strings.Repeat("a", 32), wantStatus: http.StatusRequestURITooLong},
+		{name: "long query", target: "/search?q=" + strings.Repeat("x", 30), wantStatus: http.StatusRequestURITooLong},
+	}
+
+	handler := MaxURLLength(32)(okHandler())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestMaxURLLength_WithoutRequestURI(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/"+strings.Repeat("a", 40), nil)
+	req.RequestURI = ""
+
+	rr := httptest.NewRecorder()
+	MaxURLLength(32)(okHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestURITooLong, rr.Code)
+}
+
+func TestMaxURLLength_JSONErrors(t *testing.T) {
+	t.Parallel()
+
+	handler := Chain(RequestID(), JSONErrors(), MaxURLLength(8))(okHandler())
+
+	req := requestWithID("req-414")
+	req.RequestURI = "/too-long-url"
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	body := decodeErrorResponse(t, rr, http.StatusRequestURITooLong)
+	assert.Equal(t, "Request URI Too Long", body.Error)
+	assert.Equal(t, "req-414", body.RequestID)
+}
+
+func TestMaxURLLength_InvalidLimit(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := MaxURLLength(0)(okHandler())
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, int64(0), h.records[0].Attrs["provided"])
+	assert.Equal(t, int64(defaultMaxURLLength), h.records[0].Attrs["default"])
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/"+strings.Repeat("a", defaultMaxURLLength-1), nil))
+	assert.Equal(t, http.StatusOK, rr.Code, "URL at the default limit should pass")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/"+strings.Repeat("a", defaultMaxURLLength), nil))
+	assert.Equal(t, http.StatusRequestURITooLong, rr.Code)
+}
@@ -0,0 +1,240 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+)
+
+const (
+	defaultMultipartMaxMemory   int64 = 32 << 20 // 32MB, as http.Request.FormFile
+	defaultMultipartMaxFiles          = 10
+	defaultMultipartMaxFileSize int64 = 10 << 20 // 10MB
+)
+
+// MultipartLimits returns a middleware that enforces limits on multipart/form-data
+// request bodies while they stream in: the first file part beyond maxFiles, the first
+// file part larger than maxFileSize bytes, or form values and parts without a form
+// name totalling more than maxMemory bytes get 413 Request Entity Too Large (a JSON
+// ErrorResponse inside JSONErrors) without reading the rest of the body. The whole body
+// is also capped at maxMemory plus maxFiles times maxFileSize bytes, so multipart framing
+// such as the preamble and part headers cannot grow it past the limits either. A body that is not valid multipart gets
+// 400 Bad Request. Requests with other content types pass through.
+//
+// The body read so far is kept in memory up to maxMemory bytes and in a temporary file
+// beyond that. Once every part is within the limits, it is parsed with
+// http.Request.ParseMultipartForm(maxMemory), so the form is available to the handler
+// in r.MultipartForm, r.FormFile and r.FormValue.
+// If maxMemory, maxFiles or maxFileSize is not positive, it defaults to 32MB, 10 or
+// 10MB respectively with a warning log.
+func MultipartLimits(maxMemory int64, maxFiles int, maxFileSize int64) func(http.Handler) http.Handler {
+	if maxMemory <= 0 {
+		slog.Warn("middleware: maxMemory must be positive, using default",
+			"provided", maxMemory, "default", defaultMultipartMaxMemory)
+
+		maxMemory = defaultMultipartMaxMemory
+	}
+
+	if maxFiles <= 0 {
+		slog.Warn("middleware: maxFiles must be positive, using default",
+			"provided", maxFiles, "default", defaultMultipartMaxFiles)
+
+		maxFiles = defaultMultipartMaxFiles
+	}
+
+	if maxFileSize <= 0 {
+		slog.Warn("middleware: maxFileSize must be positive, using default",
+			"provided", maxFileSize, "default", defaultMultipartMaxFileSize)
+
+		maxFileSize = defaultMultipartMaxFileSize
+	}
+
+	limits := multipartLimits{maxMemory: maxMemory, maxFiles: maxFiles, maxFileSize: maxFileSize}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "multipart/form-data" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			spool := &multipartSpool{limit: maxMemory}
+			defer spool.remove()
+
+			body := io.TeeReader(http.MaxBytesReader(w, r.Body, limits.maxBodySize()), spool)
+
+			status, msg := limits.check(multipart.NewReader(body, params["boundary"]))
+			if spool.err != nil {
+				slog.Error("middleware: failed to buffer multipart form", "error", spool.err)
+
+				status, msg = http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+			}
+
+			if status != 0 {
+				writeMiddlewareError(w, r, status, msg)
+
+				return
+			}
+
+			spooled, err := spool.reader()
+			if err == nil {
+				r.Body = io.NopCloser(spooled)
+				err = r.ParseMultipartForm(maxMemory)
+			}
+
+			if err != nil {
+				writeMiddlewareError(w, r, http.StatusBadRequest, "invalid multipart form")
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// multipartLimits holds the limits enforced by MultipartLimits.
+type multipartLimits struct {
+	maxMemory   int64
+	maxFiles    int
+	maxFileSize int64
+}
+
+// check reads every part of mr and returns the status and message for the first limit
+// exceeded or read error, or a zero status if the form is within the limits.
+func (l multipartLimits) check(mr *multipart.Reader) (int, string) {
+	files := 0
+	valueBytes := int64(0)
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return 0, ""
+		}
+
+		if err != nil {
+			return multipartReadError(err)
+		}
+
+		// Parts without a form name are not part of the form but still spooled, so
+		// they count against the value budget like form values.
+		limit := l.maxMemory - valueBytes
+
+		if part.FileName() != "" {
+			files++
+			if files > l.maxFiles {
+				return http.StatusRequestEntityTooLarge, "too many files in multipart form"
+			}
+
+			limit = l.maxFileSize
+		}
+
+		n, err := io.Copy(io.Discard, io.LimitReader(part, limit+1))
+		if err != nil {
+			return multipartReadError(err)
+		}
+
+		if n > limit {
+			if part.FileName() != "" {
+				return http.StatusRequestEntityTooLarge, "multipart file too large"
+			}
+
+			return http.StatusRequestEntityTooLarge, "multipart form values too large"
+		}
+
+		if part.FileName() == "" {
+			valueBytes += n
+		}
+	}
+}
+
+// maxBodySize returns the largest body the limits allow: maxMemory for values and
+// framing plus maxFiles files of maxFileSize bytes, saturating at math.MaxInt64.
+func (l multipartLimits) maxBodySize() int64 {
+	if l.maxFileSize > (math.MaxInt64-l.maxMemory)/int64(l.maxFiles) {
+		return math.MaxInt64
+	}
+
+	return l.maxMemory + int64(l.maxFiles)*l.maxFileSize
+}
+
+// multipartReadError maps an error reading the multipart body to a status and message.
+func multipartReadError(err error) (int, string) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge, "request body too large"
+	}
+
+	return http.StatusBadRequest, "invalid multipart form"
+}
+
+// multipartSpool keeps a copy of the bytes written to it, in memory up to limit bytes
+// and in a temporary file beyond that. A write error is recorded in err.
+type multipartSpool struct {
+	buf   bytes.Buffer
+	file  *os.File
+	limit int64
+	err   error
+}
+
+func (s *multipartSpool) Write(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	if s.file == nil && int64(s.buf.Len()+len(p)) > s.limit {
+		s.file, s.err = os.CreateTemp("", "multipart-limits-")
+		if s.err != nil {
+			s.err = fmt.Errorf("creating multipart spool file: %w", s.err)
+
+			return 0, s.err
+		}
+
+		_, s.err = s.buf.WriteTo(s.file)
+		if s.err != nil {
+			return 0, s.err //nolint:wrapcheck
+		}
+	}
+
+	if s.file == nil {
+		return s.buf.Write(p) //nolint:wrapcheck
+	}
+
+	n, err := s.file.Write(p)
+	if err != nil {
+		s.err = err
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// reader returns the spooled bytes from the start.
+func (s *multipartSpool) reader() (io.Reader, error) {
+	if s.file == nil {
+		return &s.buf, nil
+	}
+
+	_, err := s.file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("rewinding multipart spool file: %w", err)
+	}
+
+	return s.file, nil
+}
+
+// remove deletes the temporary file, if any.
+func (s *multipartSpool) remove() {
+	if s.file != nil {
+		_ = s.file.Close()
+		_ = os.Remove(s.file.Name())
+	}
+}
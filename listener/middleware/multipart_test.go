@@ -0,0 +1,274 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// multipartRequest builds a POST request with a "title" field and one file part per
+// entry of files, named after its key.
+func multipartRequest(t *testing.T, files map[string]string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("title", "report"))
+
+	for name, content := range files {
+		part, err := mw.CreateFormFile("upload", name)
+		require.NoError(t, err)
+
+		_, err = io.WriteString(part, content)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	return req
+}
+
+// uploadHandler responds with the title field and the number of uploaded files.
+func uploadHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.MultipartForm == nil {
+			http.Error(w, "form not parsed", http.StatusInternalServerError)
+
+			return
+		}
+
+		_, _ = io.WriteString(w, r.FormValue("title")+":"+strings.Repeat("f", len(r.MultipartForm.File["upload"])))
+	})
+}
+
+func TestMultipartLimits_WithinLimits(t *testing.T) {
+	t.Parallel()
+
+	handler := MultipartLimits(1024, 2, 16)(uploadHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, multipartRequest(t, map[string]string{"a.txt": "hello", "b.txt": "world"}))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "report:ff", rr.Body.String(), "handler should see the parsed form")
+}
+
+func TestMultipartLimits_TooManyFiles(t *testing.T) {
+	t.Parallel()
+
+	handler := MultipartLimits(1024, 2, 16)(uploadHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, multipartRequest(t, map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c"}))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Contains(t, rr.Body.String(), "too many files")
+}
+
+func TestMultipartLimits_OversizedFile(t *testing.T) {
+	t.Parallel()
+
+	handler := MultipartLimits(1024, 2, 16)(uploadHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, multipartRequest(t, map[string]string{"big.bin": strings.Repeat("x", 17)}))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Contains(t, rr.Body.String(), "file too large")
+}
+
+func TestMultipartLimits_OversizedFileOnDisk(t *testing.T) {
+	t.Parallel()
+
+	handler := MultipartLimits(8, 2, 16)(uploadHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, multipartRequest(t, map[string]string{"big.bin": strings.Repeat("x", 64)}))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code, "files spilled to disk should be checked too")
+}
+
+func TestMultipartLimits_BodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	handler := MaxRequestSize(64)(MultipartLimits(1024, 2, 1024)(uploadHandler()))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, multipartRequest(t, map[string]string{"big.bin": strings.Repeat("x", 512)}))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+}
+
+func TestMultipartLimits_InvalidAndOtherBodies(t *testing.T) {
+	t.Parallel()
+
+	handler := MultipartLimits(1024, 2, 16)(okHandler())
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("not multipart"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "non-multipart requests should pass through")
+}
+
+func TestMultipartLimits_JSONError(t *testing.T) {
+	t.Parallel()
+
+	handler := Chain(RequestID(), JSONErrors(), MultipartLimits(1024, 1, 16))(uploadHandler())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, multipartRequest(t, map[string]string{"a.txt": "a", "b.txt": "b"}))
+
+	resp := decodeErrorResponse(t, rr, http.StatusRequestEntityTooLarge)
+	assert.Equal(t, "too many files in multipart form", resp.Error)
+}
+
+func TestMultipartLimits_InvalidArguments(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := MultipartLimits(0, -1, 0)(uploadHandler())
+
+	require.Len(t, h.records, 3)
+	assert.Equal(t, defaultMultipartMaxMemory, h.records[0].Attrs["default"])
+	assert.Equal(t, int64(defaultMultipartMaxFiles), h.records[1].Attrs["default"])
+	assert.Equal(t, defaultMultipartMaxFileSize, h.records[2].Attrs["default"])
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, multipartRequest(t, map[string]string{"a.txt": "a"}))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+// failingReader fails every read, standing in for the part of a body that must not be read.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestMultipartLimits_RejectsBeforeReadingRest(t *testing.T) {
+	t.Parallel()
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	part, err := mw.CreateFormFile("upload", "big.bin")
+	require.NoError(t, err)
+
+	_, err = io.WriteString(part, strings.Repeat("x", 64))
+	require.NoError(t, err)
+
+	_, err = mw.CreateFormFile("upload", "next.bin")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", io.MultiReader(&body, failingReader{}))
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	rr := httptest.NewRecorder()
+	MultipartLimits(1024, 2, 16)(uploadHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code, "the oversized file should be rejected before the body ends")
+	assert.Contains(t, rr.Body.String(), "file too large")
+}
+
+func TestMultipartLimits_ValuesTooLarge(t *testing.T) {
+	t.Parallel()
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("notes", strings.Repeat("n", 64)))
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	rr := httptest.NewRecorder()
+	MultipartLimits(32, 2, 16)(uploadHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+}
+
+func TestMultipartLimits_SpoolsLargeBodies(t *testing.T) {
+	t.Parallel()
+
+	handler := MultipartLimits(16, 2, 1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("upload")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		defer func() { _ = file.Close() }()
+
+		content, _ := io.ReadAll(file)
+		_, _ = w.Write(content)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, multipartRequest(t, map[string]string{"a.bin": strings.Repeat("a", 512)}))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, strings.Repeat("a", 512), rr.Body.String(), "the handler should see the complete file")
+}
+
+func TestMultipartLimits_UnnamedPartsCount(t *testing.T) {
+	t.Parallel()
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/octet-stream"}})
+	require.NoError(t, err)
+
+	_, err = part.Write(bytes.Repeat([]byte("x"), 5<<20))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	rr := httptest.NewRecorder()
+	MultipartLimits(1<<10, 1, 1<<10)(uploadHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code, "parts without a form name should not bypass the limits")
+}
+
+func TestMultipartLimits_CapsPreamble(t *testing.T) {
+	t.Parallel()
+
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("title", "report"))
+	require.NoError(t, mw.Close())
+
+	preamble := strings.Repeat("p", 4<<10) + "\r\n"
+	req := httptest.NewRequest(http.MethodPost, "/upload", io.MultiReader(strings.NewReader(preamble), &body))
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	rr := httptest.NewRecorder()
+	MultipartLimits(1<<10, 1, 1<<10)(uploadHandler()).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code, "the body should be capped at maxMemory plus the file budget")
+}
@@ -10,19 +10,27 @@ import (
 )
 
 type tokenBucket struct {
-	mu              sync.Mutex
-	tokens          float64
-	maxTokens       float64
-	refillRate      float64
-	lastRefillTime  time.Time
+	mu             sync.Mutex
+	tokens         float64
+	maxTokens      float64
+	refillRate     float64
+	lastRefillTime time.Time
+	timeNow        func() time.Time
 }
 
-func newTokenBucket(requestsPerSecond float64, burst int) *tokenBucket {
+// newTokenBucket creates a full bucket. timeNow supplies the clock used for
+// replenishment and defaults to time.Now when nil.
+func newTokenBucket(requestsPerSecond float64, burst int, timeNow func() time.Time) *tokenBucket {
+	if timeNow == nil {
+		timeNow = time.Now
+	}
+
 	return &tokenBucket{
 		tokens:         float64(burst),
 		maxTokens:      float64(burst),
 		refillRate:     requestsPerSecond,
-		lastRefillTime: time.Now(),
+		lastRefillTime: timeNow(),
+		timeNow:        timeNow,
 	}
 }
 
@@ -30,7 +38,7 @@ func (tb *tokenBucket) tryAcquire() (bool, time.Duration) {
 	tb.mu.Lock()
 	defer tb.mu.Unlock()
 
-	now := time.Now()
+	now := tb.timeNow()
 	elapsed := max(0.0, now.Sub(tb.lastRefillTime).Seconds())
 	tb.tokens = math.Min(tb.maxTokens, tb.tokens+elapsed*tb.refillRate)
 	tb.lastRefillTime = now
@@ -49,10 +57,34 @@ func (tb *tokenBucket) tryAcquire() (bool, time.Duration) {
 
 // RateLimit returns a middleware that enforces a global rate limit using a
 // token bucket algorithm. When the limit is exceeded, it responds with
-// 429 Too Many Requests and includes a Retry-After header.
+// 429 Too Many Requests and includes a Retry-After header; inside JSONErrors the
+// body is a JSON ErrorResponse.
 // If requestsPerSecond is not positive, it defaults to 1.0 with a warning log.
 // If burst is not positive, it defaults to 1 with a warning log.
 func RateLimit(requestsPerSecond float64, burst int) func(http.Handler) http.Handler {
+	return rateLimit(requestsPerSecond, burst, time.Now)
+}
+
+// rateLimit is RateLimit with an injectable clock, letting tests advance time
+// deterministically instead of sleeping.
+func rateLimit(requestsPerSecond float64, burst int, timeNow func() time.Time) func(http.Handler) http.Handler {
+	requestsPerSecond, burst = normalizeRate(requestsPerSecond, burst)
+	bucket := newTokenBucket(requestsPerSecond, burst, timeNow)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !allowRequest(w, r, bucket) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// normalizeRate replaces a non-positive or non-finite rate with 1.0 and a
+// non-positive burst with 1, logging a warning for each.
+func normalizeRate(requestsPerSecond float64, burst int) (float64, int) {
 	if math.IsNaN(requestsPerSecond) || math.IsInf(requestsPerSecond, 0) || requestsPerSecond <= 0 {
 		slog.Warn("middleware: requestsPerSecond must be a positive finite number, using default",
 			"provided", requestsPerSecond, "default", 1.0)
@@ -65,17 +97,64 @@ func RateLimit(requestsPerSecond float64, burst int) func(http.Handler) http.Han
 		burst = 1
 	}
 
-	bucket := newTokenBucket(requestsPerSecond, burst)
+	return requestsPerSecond, burst
+}
+
+// allowRequest takes a token from bucket. When none is available it responds with
+// 429 Too Many Requests and a Retry-After header and returns false.
+func allowRequest(w http.ResponseWriter, r *http.Request, bucket *tokenBucket) bool {
+	allowed, retryAfter := bucket.tryAcquire()
+	if allowed {
+		return true
+	}
+
+	seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	writeMiddlewareError(w, r, http.StatusTooManyRequests, "Too Many Requests")
+
+	return false
+}
+
+// AnyMethod is the RateLimitByMethod key whose limit applies to methods without their own entry.
+const AnyMethod = "*"
+
+// RateSpec is a token bucket rate limit: RequestsPerSecond tokens are added per second,
+// up to Burst tokens.
+type RateSpec struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// RateLimitByMethod returns a middleware that enforces a separate global rate limit per
+// HTTP method, e.g. a stricter limit for POST than for GET. Each entry of limits gets its
+// own token bucket; method names are matched case-sensitively, as in http.Request.Method.
+// The AnyMethod ("*") entry is a single bucket shared by all methods without an entry;
+// without it those methods are not limited. Invalid rates and bursts default to 1 with a
+// warning log, as in RateLimit. Rejected requests get 429 Too Many Requests with Retry-After.
+func RateLimitByMethod(limits map[string]RateSpec) func(http.Handler) http.Handler {
+	return rateLimitByMethod(limits, time.Now)
+}
+
+// rateLimitByMethod is RateLimitByMethod with an injectable clock.
+func rateLimitByMethod(limits map[string]RateSpec, timeNow func() time.Time) func(http.Handler) http.Handler {
+	buckets := make(map[string]*tokenBucket, len(limits))
+
+	for method, spec := range limits {
+		requestsPerSecond, burst := normalizeRate(spec.RequestsPerSecond, spec.Burst)
+		buckets[method] = newTokenBucket(requestsPerSecond, burst, timeNow)
+	}
+
+	fallback := buckets[AnyMethod]
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			allowed, retryAfter := bucket.tryAcquire()
-			if !allowed {
-				seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
-
-				w.Header().Set("Retry-After", strconv.Itoa(seconds))
-				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			bucket, ok := buckets[r.Method]
+			if !ok {
+				bucket = fallback
+			}
 
+			if bucket != nil && !allowRequest(w, r, bucket) {
 				return
 			}
 
@@ -6,6 +6,7 @@ import (
 	"math"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -51,32 +52,119 @@ func TestRateLimit_ExceedingLimitReturns429(t *testing.T) { //nolint:paralleltes
 	assert.Contains(t, rr.Body.String(), "Too Many Requests")
 }
 
-func TestRateLimit_TokenReplenishmentOverTime(t *testing.T) { //nolint:paralleltest // uses shared rate limiter state
-	handler := RateLimit(100, 1)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+// fakeClock is a manually advanced clock for deterministic rate limiter tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// serveStatus sends a GET request through handler and returns the response status code.
+func serveStatus(handler http.Handler) int {
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	return rr.Code
+}
+
+func TestRateLimit_TokenReplenishmentOverTime(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	handler := rateLimit(100, 1, clock.Now)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
 
 	// Use the single burst token.
-	rr := httptest.NewRecorder()
-	req := httptest.NewRequest(http.MethodGet, "/", nil)
-	handler.ServeHTTP(rr, req)
-	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, http.StatusOK, serveStatus(handler))
 
 	// Should be limited now.
-	rr = httptest.NewRecorder()
-	req = httptest.NewRequest(http.MethodGet, "/", nil)
-	handler.ServeHTTP(rr, req)
-	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	require.Equal(t, http.StatusTooManyRequests, serveStatus(handler))
 
-	// Wait for tokens to replenish (100 rps = 10ms per token, wait a bit more).
-	time.Sleep(50 * time.Millisecond)
+	// 100 rps = 10ms per token; half a token is not enough.
+	clock.Advance(5 * time.Millisecond)
+	require.Equal(t, http.StatusTooManyRequests, serveStatus(handler))
 
 	// Should succeed again after replenishment.
+	clock.Advance(5 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, serveStatus(handler))
+}
+
+func TestRateLimit_ReplenishmentCappedAtBurst(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	handler := rateLimit(10, 3, clock.Now)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for range 3 {
+		require.Equal(t, http.StatusOK, serveStatus(handler))
+	}
+
+	require.Equal(t, http.StatusTooManyRequests, serveStatus(handler))
+
+	// An hour of idle time refills only up to the burst size.
+	clock.Advance(time.Hour)
+
+	for range 3 {
+		require.Equal(t, http.StatusOK, serveStatus(handler))
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, serveStatus(handler))
+}
+
+func TestRateLimit_RetryAfterFromFakeClock(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	handler := rateLimit(0.25, 1, clock.Now)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	require.Equal(t, http.StatusOK, serveStatus(handler))
+
+	// 0.25 rps = 4s per token.
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "4", rr.Header().Get("Retry-After"))
+
+	clock.Advance(3 * time.Second)
+
 	rr = httptest.NewRecorder()
-	req = httptest.NewRequest(http.MethodGet, "/", nil)
-	handler.ServeHTTP(rr, req)
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
 
-	assert.Equal(t, http.StatusOK, rr.Code)
+	clock.Advance(time.Second)
+	assert.Equal(t, http.StatusOK, serveStatus(handler))
+}
+
+func TestNewTokenBucket_DefaultsToTimeNow(t *testing.T) {
+	t.Parallel()
+
+	bucket := newTokenBucket(1, 1, nil)
+
+	require.NotNil(t, bucket.timeNow)
+	assert.WithinDuration(t, time.Now(), bucket.lastRefillTime, time.Second)
 }
 
 func TestRateLimit_RetryAfterHeader(t *testing.T) { //nolint:paralleltest // uses shared rate limiter state
@@ -244,3 +332,96 @@ func TestRateLimit_DefaultsOnInf(t *testing.T) { //nolint:paralleltest // uses s
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Contains(t, buf.String(), "requestsPerSecond must be a positive finite number")
 }
+
+// serveMethodStatus sends a request with method through handler and returns the response status code.
+func serveMethodStatus(handler http.Handler, method string) int {
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(method, "/", nil))
+
+	return rr.Code
+}
+
+func TestRateLimitByMethod_IndependentBuckets(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	handler := rateLimitByMethod(map[string]RateSpec{
+		http.MethodGet:  {RequestsPerSecond: 10, Burst: 5},
+		http.MethodPost: {RequestsPerSecond: 1, Burst: 1},
+	}, clock.Now)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	assert.Equal(t, http.StatusOK, serveMethodStatus(handler, http.MethodPost))
+	assert.Equal(t, http.StatusTooManyRequests, serveMethodStatus(handler, http.MethodPost),
+		"POST burst of 1 should be exhausted")
+
+	for i := range 5 {
+		assert.Equal(t, http.StatusOK, serveMethodStatus(handler, http.MethodGet),
+			"GET request %d should not be affected by POST throttling", i+1)
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, serveMethodStatus(handler, http.MethodGet))
+
+	// 200ms refills two GET tokens but not a single POST token.
+	clock.Advance(200 * time.Millisecond)
+
+	assert.Equal(t, http.StatusTooManyRequests, serveMethodStatus(handler, http.MethodPost))
+	assert.Equal(t, http.StatusOK, serveMethodStatus(handler, http.MethodGet))
+	assert.Equal(t, http.StatusOK, serveMethodStatus(handler, http.MethodGet))
+	assert.Equal(t, http.StatusTooManyRequests, serveMethodStatus(handler, http.MethodGet))
+
+	clock.Advance(800 * time.Millisecond)
+
+	assert.Equal(t, http.StatusOK, serveMethodStatus(handler, http.MethodPost))
+}
+
+func TestRateLimitByMethod_DefaultForUnlistedMethods(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	handler := rateLimitByMethod(map[string]RateSpec{
+		http.MethodGet: {RequestsPerSecond: 100, Burst: 100},
+		AnyMethod:      {RequestsPerSecond: 1, Burst: 1},
+	}, clock.Now)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	assert.Equal(t, http.StatusOK, serveMethodStatus(handler, http.MethodPut))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/", nil))
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, "unlisted methods share the default bucket")
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, serveMethodStatus(handler, http.MethodGet))
+}
+
+func TestRateLimitByMethod_UnlistedMethodsUnlimitedWithoutDefault(t *testing.T) {
+	t.Parallel()
+
+	handler := RateLimitByMethod(map[string]RateSpec{
+		http.MethodPost: {RequestsPerSecond: 1, Burst: 1},
+	})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for range 10 {
+		assert.Equal(t, http.StatusOK, serveMethodStatus(handler, http.MethodGet))
+	}
+}
+
+func TestRateLimitByMethod_InvalidSpecUsesDefaults(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	clock := newFakeClock()
+	handler := rateLimitByMethod(map[string]RateSpec{
+		http.MethodPost: {RequestsPerSecond: 0, Burst: -1},
+	}, clock.Now)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	require.Len(t, h.records, 2)
+	assert.Equal(t, http.StatusOK, serveMethodStatus(handler, http.MethodPost))
+	assert.Equal(t, http.StatusTooManyRequests, serveMethodStatus(handler, http.MethodPost))
+}
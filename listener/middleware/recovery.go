@@ -2,11 +2,15 @@ package middleware
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"reflect"
+	"runtime"
 	"runtime/debug"
+	"strings"
 )
 
 // recoveryWriter wraps http.ResponseWriter to track whether headers have been sent.
@@ -62,12 +66,128 @@ func (w *recoveryWriter) Unwrap() http.ResponseWriter {
 	return w.ResponseWriter
 }
 
+// responseBuffer is implemented by response writers that buffer output before
+// sending it downstream, such as gzipResponseWriter.
+type responseBuffer interface {
+	// discardUncommitted drops buffered output if none of it has been sent
+	// downstream yet and reports whether it did.
+	discardUncommitted() bool
+}
+
+// responseSent reports whether any part of the response may have reached the client.
+// Writes recorded by w can still be pending in a buffering writer further down the
+// chain; if the first such writer has not committed yet, its buffer is discarded and
+// the response is considered unsent.
+func (w *recoveryWriter) responseSent() bool {
+	if !w.written {
+		return false
+	}
+
+	next := w.ResponseWriter
+
+	for next != nil {
+		if buffer, ok := next.(responseBuffer); ok {
+			return !buffer.discardUncommitted()
+		}
+
+		unwrapper, ok := next.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+
+		next = unwrapper.Unwrap()
+	}
+
+	return true
+}
+
+// recoveryConfig holds configuration for the Recovery middleware.
+type recoveryConfig struct {
+	statusCode   func(panicVal any) int
+	requestAttrs func(r *http.Request) []slog.Attr
+	trimStack    bool
+}
+
+// RecoveryOption configures the Recovery middleware.
+type RecoveryOption func(*recoveryConfig)
+
+// WithRecoveryStatusCode sets a function that maps the recovered panic value to the
+// HTTP status code of the error response (e.g., 502 for upstream panics, 503 for
+// capacity panics). If fn returns a value outside the 100-599 range (such as 0),
+// the default 500 Internal Server Error is used.
+func WithRecoveryStatusCode(fn func(panicVal any) int) RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.statusCode = fn
+	}
+}
+
+// WithRequestAttrs sets a function whose attributes are added to the recovered-panic
+// log entry, e.g. the remote address or User-Agent of the request. It is called only
+// when a panic is recovered and must not panic itself.
+func WithRequestAttrs(fn func(r *http.Request) []slog.Attr) RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.requestAttrs = fn
+	}
+}
+
+// WithStackTrim logs a concise stack containing only the frames between the panic and
+// the handler Recovery wraps: the recover machinery above the panic, and the net/http
+// and middleware frames below the handler, are dropped. The full debug.Stack output is
+// logged by default, and is still used when the panic frames cannot be located.
+func WithStackTrim() RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.trimStack = true
+	}
+}
+
+// ConstantRecoveryStatus returns a status code function for WithRecoveryStatusCode
+// that maps every panic value to code.
+func ConstantRecoveryStatus(code int) func(panicVal any) int {
+	return func(any) int {
+		return code
+	}
+}
+
+const (
+	minHTTPStatus = 100
+	maxHTTPStatus = 599
+)
+
+func (c *recoveryConfig) status(panicVal any) int {
+	if c.statusCode == nil {
+		return http.StatusInternalServerError
+	}
+
+	code := c.statusCode(panicVal)
+	if code < minHTTPStatus || code > maxHTTPStatus {
+		return http.StatusInternalServerError
+	}
+
+	return code
+}
+
 // Recovery returns a middleware that recovers from panics in downstream handlers.
-// It logs the panic value and stack trace via global slog.Error and responds
-// with 500 Internal Server Error. If a request ID is available in the context,
+// It logs the panic value, its Go type ("panic_type"), the error message for error
+// values ("error") and the stack trace via global slog.Error and responds
+// with 500 Internal Server Error (a JSON ErrorResponse inside JSONErrors). If a request ID is available in the context,
 // it is included in the log entry. If the response has already been partially
-// written, it logs an error instead of attempting to write a 500 status.
-func Recovery() func(http.Handler) http.Handler {
+// written, it logs an error instead of attempting to write an error status.
+// Output still held by a buffering writer (such as Compress before it commits)
+// does not count as written: it is discarded and the error status is sent.
+//
+// Options:
+//   - WithRecoveryStatusCode(fn) - map the panic value to a custom status code
+//   - WithRequestAttrs(fn) - add request-derived attributes to the panic log entry
+//   - WithStackTrim() - log only the stack frames between the panic and the handler
+func Recovery(opts ...RecoveryOption) func(http.Handler) http.Handler {
+	cfg := recoveryConfig{}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			recWriter := &recoveryWriter{ResponseWriter: w}
@@ -80,28 +200,48 @@ func Recovery() func(http.Handler) http.Handler {
 					}
 
 					stack := debug.Stack()
+					if cfg.trimStack {
+						stack = trimmedStack(stack)
+					}
 
 					attrs := []any{
 						slog.String("panic", fmt.Sprintf("%v", rec)),
+						slog.String("panic_type", fmt.Sprintf("%T", rec)),
+					}
+
+					if ok {
+						attrs = append(attrs, slog.String("error", err.Error()))
+					}
+
+					attrs = append(attrs,
 						slog.String("stack", string(stack)),
 						slog.String("method", r.Method),
 						slog.String("path", r.URL.Path),
-					}
+					)
 
 					if reqID := GetRequestID(r.Context()); reqID != "" {
 						attrs = append(attrs, slog.String("request_id", reqID))
 					}
 
-					if recWriter.written {
+					if cfg.requestAttrs != nil {
+						for _, attr := range cfg.requestAttrs(r) {
+							attrs = append(attrs, attr)
+						}
+					}
+
+					if recWriter.responseSent() {
 						attrs = append(attrs, slog.Bool("response_already_written", true))
 						slog.Error("panic recovered after response was already written", attrs...) //nolint:gosec
 
 						return
 					}
 
+					status := cfg.status(rec)
+					attrs = append(attrs, slog.Int("status", status))
+
 					slog.Error("panic recovered", attrs...) //nolint:gosec // G706: message is a hardcoded constant.
 
-					http.Error(recWriter, "Internal Server Error", http.StatusInternalServerError)
+					writeMiddlewareError(recWriter, r, status, http.StatusText(status))
 				}
 			}()
 
@@ -109,3 +249,58 @@ func Recovery() func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// maxStackDepth is the number of frames inspected when trimming a panic stack.
+const maxStackDepth = 64
+
+// middlewarePkgPath is the import path of this package, the prefix of its function names.
+var middlewarePkgPath = reflect.TypeFor[recoveryConfig]().PkgPath() //nolint:gochecknoglobals
+
+// trimmedStack returns the frames of the current goroutine from the panicking function
+// down to, but excluding, the first net/http or middleware frame below it, formatted
+// like debug.Stack. It must be called from the deferred recover function. If no
+// panic frame is found, fullStack is returned.
+func trimmedStack(fullStack []byte) []byte {
+	pcs := make([]uintptr, maxStackDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+
+	var (
+		buf       bytes.Buffer
+		panicking bool
+	)
+
+	for {
+		frame, more := frames.Next()
+
+		switch {
+		case !panicking:
+			panicking = frame.Function == "runtime.gopanic"
+		case isHandlerBoundary(frame):
+			more = false
+		case buf.Len() == 0 && strings.HasPrefix(frame.Function, "runtime."):
+			// Runtime helpers that raised the panic, such as runtime.sigpanic.
+		default:
+			fmt.Fprintf(&buf, "%s(...)\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	if buf.Len() == 0 {
+		return fullStack
+	}
+
+	return buf.Bytes()
+}
+
+// isHandlerBoundary reports whether frame belongs to the code that called the user
+// handler: net/http or a non-test file of this package.
+func isHandlerBoundary(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "net/http.") {
+		return true
+	}
+
+	return strings.HasPrefix(frame.Function, middlewarePkgPath+".") && !strings.HasSuffix(frame.File, "_test.go")
+}
@@ -4,13 +4,16 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"log/slog"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRecovery_PanicReturns500(t *testing.T) { //nolint:paralleltest // modifies global slog default
@@ -53,6 +56,139 @@ func TestRecovery_LogsPanicAndStack(t *testing.T) { //nolint:paralleltest // mod
 	assert.Contains(t, logOutput, http.MethodGet)
 }
 
+func TestRecovery_LogsPanicTypeAndError(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	tests := []struct {
+		name      string
+		value     any
+		wantPanic string
+		wantType  string
+		wantError string
+	}{
+		{name: "string", value: "boom", wantPanic: "boom", wantType: "string"},
+		{name: "error", value: errors.New("db down"), wantPanic: "db down", wantType: "*errors.errorString", wantError: "db down"},
+		{name: "integer", value: 42, wantPanic: "42", wantType: "int"},
+	}
+
+	for _, tt := range tests {
+		h.records = nil
+
+		handler := Recovery()(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic(tt.value)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		require.Len(t, h.records, 1, tt.name)
+
+		attrs := h.records[0].Attrs
+		assert.Equal(t, tt.wantPanic, attrs["panic"], tt.name)
+		assert.Equal(t, tt.wantType, attrs["panic_type"], tt.name)
+
+		if tt.wantError == "" {
+			assert.NotContains(t, attrs, "error", tt.name)
+		} else {
+			assert.Equal(t, tt.wantError, attrs["error"], tt.name)
+		}
+	}
+}
+
+// panickingHandler panics through a helper so the trimmed stack has more than one frame.
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	panicHelper(w, r)
+}
+
+func panicHelper(http.ResponseWriter, *http.Request) {
+	panic("trimmed")
+}
+
+func TestRecovery_WithStackTrim(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Chain(Logging(), Recovery(WithStackTrim()))(http.HandlerFunc(panickingHandler))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	var stack string
+
+	for _, record := range h.records {
+		if record.Message == "panic recovered" {
+			stack, _ = record.Attrs["stack"].(string)
+		}
+	}
+
+	require.NotEmpty(t, stack)
+	assert.True(t, strings.HasPrefix(stack, middlewarePkgPath+".panicHelper("), "stack should start at the panic: %s", stack)
+	assert.Contains(t, stack, middlewarePkgPath+".panickingHandler(")
+	assert.Contains(t, stack, "recovery_test.go:")
+	assert.NotContains(t, stack, "recovery.go:", "Recovery frames should be trimmed")
+	assert.NotContains(t, stack, "logging.go:", "outer middleware frames should be trimmed")
+	assert.NotContains(t, stack, "net/http.")
+	assert.NotContains(t, stack, "runtime/debug")
+	assert.NotContains(t, stack, "goroutine")
+}
+
+func TestRecovery_FullStackByDefault(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Recovery()(http.HandlerFunc(panickingHandler))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	require.Len(t, h.records, 1)
+
+	stack, _ := h.records[0].Attrs["stack"].(string)
+	assert.Contains(t, stack, "goroutine")
+	assert.Contains(t, stack, "recovery.go:")
+	assert.Contains(t, stack, "panickingHandler")
+}
+
+func TestRecovery_WithRequestAttrs(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Recovery(WithRequestAttrs(func(r *http.Request) []slog.Attr {
+		return []slog.Attr{
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.String("user_agent", r.UserAgent()),
+		}
+	}))(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.9:4711"
+	req.Header.Set("User-Agent", "test-agent/1.0")
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Len(t, h.records, 1)
+	assert.Equal(t, "203.0.113.9:4711", h.records[0].Attrs["remote_addr"])
+	assert.Equal(t, "test-agent/1.0", h.records[0].Attrs["user_agent"])
+	assert.Equal(t, "boom", h.records[0].Attrs["panic"], "default attributes should be kept")
+}
+
+func TestRecovery_WithRequestAttrsNotCalledWithoutPanic(t *testing.T) {
+	t.Parallel()
+
+	called := false
+
+	handler := Recovery(WithRequestAttrs(func(*http.Request) []slog.Attr {
+		called = true
+
+		return nil
+	}))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.False(t, called)
+}
+
 func TestRecovery_IncludesRequestIDInLog(t *testing.T) { //nolint:paralleltest // modifies global slog default
 	var buf bytes.Buffer
 
@@ -296,3 +432,176 @@ func TestRecovery_NoPanicPassesThrough(t *testing.T) { //nolint:paralleltest //
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Equal(t, "ok", rec.Body.String())
 }
+
+func TestRecovery_WithRecoveryStatusCode(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	setupTestLogger(t)
+
+	statusFor := func(panicVal any) int {
+		if panicVal == "upstream unavailable" {
+			return http.StatusBadGateway
+		}
+
+		return 0
+	}
+
+	tests := []struct {
+		name       string
+		panicVal   any
+		wantStatus int
+	}{
+		{"mapped string value", "upstream unavailable", http.StatusBadGateway},
+		{"other string falls back", "something else", http.StatusInternalServerError},
+		{"non-string falls back", 42, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Recovery(WithRecoveryStatusCode(statusFor))(
+				http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+					panic(tt.panicVal)
+				}),
+			)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), http.StatusText(tt.wantStatus))
+		})
+	}
+}
+
+func TestRecovery_ConstantRecoveryStatus(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Recovery(WithRecoveryStatusCode(ConstantRecoveryStatus(http.StatusServiceUnavailable)))(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("capacity exceeded")
+		}),
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, int64(http.StatusServiceUnavailable), h.records[0].Attrs["status"])
+}
+
+func TestRecovery_CompressPanicBeforeFlushReturns500(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	tests := []struct {
+		name  string
+		chain func(http.Handler) http.Handler
+	}{
+		{name: "recovery inside compress", chain: Chain(Compress(), Recovery())},
+		{name: "recovery outside compress", chain: Chain(Recovery(), Compress())},
+	}
+
+	for _, tt := range tests {
+		h.records = nil
+
+		handler := tt.chain(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusOK)
+			// Smaller than the compression threshold, so it stays buffered.
+			_, _ = w.Write([]byte("partial response"))
+
+			panic("panic before flush")
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code, tt.name)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"), tt.name)
+		assert.Equal(t, "Internal Server Error\n", rec.Body.String(), tt.name)
+		assert.NotContains(t, rec.Body.String(), "partial response", tt.name)
+
+		require.Len(t, h.records, 1, tt.name)
+		assert.Equal(t, "panic recovered", h.records[0].Message, tt.name)
+	}
+}
+
+func TestRecovery_CompressPanicWhileGzipHeldReturns500(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Chain(Compress(), Recovery())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		// Exceeds the compression threshold, but the compressed output is still held back.
+		_, _ = w.Write(bytes.Repeat([]byte("a"), 2*minCompressSize))
+
+		panic("panic after commit")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Internal Server Error\n", rec.Body.String())
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, "panic recovered", h.records[0].Message)
+}
+
+func TestRecovery_CompressPanicAfterStreamingKeepsStatus(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Chain(Compress(), Recovery())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		// Incompressible data larger than the held-back limit, so gzip starts streaming.
+		_, _ = w.Write(incompressible(2 * maxBufferedGzipSize))
+
+		panic("panic after commit")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, "panic recovered after response was already written", h.records[0].Message)
+}
+
+func TestRecovery_CompressPanicAfterFlushKeepsStatus(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	handler := Chain(Compress(), Recovery())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("partial response"))
+
+		require.NoError(t, http.NewResponseController(w).Flush())
+
+		panic("panic after flush")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "partial response")
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, "panic recovered after response was already written", h.records[0].Message)
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"hash/fnv"
 	"log/slog"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -22,41 +25,71 @@ const (
 	// snowflakeEpochMs is 2026-01-01 00:00:00 UTC in milliseconds since Unix epoch.
 	snowflakeEpochMs int64 = 1767225600000
 
-	// Bit widths for snowflake ID components.
+	// Default bit widths for snowflake ID components.
 	snowflakeMachineBits  = 16
 	snowflakeSequenceBits = 7
 
-	// Masks for snowflake ID components.
+	// snowflakeTimestampBits is the fixed width of the timestamp component. The remaining
+	// 23 bits of the 64-bit ID are split between machine and sequence bits.
+	snowflakeTimestampBits = 41
+
+	// snowflakeNodeBits is the number of bits shared by the machine and sequence components.
+	snowflakeNodeBits = 64 - snowflakeTimestampBits
+
+	// Masks for snowflake ID components with the default bit widths.
 	snowflakeMaxSequence uint64 = (1 << snowflakeSequenceBits) - 1 // 0x7F = 127
-	snowflakeMachineMask uint64 = (1 << snowflakeMachineBits) - 1 // 0xFFFF = 65535
+	snowflakeMachineMask uint64 = (1 << snowflakeMachineBits) - 1  // 0xFFFF = 65535
 
 	// maxClockDriftMs is the maximum backward clock drift (in ms) the generator
 	// will spin-wait for. Beyond this threshold it resets rather than blocking.
 	maxClockDriftMs int64 = 500
 
-	// Bit shifts for composing the snowflake ID.
-	snowflakeMachineShift   = snowflakeSequenceBits                          // 7
-	snowflakeTimestampShift = snowflakeSequenceBits + snowflakeMachineBits   // 23
+	// Bit shifts for composing the snowflake ID with the default bit widths.
+	snowflakeMachineShift   = snowflakeSequenceBits                        // 7
+	snowflakeTimestampShift = snowflakeSequenceBits + snowflakeMachineBits // 23
+)
+
+// defaultHexRequestIDLength is the length of generated snowflake IDs, used by
+// ValidateHex when the provided length is not positive.
+const defaultHexRequestIDLength = 16
+
+// uuidLength is the length of a canonical textual UUID.
+const uuidLength = 36
+
+var (
+	// ErrInvalidSnowflakeBits is returned by NewCustomSnowflakeGenerator when the machine
+	// and sequence bit widths do not fit in 23 bits or the sequence has no bits.
+	ErrInvalidSnowflakeBits = errors.New("invalid snowflake bit allocation")
+
+	// ErrInvalidSnowflakeEpoch is returned by NewCustomSnowflakeGenerator when the epoch
+	// is negative or in the future.
+	ErrInvalidSnowflakeEpoch = errors.New("invalid snowflake epoch")
 )
 
 type requestIDKeyType struct{}
 
 var requestIDKey = requestIDKeyType{} //nolint:gochecknoglobals
 
-// snowflakeGenerator produces snowflake-like unique IDs composed of
-// 41 bits timestamp (ms since 2026-01-01 UTC), 16 bits machine hash,
-// and 7 bits sequence counter.
-type snowflakeGenerator struct {
-	mu            sync.Mutex
-	machineID     uint64
-	sequence      uint64
-	lastTimestamp int64
-	timeNow       func() time.Time
+// SnowflakeGenerator produces snowflake-like unique IDs composed of
+// 41 bits timestamp (ms since 2026-01-01 UTC by default), a machine hash and a
+// sequence counter sharing the remaining 23 bits (16 and 7 bits by default).
+// Create one with NewCustomSnowflakeGenerator; it is safe for concurrent use.
+type SnowflakeGenerator struct {
+	mu             sync.Mutex
+	epochMs        int64
+	machineID      uint64
+	sequence       uint64
+	maxSequence    uint64
+	machineShift   uint
+	timestampShift uint
+	lastTimestamp  int64
+	timeNow        func() time.Time
 }
 
-// newSnowflakeGenerator creates a snowflake generator with machine ID
-// derived from FNV-1a hash of the hostname.
-func newSnowflakeGenerator() *snowflakeGenerator {
+// newSnowflakeGenerator creates a snowflake generator with the given machine and
+// sequence bit widths and a machine ID derived from FNV-1a hash of the hostname.
+// Callers must ensure the widths are valid (see validSnowflakeBits).
+func newSnowflakeGenerator(machineBits, sequenceBits int) *SnowflakeGenerator {
 	hostname, err := os.Hostname()
 	if err != nil {
 		slog.Warn("middleware: failed to get hostname for snowflake generator, using empty string",
@@ -68,14 +101,54 @@ func newSnowflakeGenerator() *snowflakeGenerator {
 	h := fnv.New64a()
 	_, _ = h.Write([]byte(hostname))
 
-	return &snowflakeGenerator{
-		machineID: h.Sum64() & snowflakeMachineMask,
-		timeNow:   time.Now,
+	machineMask := uint64(1)<<machineBits - 1
+
+	return &SnowflakeGenerator{
+		epochMs:        snowflakeEpochMs,
+		machineID:      h.Sum64() & machineMask,
+		maxSequence:    uint64(1)<<sequenceBits - 1,
+		machineShift:   uint(sequenceBits),
+		timestampShift: uint(sequenceBits + machineBits),
+		timeNow:        time.Now,
 	}
 }
 
+// NewCustomSnowflakeGenerator creates a snowflake generator counting milliseconds since
+// epochMs (Unix milliseconds) with the given machine and sequence bit widths, for use
+// with WithSnowflakeGenerator. The machine and sequence masks are derived from the widths
+// and the timestamp takes the remaining high bits; as with WithSnowflakeBits,
+// machineBits+sequenceBits may be at most 23. It returns ErrInvalidSnowflakeBits if the
+// widths do not fit or sequenceBits is 0, and ErrInvalidSnowflakeEpoch if epochMs is
+// negative or in the future.
+func NewCustomSnowflakeGenerator(epochMs int64, machineBits, sequenceBits uint8) (*SnowflakeGenerator, error) {
+	if !validSnowflakeBits(int(machineBits), int(sequenceBits)) {
+		return nil, fmt.Errorf("%w: %d machine + %d sequence bits, want at most %d with at least 1 sequence bit",
+			ErrInvalidSnowflakeBits, machineBits, sequenceBits, snowflakeNodeBits)
+	}
+
+	if epochMs < 0 || epochMs > time.Now().UnixMilli() {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidSnowflakeEpoch, epochMs)
+	}
+
+	gen := newSnowflakeGenerator(int(machineBits), int(sequenceBits))
+	gen.epochMs = epochMs
+
+	return gen, nil
+}
+
+// Generate returns a new unique 16-character hex snowflake ID.
+func (g *SnowflakeGenerator) Generate() string {
+	return g.generate()
+}
+
+// validSnowflakeBits reports whether the machine and sequence widths fit beside
+// the 41-bit timestamp. The sequence needs at least one bit; the machine may use none.
+func validSnowflakeBits(machineBits, sequenceBits int) bool {
+	return machineBits >= 0 && sequenceBits >= 1 && machineBits+sequenceBits <= snowflakeNodeBits
+}
+
 // generate produces a unique 16-character hex string snowflake ID.
-func (g *snowflakeGenerator) generate() string {
+func (g *SnowflakeGenerator) generate() string {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
@@ -106,7 +179,7 @@ func (g *snowflakeGenerator) generate() string {
 		g.sequence++
 
 		// Sequence overflow: spin-wait until next millisecond.
-		if g.sequence > snowflakeMaxSequence {
+		if g.sequence > g.maxSequence {
 			for now <= g.lastTimestamp {
 				now = g.currentTimestampMs()
 			}
@@ -121,8 +194,8 @@ func (g *snowflakeGenerator) generate() string {
 
 	ts := max(now, 0)
 
-	id := (uint64(ts) << snowflakeTimestampShift) |
-		(g.machineID << snowflakeMachineShift) |
+	id := (uint64(ts) << g.timestampShift) |
+		(g.machineID << g.machineShift) |
 		g.sequence
 
 	var buf [8]byte
@@ -132,9 +205,9 @@ func (g *snowflakeGenerator) generate() string {
 	return hex.EncodeToString(buf[:])
 }
 
-// currentTimestampMs returns the current time in milliseconds since the snowflake epoch.
-func (g *snowflakeGenerator) currentTimestampMs() int64 {
-	return g.timeNow().UnixMilli() - snowflakeEpochMs
+// currentTimestampMs returns the current time in milliseconds since the generator's epoch.
+func (g *SnowflakeGenerator) currentTimestampMs() int64 {
+	return g.timeNow().UnixMilli() - g.epochMs
 }
 
 // GetRequestID retrieves the request ID from the context.
@@ -158,20 +231,163 @@ func isPrintableASCII(s string) bool {
 	return true
 }
 
+// requestIDConfig holds configuration for the RequestID middleware.
+type requestIDConfig struct {
+	validator    func(id string) bool
+	machineBits  int
+	sequenceBits int
+	generator    *SnowflakeGenerator
+}
+
+// RequestIDOption configures the RequestID middleware.
+type RequestIDOption func(*requestIDConfig)
+
+// WithRequestIDValidator sets the function deciding whether an incoming X-Request-ID
+// is accepted. It replaces the built-in length and printable ASCII checks. IDs it
+// rejects are replaced with a newly generated snowflake ID. Empty IDs are always replaced.
+func WithRequestIDValidator(fn func(id string) bool) RequestIDOption {
+	return func(c *requestIDConfig) {
+		c.validator = fn
+	}
+}
+
+// WithSnowflakeBits sets how the 23 bits after the 41-bit timestamp are split between
+// the machine hash and the per-millisecond sequence counter (default 16 and 7).
+// More machine bits lower the chance of collisions between hosts; fewer sequence bits
+// lower the number of IDs generated per millisecond before spin-waiting (2^sequenceBits).
+// Generated IDs are always 16 hex characters. If machineBits is negative, sequenceBits is
+// less than 1, or their sum exceeds 23, the defaults are used with a warning log.
+func WithSnowflakeBits(machineBits, sequenceBits int) RequestIDOption {
+	return func(c *requestIDConfig) {
+		if !validSnowflakeBits(machineBits, sequenceBits) {
+			slog.Warn("middleware: snowflake machine and sequence bits must fit in 23 bits, using defaults",
+				"machineBits", machineBits, "sequenceBits", sequenceBits,
+				"defaultMachineBits", snowflakeMachineBits, "defaultSequenceBits", snowflakeSequenceBits)
+
+			machineBits, sequenceBits = snowflakeMachineBits, snowflakeSequenceBits
+		}
+
+		c.machineBits = machineBits
+		c.sequenceBits = sequenceBits
+	}
+}
+
+// WithSnowflakeGenerator makes RequestID generate IDs with gen, typically created by
+// NewCustomSnowflakeGenerator, instead of its own generator; WithSnowflakeBits is then
+// ignored. A nil gen keeps the default generator.
+func WithSnowflakeGenerator(gen *SnowflakeGenerator) RequestIDOption {
+	return func(c *requestIDConfig) {
+		c.generator = gen
+	}
+}
+
+// defaultRequestIDValidator accepts IDs of at most 256 printable ASCII characters.
+func defaultRequestIDValidator(id string) bool {
+	return len(id) <= maxRequestIDLength && isPrintableASCII(id)
+}
+
+// ValidateUUID4 returns a validator accepting canonical version 4 UUIDs
+// (e.g. "f47ac10b-58cc-4372-a567-0e02b2c3d479"), in either letter case.
+func ValidateUUID4() func(string) bool {
+	return func(id string) bool {
+		if len(id) != uuidLength {
+			return false
+		}
+
+		for i := range len(id) {
+			switch i {
+			case 8, 13, 18, 23:
+				if id[i] != '-' {
+					return false
+				}
+			case 14:
+				if id[i] != '4' {
+					return false
+				}
+			case 19:
+				if !strings.ContainsRune("89abAB", rune(id[i])) {
+					return false
+				}
+			default:
+				if !isHexDigit(id[i]) {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+}
+
+// ValidateHex returns a validator accepting hexadecimal IDs of exactly length characters.
+// If length is not positive, it defaults to 16 (the length of generated IDs) with a warning log.
+func ValidateHex(length int) func(string) bool {
+	if length <= 0 {
+		slog.Warn("middleware: hex request ID length must be positive, using default",
+			"provided", length, "default", defaultHexRequestIDLength)
+
+		length = defaultHexRequestIDLength
+	}
+
+	return func(id string) bool {
+		if len(id) != length {
+			return false
+		}
+
+		for i := range len(id) {
+			if !isHexDigit(id[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// isHexDigit reports whether c is a hexadecimal digit in either letter case.
+func isHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
 // RequestID is a middleware that assigns a unique snowflake-based request ID to each request.
 // The ID is a 16-character hex string encoding a 64-bit snowflake composed of:
 // 41 bits timestamp (ms since 2026-01-01 UTC), 16 bits machine hash (FNV-1a of hostname),
-// and 7 bits sequence counter.
-// If the X-Request-ID header is already present in the request, it reuses that value.
-// Otherwise, it generates a new snowflake ID. The ID is stored in the request context
-// and set as the X-Request-ID response header.
-func RequestID() func(http.Handler) http.Handler {
-	gen := newSnowflakeGenerator()
+// and 7 bits sequence counter. The machine/sequence split is configurable.
+// If the X-Request-ID header is already present in the request and passes validation,
+// it reuses that value. Otherwise, it generates a new snowflake ID. The ID is stored in
+// the request context and set as the X-Request-ID response header.
+// By default incoming IDs must be at most 256 printable ASCII characters.
+//
+// Options:
+//   - WithRequestIDValidator(fn) - replace the default validation, e.g. with ValidateUUID4() or ValidateHex(n)
+//   - WithSnowflakeBits(machineBits, sequenceBits) - change the machine/sequence bit split
+//   - WithSnowflakeGenerator(gen) - use a generator from NewCustomSnowflakeGenerator
+func RequestID(opts ...RequestIDOption) func(http.Handler) http.Handler {
+	cfg := requestIDConfig{
+		validator:    defaultRequestIDValidator,
+		machineBits:  snowflakeMachineBits,
+		sequenceBits: snowflakeSequenceBits,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	if cfg.validator == nil {
+		cfg.validator = defaultRequestIDValidator
+	}
+
+	gen := cfg.generator
+	if gen == nil {
+		gen = newSnowflakeGenerator(cfg.machineBits, cfg.sequenceBits)
+	}
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			id := r.Header.Get(RequestIDHeader)
-			if id == "" || len(id) > maxRequestIDLength || !isPrintableASCII(id) {
+			if id == "" || !cfg.validator(id) {
 				id = gen.generate()
 			}
 
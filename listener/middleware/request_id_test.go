@@ -3,6 +3,7 @@ package middleware
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"fmt"
 	"hash/fnv"
 	"net/http"
 	"net/http/httptest"
@@ -47,7 +48,7 @@ func expectedMachineID(t *testing.T) uint64 {
 func TestGenerateRequestID_Format(t *testing.T) {
 	t.Parallel()
 
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 	id := gen.generate()
 
 	assert.Len(t, id, 16, "request ID should be 16 hex characters")
@@ -63,7 +64,7 @@ func TestGenerateRequestID_Format(t *testing.T) {
 func TestGenerateRequestID_Uniqueness(t *testing.T) {
 	t.Parallel()
 
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 	seen := make(map[string]struct{}, 10000)
 
 	for range 10000 {
@@ -79,7 +80,7 @@ func TestGenerateRequestID_Uniqueness(t *testing.T) {
 func TestSnowflakeGenerator_Structure(t *testing.T) {
 	t.Parallel()
 
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 
 	before := time.Now()
 	id := gen.generate()
@@ -101,7 +102,7 @@ func TestSnowflakeGenerator_Structure(t *testing.T) {
 func TestSnowflakeGenerator_SequenceIncrement(t *testing.T) {
 	t.Parallel()
 
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 	fixedTime := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
 	gen.timeNow = func() time.Time {
 		return fixedTime
@@ -126,7 +127,7 @@ func TestSnowflakeGenerator_SequenceIncrement(t *testing.T) {
 func TestSnowflakeGenerator_SpinWait(t *testing.T) {
 	t.Parallel()
 
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 	fixedTime := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
 	fixedMs := fixedTime.UnixMilli() - snowflakeEpochMs
 
@@ -154,7 +155,7 @@ func TestSnowflakeGenerator_SpinWait(t *testing.T) {
 func TestSnowflakeGenerator_ClockBackward(t *testing.T) {
 	t.Parallel()
 
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 	baseTime := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
 
 	callCount := 0
@@ -200,7 +201,7 @@ func TestSnowflakeGenerator_ClockBackward(t *testing.T) {
 func TestSnowflakeGenerator_LargeClockBackward(t *testing.T) {
 	t.Parallel()
 
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 	baseTime := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
 
 	callCount := 0
@@ -243,7 +244,7 @@ func TestSnowflakeGenerator_LargeClockBackward(t *testing.T) {
 func TestSnowflakeGenerator_ConcurrentUniqueness(t *testing.T) {
 	t.Parallel()
 
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 
 	var collected sync.Map
 
@@ -272,7 +273,7 @@ func TestSnowflakeGenerator_ConcurrentUniqueness(t *testing.T) {
 }
 
 func BenchmarkSnowflakeGenerator(b *testing.B) {
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 
 	for b.Loop() {
 		gen.generate()
@@ -280,7 +281,7 @@ func BenchmarkSnowflakeGenerator(b *testing.B) {
 }
 
 func BenchmarkSnowflakeGenerator_Parallel(b *testing.B) {
-	gen := newSnowflakeGenerator()
+	gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
@@ -300,7 +301,7 @@ func FuzzSnowflakeGenerator_Uniqueness(f *testing.F) {
 			t.Skip("batch size out of range")
 		}
 
-		gen := newSnowflakeGenerator()
+		gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 		seen := make(map[string]struct{}, batchSize)
 
 		for range batchSize {
@@ -333,7 +334,7 @@ func FuzzSnowflakeGenerator_Structure(f *testing.F) {
 			t.Skip("batch size out of range")
 		}
 
-		gen := newSnowflakeGenerator()
+		gen := newSnowflakeGenerator(snowflakeMachineBits, snowflakeSequenceBits)
 
 		hostname, _ := os.Hostname()
 		h := fnv.New64a()
@@ -514,3 +515,362 @@ func TestIsPrintableASCII(t *testing.T) { //nolint:paralleltest // table-driven
 		})
 	}
 }
+
+func TestValidateUUID4(t *testing.T) {
+	t.Parallel()
+
+	validate := ValidateUUID4()
+
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "valid lowercase", id: "f47ac10b-58cc-4372-a567-0e02b2c3d479", want: true},
+		{name: "valid uppercase", id: "F47AC10B-58CC-4372-B567-0E02B2C3D479", want: true},
+		{name: "wrong version", id: "f47ac10b-58cc-1372-a567-0e02b2c3d479", want: false},
+		{name: "wrong variant", id: "f47ac10b-58cc-4372-c567-0e02b2c3d479", want: false},
+		{name: "missing hyphens", id: "f47ac10b58cc4372a5670e02b2c3d479", want: false},
+		{name: "non-hex character", id: "g47ac10b-58cc-4372-a567-0e02b2c3d479", want: false},
+		{name: "too long", id: "f47ac10b-58cc-4372-a567-0e02b2c3d4790", want: false},
+		{name: "snowflake ID", id: "abcdef1234567890", want: false},
+		{name: "empty", id: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, validate(tt.id))
+		})
+	}
+}
+
+func TestValidateHex(t *testing.T) {
+	t.Parallel()
+
+	validate := ValidateHex(8)
+
+	assert.True(t, validate("deadBEEF"))
+	assert.False(t, validate("deadbee"), "too short")
+	assert.False(t, validate("deadbeef0"), "too long")
+	assert.False(t, validate("deadbeeg"), "non-hex character")
+}
+
+func TestValidateHex_NonPositiveLength(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	validate := ValidateHex(0)
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, int64(0), h.records[0].Attrs["provided"])
+	assert.True(t, validate("abcdef1234567890"), "should default to the generated ID length")
+	assert.False(t, validate("abcdef12"))
+}
+
+func TestRequestID_WithRequestIDValidator(t *testing.T) {
+	t.Parallel()
+
+	const uuid = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+
+	tests := []struct {
+		name       string
+		incoming   string
+		wantReused bool
+	}{
+		{name: "valid UUID is reused", incoming: uuid, wantReused: true},
+		{name: "non-UUID is replaced", incoming: "not-a-uuid", wantReused: false},
+		{name: "default-valid ID is replaced", incoming: "abcdef1234567890", wantReused: false},
+		{name: "empty ID is replaced", incoming: "", wantReused: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var contextID string
+
+			handler := RequestID(WithRequestIDValidator(ValidateUUID4()))(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					contextID = GetRequestID(r.Context())
+
+					w.WriteHeader(http.StatusOK)
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(RequestIDHeader, tt.incoming)
+
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if tt.wantReused {
+				assert.Equal(t, tt.incoming, contextID)
+				assert.Equal(t, tt.incoming, rec.Header().Get(RequestIDHeader))
+
+				return
+			}
+
+			assert.NotEqual(t, tt.incoming, contextID)
+			assert.Equal(t, contextID, rec.Header().Get(RequestIDHeader))
+
+			ts, machine, _ := decodeSnowflakeID(t, contextID)
+			assert.Positive(t, ts)
+			assert.Equal(t, expectedMachineID(t), machine)
+		})
+	}
+}
+
+func TestRequestID_CustomValidatorReplacesBuiltInChecks(t *testing.T) {
+	t.Parallel()
+
+	longID := strings.Repeat("a", maxRequestIDLength+1)
+
+	var contextID string
+
+	handler := RequestID(WithRequestIDValidator(func(string) bool { return true }))(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			contextID = GetRequestID(r.Context())
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(RequestIDHeader, longID)
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, longID, contextID, "custom validator should replace the length check")
+}
+
+func TestSnowflakeGenerator_CustomBits(t *testing.T) {
+	t.Parallel()
+
+	const (
+		machineBits  = 20
+		sequenceBits = 3
+	)
+
+	gen := newSnowflakeGenerator(machineBits, sequenceBits)
+
+	fixedTime := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	calls := 0
+	gen.timeNow = func() time.Time {
+		calls++
+
+		// Advance one millisecond every 20 calls, forcing sequence overflow spin-waits.
+		return fixedTime.Add(time.Duration(calls/20) * time.Millisecond)
+	}
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(hostname))
+
+	wantMachine := h.Sum64() & (1<<machineBits - 1)
+	fixedMs := fixedTime.UnixMilli() - snowflakeEpochMs
+	seen := make(map[string]struct{}, 1000)
+
+	for range 1000 {
+		id := gen.generate()
+		require.Len(t, id, 16, "ID must be 16 hex characters")
+
+		_, exists := seen[id]
+		require.False(t, exists, "duplicate request ID generated: %s", id)
+
+		seen[id] = struct{}{}
+
+		raw, err := hex.DecodeString(id)
+		require.NoError(t, err, "ID must be valid hex")
+
+		val := binary.BigEndian.Uint64(raw)
+		assert.Equal(t, wantMachine, (val>>sequenceBits)&(1<<machineBits-1))
+		assert.GreaterOrEqual(t, int64(val>>(machineBits+sequenceBits)), fixedMs)
+	}
+}
+
+func TestSnowflakeGenerator_CustomBitsConcurrentUniqueness(t *testing.T) {
+	t.Parallel()
+
+	gen := newSnowflakeGenerator(snowflakeNodeBits-4, 4)
+
+	var collected sync.Map
+
+	var waitGroup sync.WaitGroup
+
+	const goroutines = 10
+
+	const idsPerGoroutine = 100
+
+	waitGroup.Add(goroutines)
+
+	for range goroutines {
+		go func() {
+			defer waitGroup.Done()
+
+			for range idsPerGoroutine {
+				id := gen.generate()
+				assert.Len(t, id, 16)
+
+				_, loaded := collected.LoadOrStore(id, struct{}{})
+				assert.False(t, loaded, "duplicate concurrent ID: %s", id)
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+}
+
+func TestNewCustomSnowflakeGenerator(t *testing.T) {
+	t.Parallel()
+
+	const (
+		machineBits  = 10
+		sequenceBits = 12
+	)
+
+	epochMs := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+	gen, err := NewCustomSnowflakeGenerator(epochMs, machineBits, sequenceBits)
+	require.NoError(t, err)
+
+	wantMachine := expectedMachineID(t) & (1<<machineBits - 1)
+	start := time.Now().UnixMilli() - epochMs
+
+	var last uint64
+
+	for i := range 10000 {
+		raw, err := hex.DecodeString(gen.Generate())
+		require.NoError(t, err)
+
+		val := binary.BigEndian.Uint64(raw)
+		require.Greater(t, val, last, "ID %d must be greater than the previous one", i)
+
+		last = val
+
+		assert.Equal(t, wantMachine, (val>>sequenceBits)&(1<<machineBits-1))
+		assert.GreaterOrEqual(t, int64(val>>(machineBits+sequenceBits)), start)
+	}
+
+	assert.Zero(t, last>>63, "top bit must stay clear")
+}
+
+func TestNewCustomSnowflakeGenerator_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		epochMs      int64
+		machineBits  uint8
+		sequenceBits uint8
+		wantErr      error
+	}{
+		{name: "exceeds 23 bits", machineBits: 12, sequenceBits: 12, wantErr: ErrInvalidSnowflakeBits},
+		{name: "zero sequence bits", machineBits: 10, sequenceBits: 0, wantErr: ErrInvalidSnowflakeBits},
+		{name: "negative epoch", epochMs: -1, machineBits: 10, sequenceBits: 12, wantErr: ErrInvalidSnowflakeEpoch},
+		{
+			name:         "future epoch",
+			epochMs:      time.Now().Add(time.Hour).UnixMilli(),
+			machineBits:  10,
+			sequenceBits: 12,
+			wantErr:      ErrInvalidSnowflakeEpoch,
+		},
+	}
+
+	for _, tt := range tests {
+		gen, err := NewCustomSnowflakeGenerator(tt.epochMs, tt.machineBits, tt.sequenceBits)
+
+		require.ErrorIs(t, err, tt.wantErr, tt.name)
+		assert.Nil(t, gen, tt.name)
+	}
+
+	_, err := NewCustomSnowflakeGenerator(snowflakeEpochMs, snowflakeMachineBits, snowflakeSequenceBits)
+	require.NoError(t, err, "the default 23-bit split should be accepted, as by WithSnowflakeBits")
+}
+
+func TestRequestID_WithSnowflakeGenerator(t *testing.T) {
+	t.Parallel()
+
+	gen, err := NewCustomSnowflakeGenerator(snowflakeEpochMs, 10, 12)
+	require.NoError(t, err)
+
+	fixedTime := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	gen.timeNow = func() time.Time { return fixedTime }
+
+	handler := RequestID(WithSnowflakeGenerator(gen))(okHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	raw, err := hex.DecodeString(rec.Header().Get(RequestIDHeader))
+	require.NoError(t, err)
+
+	val := binary.BigEndian.Uint64(raw)
+	assert.Equal(t, uint64(0), val&(1<<12-1), "first ID should use sequence 0")
+	assert.Equal(t, gen.Generate(), fmt.Sprintf("%016x", val+1), "handler should share the provided generator")
+}
+
+func TestWithSnowflakeBits(t *testing.T) {
+	t.Parallel()
+
+	cfg := requestIDConfig{}
+	WithSnowflakeBits(snowflakeNodeBits, 0)(&cfg)
+
+	// A zero-width sequence is invalid and falls back to the defaults.
+	assert.Equal(t, snowflakeMachineBits, cfg.machineBits)
+	assert.Equal(t, snowflakeSequenceBits, cfg.sequenceBits)
+
+	WithSnowflakeBits(0, snowflakeNodeBits)(&cfg)
+	assert.Equal(t, 0, cfg.machineBits)
+	assert.Equal(t, snowflakeNodeBits, cfg.sequenceBits)
+}
+
+func TestWithSnowflakeBits_Invalid(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	tests := []struct {
+		name         string
+		machineBits  int
+		sequenceBits int
+	}{
+		{name: "exceeds available bits", machineBits: 20, sequenceBits: 4},
+		{name: "negative machine bits", machineBits: -1, sequenceBits: 7},
+		{name: "zero sequence bits", machineBits: 16, sequenceBits: 0},
+	}
+
+	for _, tt := range tests {
+		h.records = nil
+
+		cfg := requestIDConfig{}
+		WithSnowflakeBits(tt.machineBits, tt.sequenceBits)(&cfg)
+
+		assert.Equal(t, snowflakeMachineBits, cfg.machineBits, tt.name)
+		assert.Equal(t, snowflakeSequenceBits, cfg.sequenceBits, tt.name)
+		require.Len(t, h.records, 1, tt.name)
+		assert.Equal(t, int64(tt.machineBits), h.records[0].Attrs["machineBits"], tt.name)
+	}
+}
+
+func TestRequestID_WithSnowflakeBits(t *testing.T) {
+	t.Parallel()
+
+	handler := RequestID(WithSnowflakeBits(22, 1))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(GetRequestID(r.Context())))
+	}))
+
+	seen := make(map[string]struct{}, 50)
+
+	for range 50 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		id := rec.Body.String()
+		assert.True(t, ValidateHex(16)(id), "expected 16 hex characters, got %q", id)
+
+		_, exists := seen[id]
+		assert.False(t, exists, "duplicate request ID generated: %s", id)
+
+		seen[id] = struct{}{}
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequestStartHeader is the header load balancers set to the time they received the request.
+const RequestStartHeader = "X-Request-Start"
+
+type queueDelayKeyType struct{}
+
+var queueDelayKey = queueDelayKeyType{} //nolint:gochecknoglobals
+
+// RequestStartTime returns a middleware that reads the X-Request-Start header set by a
+// load balancer, in the "t=<unix-ms>" format, and stores the time the request spent
+// queued before reaching the application in the request context. Downstream handlers
+// can retrieve it via GetQueueDelay, and Logging adds it as the "queue_delay" attribute;
+// like RequestID, place it outside Logging. A delay that would be negative because of
+// clock skew is recorded as 0. If the header is absent or malformed, nothing is stored.
+func RequestStartTime() func(http.Handler) http.Handler {
+	return requestStartTime(time.Now)
+}
+
+// requestStartTime is RequestStartTime with an injectable clock for tests.
+func requestStartTime(timeNow func() time.Time) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start, ok := parseRequestStart(r.Header.Get(RequestStartHeader))
+			if !ok {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			delay := max(timeNow().Sub(start), 0)
+			ctx := context.WithValue(r.Context(), queueDelayKey, delay)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// parseRequestStart parses a "t=<unix-ms>" header value.
+func parseRequestStart(value string) (time.Time, bool) {
+	millis, ok := strings.CutPrefix(strings.TrimSpace(value), "t=")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}, false
+	}
+
+	return time.UnixMilli(ms), true
+}
+
+// GetQueueDelay retrieves the queuing delay stored by RequestStartTime from the context.
+// It returns false if no delay is set.
+func GetQueueDelay(ctx context.Context) (time.Duration, bool) {
+	delay, ok := ctx.Value(queueDelayKey).(time.Duration)
+
+	return delay, ok
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestStartTime_QueueDelay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		delay time.Duration
+		found bool
+	)
+
+	handler := RequestStartTime()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		delay, found = GetQueueDelay(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestStartHeader, "t="+strconv.FormatInt(time.Now().Add(-100*time.Millisecond).UnixMilli(), 10))
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.True(t, found)
+	assert.InDelta(t, 100*time.Millisecond, delay, float64(50*time.Millisecond))
+}
+
+func TestRequestStartTime_InvalidHeader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "absent", header: ""},
+		{name: "missing prefix", header: "1700000000000"},
+		{name: "not a number", header: "t=soon"},
+		{name: "fractional seconds", header: "t=1700000000.123"},
+		{name: "zero", header: "t=0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var found bool
+
+			handler := RequestStartTime()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				_, found = GetQueueDelay(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(RequestStartHeader, tt.header)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.False(t, found)
+			assert.Equal(t, http.StatusOK, rr.Code)
+		})
+	}
+}
+
+func TestRequestStartTime_ClockSkewClampsToZero(t *testing.T) {
+	t.Parallel()
+
+	now := time.UnixMilli(1_700_000_000_000)
+
+	var delay time.Duration
+
+	handler := requestStartTime(func() time.Time { return now })(
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			delay, _ = GetQueueDelay(r.Context())
+		}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestStartHeader, "t=1700000000500")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, time.Duration(0), delay)
+}
+
+func TestGetQueueDelay_NotSet(t *testing.T) {
+	t.Parallel()
+
+	_, ok := GetQueueDelay(context.Background())
+	assert.False(t, ok)
+}
+
+func TestLogging_QueueDelay(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+
+	now := time.UnixMilli(1_700_000_000_250)
+	handler := Chain(requestStartTime(func() time.Time { return now }), Logging())(okHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestStartHeader, "t=1700000000000")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, h.records, 1)
+	assert.Equal(t, 250*time.Millisecond, h.records[0].Attrs["queue_delay"])
+}
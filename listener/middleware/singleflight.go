@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+)
+
+// flightResponse is a handler response captured for replay to every waiter.
+type flightResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// flightCall is an in-flight handler execution that duplicate requests wait on.
+type flightCall struct {
+	wg   sync.WaitGroup
+	res  *flightResponse
+	dups int
+}
+
+// flightGroup deduplicates concurrent executions with the same key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: make(map[string]*flightCall)}
+}
+
+// do runs fn once per key among concurrent callers; duplicates block until the first
+// caller finishes and receive its result. If fn panics, the panic propagates to the
+// first caller and the waiters receive nil.
+func (g *flightGroup) do(key string, fn func() *flightResponse) *flightResponse {
+	g.mu.Lock()
+
+	if call, ok := g.calls[key]; ok {
+		call.dups++
+		g.mu.Unlock()
+		call.wg.Wait()
+
+		return call.res
+	}
+
+	call := new(flightCall)
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		call.wg.Done()
+	}()
+
+	call.res = fn()
+
+	return call.res
+}
+
+// duplicates returns the number of callers currently waiting on key.
+func (g *flightGroup) duplicates(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if call, ok := g.calls[key]; ok {
+		return call.dups
+	}
+
+	return 0
+}
+
+// flightRecorder buffers a handler response instead of sending it.
+type flightRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (fr *flightRecorder) Header() http.Header {
+	return fr.header
+}
+
+func (fr *flightRecorder) WriteHeader(code int) {
+	if fr.status == 0 {
+		fr.status = code
+	}
+}
+
+func (fr *flightRecorder) Write(b []byte) (int, error) {
+	if fr.status == 0 {
+		fr.status = http.StatusOK
+	}
+
+	return fr.body.Write(b)
+}
+
+// replay writes res to w, copying the header values so waiters never share slices.
+func (res *flightResponse) replay(w http.ResponseWriter) {
+	for key, values := range res.header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+
+	w.WriteHeader(res.status)
+	_, _ = w.Write(res.body)
+}
+
+// SingleFlight returns a middleware that coalesces concurrent GET requests with the same
+// key: the first one runs the handler while the others wait, and the buffered status,
+// headers and body are replayed to all of them. keyFunc derives the key from the request,
+// e.g. r.URL.RequestURI(); when nil, the request URI is used.
+// Non-GET requests and requests with an empty key bypass deduplication.
+//
+// Only use it for idempotent handlers whose response does not depend on per-request
+// data beyond the key, such as cookies or authorization. The handler sees a fresh
+// header map and runs with the first request's context, so if that client disconnects
+// every waiter receives the cancelled result. Responses are buffered in full,
+// which makes the middleware unsuitable for streaming. If the handler panics, the
+// panic propagates to the first request and each waiter runs the handler itself.
+func SingleFlight(keyFunc func(*http.Request) string) func(http.Handler) http.Handler {
+	return singleFlight(keyFunc, newFlightGroup())
+}
+
+// singleFlight is SingleFlight with an injectable group, letting tests observe
+// how many requests are waiting on a key.
+func singleFlight(keyFunc func(*http.Request) string, group *flightGroup) func(http.Handler) http.Handler {
+	if keyFunc == nil {
+		keyFunc = func(r *http.Request) string { return r.URL.RequestURI() }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			key := keyFunc(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			res := group.do(key, func() *flightResponse {
+				rec := &flightRecorder{header: make(http.Header)}
+				next.ServeHTTP(rec, r)
+
+				if rec.status == 0 {
+					rec.status = http.StatusOK
+				}
+
+				return &flightResponse{status: rec.status, header: rec.header, body: rec.body.Bytes()}
+			})
+			if res == nil {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			res.replay(w)
+		})
+	}
+}
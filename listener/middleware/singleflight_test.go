@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleFlight_ConcurrentIdenticalRequestsRunOnce(t *testing.T) {
+	t.Parallel()
+
+	const waiters = 9
+
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	group := newFlightGroup()
+
+	handler := singleFlight(nil, group)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		<-release
+
+		w.Header().Set("X-Report", "daily")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("expensive result"))
+	}))
+
+	recorders := make([]*httptest.ResponseRecorder, waiters+1)
+
+	var wg sync.WaitGroup
+
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+
+		wg.Go(func() {
+			handler.ServeHTTP(recorders[i], httptest.NewRequest(http.MethodGet, "/report?day=1", nil))
+		})
+	}
+
+	require.Eventually(t, func() bool { return group.duplicates("/report?day=1") == waiters },
+		time.Second, time.Millisecond, "all other requests should wait on the first")
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "handler should run once")
+
+	for _, rec := range recorders {
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "daily", rec.Header().Get("X-Report"))
+		assert.Equal(t, "expensive result", rec.Body.String())
+	}
+}
+
+func TestSingleFlight_SequentialRequestsRunEach(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	handler := SingleFlight(nil)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		calls.Add(1)
+	}))
+
+	for range 3 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	assert.Equal(t, int32(3), calls.Load(), "results must not be cached after the flight lands")
+}
+
+func TestSingleFlight_Bypass(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		method  string
+		keyFunc func(*http.Request) string
+	}{
+		{name: "non-GET method", method: http.MethodPost, keyFunc: nil},
+		{name: "empty key", method: http.MethodGet, keyFunc: func(*http.Request) string { return "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			const requests = 3
+
+			var (
+				calls   atomic.Int32
+				started sync.WaitGroup
+			)
+
+			started.Add(requests)
+
+			handler := SingleFlight(tt.keyFunc)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				calls.Add(1)
+				// Every request must reach the handler concurrently; coalescing would deadlock here.
+				started.Done()
+				started.Wait()
+			}))
+
+			var wg sync.WaitGroup
+
+			for range requests {
+				wg.Go(func() {
+					handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, "/", nil))
+				})
+			}
+
+			wg.Wait()
+
+			assert.Equal(t, int32(requests), calls.Load())
+		})
+	}
+}
+
+func TestSingleFlight_PanicLetsWaitersRunHandler(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	group := newFlightGroup()
+
+	handler := singleFlight(nil, group)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			<-release
+			panic("boom")
+		}
+
+		_, _ = w.Write([]byte("retried"))
+	}))
+
+	leaderDone := make(chan any)
+
+	go func() {
+		defer func() { leaderDone <- recover() }()
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	waiter := httptest.NewRecorder()
+	waiterDone := make(chan struct{})
+
+	go func() {
+		defer close(waiterDone)
+
+		handler.ServeHTTP(waiter, httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	require.Eventually(t, func() bool { return group.duplicates("/") == 1 }, time.Second, time.Millisecond)
+
+	close(release)
+
+	assert.Equal(t, "boom", <-leaderDone, "panic should propagate to the first request")
+	<-waiterDone
+
+	assert.Equal(t, "retried", waiter.Body.String())
+	assert.Equal(t, int32(2), calls.Load())
+}
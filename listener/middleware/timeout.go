@@ -1,26 +1,256 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"runtime/debug"
+	"sync"
 	"time"
 )
 
 const defaultTimeoutDuration = 30 * time.Second
 
+// timeoutMessage is the error message sent when the deadline passes.
+const timeoutMessage = "Service Unavailable"
+
 // Timeout returns a middleware that enforces a request processing deadline.
 // If the handler does not complete within the given duration, a 503 Service
 // Unavailable response is sent to the client.
 // If duration is not positive, it defaults to 30s with a warning log.
+// Inside JSONErrors the 503 body is a JSON ErrorResponse carrying the request ID.
+// The response is buffered until the handler returns; writes after the deadline
+// fail with http.ErrHandlerTimeout. See StreamingTimeout for unbuffered responses.
 func Timeout(duration time.Duration) func(http.Handler) http.Handler {
+	duration = normalizeTimeout(duration)
+
+	return func(next http.Handler) http.Handler {
+		plain := http.TimeoutHandler(next, duration, timeoutMessage)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !jsonErrorsEnabled(r.Context()) {
+				plain.ServeHTTP(w, r)
+
+				return
+			}
+
+			// The error body depends on the request ID, so the handler is built per request.
+			body := errorBody(r.Context(), http.StatusServiceUnavailable, timeoutMessage)
+			jw := &jsonTimeoutWriter{ResponseWriter: w, body: body}
+
+			http.TimeoutHandler(next, duration, string(body)).ServeHTTP(jw, r)
+			jw.flushHeader()
+		})
+	}
+}
+
+// normalizeTimeout replaces a non-positive duration with the default, logging a warning.
+func normalizeTimeout(duration time.Duration) time.Duration {
 	if duration <= 0 {
 		slog.Warn("middleware: duration must be positive, using default",
 			"provided", duration, "default", defaultTimeoutDuration)
 
-		duration = defaultTimeoutDuration
+		return defaultTimeoutDuration
 	}
 
+	return duration
+}
+
+// streamingTimeoutWriter passes writes straight through until the deadline passes.
+// The handler gets its own header map, copied to the underlying writer when the
+// response starts, so the handler goroutine never touches the real headers while the
+// serving goroutine sends the timeout response. From the deadline on Write fails with
+// http.ErrHandlerTimeout and WriteHeader is a no-op logged as a warning, so a handler
+// still running learns that it is too late.
+type streamingTimeoutWriter struct {
+	http.ResponseWriter
+
+	ctx         context.Context //nolint:containedctx // the deadline is checked on every write
+	header      http.Header
+	mu          sync.Mutex
+	timedOut    bool
+	wroteHeader bool
+	warned      bool
+}
+
+// Header returns the handler's own header map.
+func (w *streamingTimeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *streamingTimeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.expired() {
+		w.warnLate(code)
+
+		return
+	}
+
+	w.startResponse()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *streamingTimeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	w.startResponse()
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered data to the client, unless the deadline has passed.
+func (w *streamingTimeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.expired() {
+		return
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.startResponse()
+
+		f.Flush()
+	}
+}
+
+func (w *streamingTimeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// startResponse copies the handler's headers to the underlying writer the first time
+// the response is written to. w.mu must be held.
+func (w *streamingTimeoutWriter) startResponse() {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+
+	dst := w.ResponseWriter.Header()
+	for key, values := range w.header {
+		dst[key] = values
+	}
+}
+
+// expired reports whether the deadline has passed, even if the serving goroutine
+// has not observed it yet. w.mu must be held.
+func (w *streamingTimeoutWriter) expired() bool {
+	if !w.timedOut && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.timedOut = true
+	}
+
+	return w.timedOut
+}
+
+// warnLate logs the first WriteHeader call made after the deadline. w.mu must be held.
+func (w *streamingTimeoutWriter) warnLate(code int) {
+	if w.warned {
+		return
+	}
+
+	w.warned = true
+
+	slog.Warn("middleware: handler wrote after the timeout fired, ignoring", "status", code)
+}
+
+// timeout marks the writer as timed out under its lock and reports whether any part
+// of the response had been sent before.
+func (w *streamingTimeoutWriter) timeout() (started bool) {
+	w.timedOut = true
+
+	return w.wroteHeader
+}
+
+// StreamingTimeout returns a middleware that enforces a request processing deadline
+// without buffering the response, so handlers can stream and flush as they go.
+// The request context is cancelled at the deadline. If the handler has not written
+// anything by then, a 503 Service Unavailable response is sent (a JSON ErrorResponse
+// inside JSONErrors); otherwise the response sent so far is ended as is.
+// Either way the handler keeps running in its goroutine until it returns, but from
+// the deadline on its Write calls return http.ErrHandlerTimeout and WriteHeader
+// calls are ignored with a warning log, so it can stop work. Headers the handler sets
+// are sent with its first write, as with http.TimeoutHandler; after the deadline they
+// no longer reach the client.
+// If duration is not positive, it defaults to 30s with a warning log.
+// Panics in the handler are re-raised in the serving goroutine, as with Timeout;
+// a panic after the timeout response is logged with its stack trace instead.
+func StreamingTimeout(duration time.Duration) func(http.Handler) http.Handler {
+	duration = normalizeTimeout(duration)
+
 	return func(next http.Handler) http.Handler {
-		return http.TimeoutHandler(next, duration, "Service Unavailable")
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), duration)
+			defer cancel()
+
+			r = r.WithContext(ctx)
+			tw := &streamingTimeoutWriter{ResponseWriter: w, ctx: ctx, header: make(http.Header)}
+
+			done := make(chan struct{})
+			panicChan := make(chan any, 1)
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						tw.reportPanic(p, panicChan)
+					}
+				}()
+
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case p := <-panicChan:
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+
+				// A handler that set headers but wrote nothing still gets them sent.
+				tw.startResponse()
+			case <-ctx.Done():
+				tw.mu.Lock()
+
+				select {
+				case p := <-panicChan:
+					tw.mu.Unlock()
+					panic(p)
+				default:
+				}
+
+				defer tw.mu.Unlock()
+
+				if !tw.timeout() {
+					writeMiddlewareError(w, r, http.StatusServiceUnavailable, timeoutMessage)
+				}
+			}
+		})
 	}
 }
+
+// reportPanic hands a handler panic to the serving goroutine, or logs it if the
+// timeout response has already been sent.
+func (w *streamingTimeoutWriter) reportPanic(p any, panicChan chan<- any) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.timedOut {
+		panicChan <- p
+
+		return
+	}
+
+	slog.Error("middleware: handler panicked after the timeout fired",
+		slog.String("panic", fmt.Sprintf("%v", p)),
+		slog.String("stack", string(debug.Stack())))
+}
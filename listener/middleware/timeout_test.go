@@ -5,6 +5,9 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -112,3 +115,191 @@ func TestTimeout_DefaultsOnNegativeDuration(t *testing.T) { //nolint:paralleltes
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Contains(t, buf.String(), "middleware: duration must be positive, using default")
 }
+
+func TestTimeout_LateWriteReturnsError(t *testing.T) { //nolint:paralleltest // timing-sensitive test
+	lateErr := make(chan error, 1)
+
+	handler := Timeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		time.Sleep(10 * time.Millisecond)
+
+		_, err := w.Write([]byte("too late"))
+		lateErr <- err
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	require.ErrorIs(t, <-lateErr, http.ErrHandlerTimeout)
+	assert.NotContains(t, rr.Body.String(), "too late")
+}
+
+func TestStreamingTimeout_StreamsBeforeDeadline(t *testing.T) { //nolint:paralleltest // timing-sensitive test
+	flushed := make(chan struct{})
+	lateErr := make(chan error, 1)
+
+	handler := StreamingTimeout(50 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("chunk 1\n"))
+		w.(http.Flusher).Flush() //nolint:forcetypeassert // the wrapper implements http.Flusher
+		close(flushed)
+
+		<-r.Context().Done()
+
+		// Keep working as a careless handler would, after the deadline.
+		for {
+			_, err := w.Write([]byte("chunk 2\n"))
+			if err != nil {
+				lateErr <- err
+
+				return
+			}
+		}
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	<-flushed
+
+	require.ErrorIs(t, <-lateErr, http.ErrHandlerTimeout, "handler should observe the timeout on write")
+	assert.Equal(t, http.StatusOK, rr.Code, "a started response keeps its status")
+	assert.True(t, rr.Flushed)
+	assert.Equal(t, "chunk 1\n", rr.Body.String())
+}
+
+func TestStreamingTimeout_NothingWrittenReturns503(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	h := setupTestLogger(t)
+	lateErr := make(chan error, 1)
+
+	handler := StreamingTimeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+
+		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(http.StatusOK)
+
+		_, err := w.Write([]byte("late"))
+		lateErr <- err
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.ErrorIs(t, <-lateErr, http.ErrHandlerTimeout)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "Service Unavailable\n", rr.Body.String())
+
+	require.Len(t, h.records, 1, "late WriteHeader should be logged once")
+	assert.Equal(t, "middleware: handler wrote after the timeout fired, ignoring", h.records[0].Message)
+}
+
+func TestStreamingTimeout_FastHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := StreamingTimeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("done"))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "done", rr.Body.String())
+}
+
+func TestStreamingTimeout_PanicPropagates(t *testing.T) {
+	t.Parallel()
+
+	handler := StreamingTimeout(time.Second)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestStreamingTimeout_HeadersAfterDeadline(t *testing.T) { //nolint:paralleltest // timing-sensitive test
+	finished := make(chan struct{})
+
+	handler := StreamingTimeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(finished)
+
+		w.Header().Set("X-Early", "kept")
+
+		<-r.Context().Done()
+
+		// Run with -race: these must not touch the headers the timeout response uses.
+		for i := range 1000 {
+			w.Header().Set("X-Late", strconv.Itoa(i))
+		}
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	<-finished
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Empty(t, rr.Header().Get("X-Early"), "headers of an unstarted response should not be sent")
+	assert.Empty(t, rr.Header().Get("X-Late"))
+}
+
+func TestStreamingTimeout_HeadersWithoutBody(t *testing.T) {
+	t.Parallel()
+
+	handler := StreamingTimeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Only-Header", "yes")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "yes", rr.Header().Get("X-Only-Header"))
+}
+
+// syncBuffer is a bytes.Buffer safe for a logger writing from another goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestStreamingTimeout_PanicAfterDeadlineIsLogged(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	var logs syncBuffer
+
+	oldDefault := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(oldDefault) })
+
+	handler := StreamingTimeout(10 * time.Millisecond)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+
+		time.Sleep(10 * time.Millisecond)
+		panic("late boom")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	require.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), "handler panicked after the timeout fired")
+	}, time.Second, time.Millisecond, "the late panic should be logged")
+	assert.Contains(t, logs.String(), `panic="late boom"`)
+}
@@ -0,0 +1,145 @@
+package listener
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync/atomic"
+
+	"github.com/0xalexb/hjarta-di/listener/middleware"
+)
+
+// Names of the middlewares applied by WithDefaultMiddleware, as reported by
+// Server.MiddlewareList and accepted by WithDefaultMiddlewareExclude.
+const (
+	DefaultMiddlewareRequestID = "RequestID"
+	DefaultMiddlewareLogging   = "Logging"
+	DefaultMiddlewareRecovery  = "Recovery"
+)
+
+// namedMiddleware is a middleware registered with WithNamedMiddleware.
+type namedMiddleware struct {
+	name       string
+	middleware func(http.Handler) http.Handler
+}
+
+// healthResponse is the JSON body served by the WithHealthEndpoint route.
+type healthResponse struct {
+	Status     string   `json:"status"`
+	Middleware []string `json:"middleware"`
+}
+
+// WithNamedMiddleware wraps the listener's handler (including routes) with mw and records
+// name in the list reported by Server.MiddlewareList and the health endpoint.
+// Middlewares apply in the order the options are given, the first being the outermost,
+// like middleware.Chain. A nil mw is ignored.
+func WithNamedMiddleware(name string, mw func(http.Handler) http.Handler) Option {
+	return func(o *options) {
+		if mw == nil {
+			return
+		}
+
+		o.middleware = append(o.middleware, namedMiddleware{name: name, middleware: mw})
+	}
+}
+
+// WithDefaultMiddleware applies middleware.RequestID, middleware.Logging and
+// middleware.Recovery, in that order from outermost to innermost, outside the
+// middlewares registered with WithNamedMiddleware. They are listed by
+// Server.MiddlewareList under the DefaultMiddleware* names.
+func WithDefaultMiddleware() Option {
+	return func(o *options) {
+		o.defaultMiddleware = true
+	}
+}
+
+// WithDefaultMiddlewareExclude leaves out the named defaults (DefaultMiddlewareRequestID,
+// DefaultMiddlewareLogging or DefaultMiddlewareRecovery) when WithDefaultMiddleware is
+// given, e.g. to register a configured Logging with WithNamedMiddleware instead.
+// Unknown names are ignored. It does not enable the defaults on its own.
+func WithDefaultMiddlewareExclude(names ...string) Option {
+	return func(o *options) {
+		o.excludedDefaults = append(o.excludedDefaults, names...)
+	}
+}
+
+// WithHealthEndpoint adds a GET route at path that responds with
+// {"status":"ok","middleware":[...]}, listing the middleware names in the order
+// MiddlewareList returns them, so operators can inspect a running listener.
+// Once Server.Stop starts the Config.PreShutdownDelay, the route responds with
+// 503 Service Unavailable and {"status":"draining",...}, so load balancers probing
+// it stop routing to the instance. The route is served through the middleware stack
+// like any other route.
+func WithHealthEndpoint(path string) Option {
+	return func(o *options) {
+		o.healthPath = path
+	}
+}
+
+// MiddlewareList returns the names of the middlewares wrapping the routes, outermost
+// first: the DefaultMiddleware* names enabled by WithDefaultMiddleware, minus those
+// excluded with WithDefaultMiddlewareExclude, followed by the names registered with
+// WithNamedMiddleware.
+func (s *Server) MiddlewareList() []string {
+	return append([]string(nil), s.middleware...)
+}
+
+// middlewareStack returns the middlewares to apply, outermost first: the defaults
+// enabled by WithDefaultMiddleware followed by those from WithNamedMiddleware.
+func (o *options) middlewareStack() []namedMiddleware {
+	if !o.defaultMiddleware {
+		return o.middleware
+	}
+
+	defaults := []namedMiddleware{
+		{name: DefaultMiddlewareRequestID, middleware: middleware.RequestID()},
+		{name: DefaultMiddlewareLogging, middleware: middleware.Logging()},
+		{name: DefaultMiddlewareRecovery, middleware: middleware.Recovery()},
+	}
+
+	stack := make([]namedMiddleware, 0, len(defaults)+len(o.middleware))
+
+	for _, m := range defaults {
+		if !slices.Contains(o.excludedDefaults, m.name) {
+			stack = append(stack, m)
+		}
+	}
+
+	return append(stack, o.middleware...)
+}
+
+// middlewareNames returns the registered middleware names in order, never nil.
+func middlewareNames(stack []namedMiddleware) []string {
+	names := make([]string, 0, len(stack))
+	for _, m := range stack {
+		names = append(names, m.name)
+	}
+
+	return names
+}
+
+// wrapMiddleware applies stack to handler with the first middleware outermost.
+func wrapMiddleware(handler http.Handler, stack []namedMiddleware) http.Handler {
+	for i := len(stack) - 1; i >= 0; i-- {
+		handler = stack[i].middleware(handler)
+	}
+
+	return handler
+}
+
+// healthHandler serves the health response for the given middleware names, reporting
+// 503 once draining is set.
+func healthHandler(names []string, draining *atomic.Bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if draining.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(healthResponse{Status: "draining", Middleware: names})
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(healthResponse{Status: "ok", Middleware: names})
+	})
+}
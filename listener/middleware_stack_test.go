@@ -0,0 +1,182 @@
+package listener
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xalexb/hjarta-di/listener/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+)
+
+// tagMiddleware appends name to the X-Stack response header before calling next.
+func tagMiddleware(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Stack", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestServer_MiddlewareList(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewServer("api", textHandler("ok"), Config{}, nil,
+		WithNamedMiddleware("RequestID", middleware.RequestID()),
+		WithNamedMiddleware("Logging", tagMiddleware("Logging")),
+		WithNamedMiddleware("Ignored", nil),
+		WithNamedMiddleware("CORS", tagMiddleware("CORS")),
+		WithNamedMiddleware("Compress", middleware.Compress()),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"RequestID", "Logging", "CORS", "Compress"}, srv.MiddlewareList())
+
+	list := srv.MiddlewareList()
+	list[0] = "changed"
+	assert.Equal(t, "RequestID", srv.MiddlewareList()[0], "callers must not modify the server's list")
+
+	rec := httptest.NewRecorder()
+	srv.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, []string{"Logging", "CORS"}, rec.Header().Values("X-Stack"), "first middleware should be outermost")
+	assert.NotEmpty(t, rec.Header().Get("X-Request-ID"))
+}
+
+func TestServer_MiddlewareListEmpty(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewServer("api", textHandler("ok"), Config{}, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, srv.MiddlewareList())
+}
+
+func TestNewModule_HealthEndpoint(t *testing.T) {
+	t.Parallel()
+
+	addr := freePort(t)
+
+	app := fxtest.New(t,
+		NewModule("ops",
+			WithAddress(addr),
+			WithNamedMiddleware("RequestID", middleware.RequestID()),
+			WithNamedMiddleware("Logging", tagMiddleware("Logging")),
+			WithNamedMiddleware("CORS", tagMiddleware("CORS")),
+			WithNamedMiddleware("Compress", middleware.Compress()),
+			WithHealthEndpoint("/healthz"),
+		),
+	)
+
+	app.RequireStart()
+	defer app.RequireStop()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr+"/healthz", nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"status":"ok","middleware":["RequestID","Logging","CORS","Compress"]}`, string(body))
+}
+
+func TestWithHealthEndpoint_NoMiddleware(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewServer("api", nil, Config{}, nil, WithHealthEndpoint("/healthz"))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	srv.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok","middleware":[]}`, rec.Body.String())
+}
+
+func TestNewModule_DefaultMiddleware(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	var logs lockedBuffer
+
+	original := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	t.Cleanup(func() { slog.SetDefault(original) })
+
+	addr := freePort(t)
+
+	app := fxtest.New(t,
+		NewModule("api",
+			WithAddress(addr),
+			WithDefaultMiddleware(),
+			WithNamedMiddleware("Tag", tagMiddleware("Tag")),
+			WithRoutes(
+				Route{Method: http.MethodGet, Pattern: "/id", Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = io.WriteString(w, middleware.GetRequestID(r.Context()))
+				})},
+				Route{Method: http.MethodGet, Pattern: "/panic", Handler: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+					panic("boom")
+				})},
+			),
+			WithHealthEndpoint("/healthz"),
+		),
+	)
+
+	app.RequireStart()
+	defer app.RequireStop()
+
+	get := func(path string) (*http.Response, string) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr+path, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+		require.NoError(t, err)
+
+		defer func() { _ = resp.Body.Close() }()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, string(body)
+	}
+
+	resp, body := get("/id")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, body, "RequestID should set the request ID in the context")
+	assert.Equal(t, body, resp.Header.Get(middleware.RequestIDHeader))
+	assert.Equal(t, []string{"Tag"}, resp.Header.Values("X-Stack"), "named middlewares should still apply")
+	assert.Contains(t, logs.String(), `"request_id":"`+body+`"`, "Logging should log the request inside RequestID")
+
+	resp, _ = get("/panic")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode, "Recovery should turn the panic into a 500")
+	assert.NotEmpty(t, resp.Header.Get(middleware.RequestIDHeader))
+	assert.Contains(t, logs.String(), `"path":"/panic","status":500`, "Logging should see the recovered status")
+
+	_, body = get("/healthz")
+	assert.JSONEq(t, `{"status":"ok","middleware":["RequestID","Logging","Recovery","Tag"]}`, body)
+}
+
+func TestWithDefaultMiddlewareExclude(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewServer("api", textHandler("ok"), Config{}, nil,
+		WithDefaultMiddleware(),
+		WithDefaultMiddlewareExclude(DefaultMiddlewareLogging, "Unknown"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultMiddlewareRequestID, DefaultMiddlewareRecovery}, srv.MiddlewareList())
+
+	srv, err = NewServer("api", textHandler("ok"), Config{}, nil, WithDefaultMiddlewareExclude(DefaultMiddlewareLogging))
+	require.NoError(t, err)
+	assert.Empty(t, srv.MiddlewareList(), "excluding does not enable the defaults")
+}
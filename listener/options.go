@@ -1,11 +1,115 @@
 package listener
 
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+)
+
 // Option defines a function type for configuring an HTTP listener.
-type Option func(*Config)
+type Option func(*options)
+
+// options holds the listener settings collected from Option values.
+type options struct {
+	config    Config
+	hasConfig bool
+	logger    *slog.Logger
+	routes    []Route
+
+	middleware        []namedMiddleware
+	defaultMiddleware bool
+	excludedDefaults  []string
+	healthPath        string
+	requestCounter    func(method, path string, status int)
+	serveErrors       func(error)
+	startupProbe      bool
+	baseContext       func(net.Listener) context.Context
+
+	handlerProvider any
+}
+
+func newOptions(opts ...Option) options {
+	var o options
+
+	for _, apply := range opts {
+		if apply != nil {
+			apply(&o)
+		}
+	}
+
+	return o
+}
 
 // WithAddress sets the address for the HTTP listener.
 func WithAddress(addr string) Option {
-	return func(cfg *Config) {
+	return ConfigOption(func(cfg *Config) {
 		cfg.Address = addr
+	})
+}
+
+// ConfigOption adapts fn, which edits the listener Config, into an Option, like
+// WithAddress. It keeps config options written against the former func(*Config)
+// Option type working. Like every config option, it is applied by NewModule only.
+func ConfigOption(fn func(*Config)) Option {
+	return func(o *options) {
+		if fn != nil {
+			fn(&o.config)
+			o.hasConfig = true
+		}
+	}
+}
+
+// moduleOnlyOptions returns an error naming the options that NewServer cannot apply.
+func (o *options) moduleOnlyOptions() error {
+	switch {
+	case o.hasConfig:
+		return fmt.Errorf("%w: config options such as WithAddress; set them on the Config instead", ErrModuleOnlyOption)
+	case o.handlerProvider != nil:
+		return fmt.Errorf("%w: WithHandlerProvider; pass the handler instead", ErrModuleOnlyOption)
+	default:
+		return nil
+	}
+}
+
+// WithLogger sets the logger used for the listener's start, stop, and error logs.
+// When not set, NewModule uses the *slog.Logger from the DI container if available,
+// and NewServer falls back to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithServeErrorHandler sets a function called with the error when the background Serve
+// goroutine fails with anything other than http.ErrServerClosed, e.g. when the listening
+// socket is closed out from under the server. A panic in the Serve goroutine is reported
+// as an error wrapping ErrServePanic. It replaces the onServeErr parameter of NewServer;
+// within NewModule it runs before the app is shut down.
+func WithServeErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.serveErrors = fn
+	}
+}
+
+// WithHandlerProvider registers provider as the constructor of the listener's named
+// http.Handler, so the handler can be built from DI dependencies instead of being
+// supplied pre-built. provider is an Fx constructor whose first result implements
+// http.Handler, optionally followed by an error, e.g.
+// func(db *sql.DB, logger *slog.Logger) (*APIHandler, error).
+// It is used by NewModule only; do not also provide the named handler elsewhere.
+func WithHandlerProvider(provider any) Option {
+	return func(o *options) {
+		o.handlerProvider = provider
+	}
+}
+
+// WithBaseContext sets http.Server.BaseContext on the constructed server, so every
+// request context derives from the context fn returns for the listening socket, e.g.
+// one carrying a logger or cancelled on shutdown. fn must not return nil. When not
+// set, request contexts derive from context.Background().
+func WithBaseContext(fn func(net.Listener) context.Context) Option {
+	return func(o *options) {
+		o.baseContext = fn
 	}
 }
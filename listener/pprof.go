@@ -0,0 +1,71 @@
+package listener
+
+import (
+	"net/http"
+	"net/http/pprof" //nolint:gosec // G108: handlers are mounted on the listener's own mux, see WithPprof
+	"strings"
+)
+
+// DefaultPprofPrefix is the path prefix used by WithPprof when none is given.
+const DefaultPprofPrefix = "/debug/pprof"
+
+// WithPprof serves the standard runtime profiling endpoints of net/http/pprof under
+// pathPrefix on the listener's mux: an index page at pathPrefix+"/", every runtime/pprof
+// profile (goroutine, heap, allocs, ...) at pathPrefix+"/<name>", plus cmdline, profile
+// (CPU, ?seconds=), trace (?seconds=) and symbol (GET or POST, as used by go tool
+// pprof). An empty pathPrefix means DefaultPprofPrefix.
+//
+// Profiling is off unless this option is used. The endpoints expose process
+// internals, so only enable them on a listener that is not publicly reachable.
+// Note that linking this package imports net/http/pprof, which registers the same
+// handlers on http.DefaultServeMux; do not serve http.DefaultServeMux publicly.
+func WithPprof(pathPrefix string) Option {
+	prefix := normalizePprofPrefix(pathPrefix)
+
+	return WithRoutes(Route{Pattern: prefix + "/", Handler: pprofHandler(prefix)})
+}
+
+// normalizePprofPrefix returns prefix with a leading and without a trailing slash.
+func normalizePprofPrefix(prefix string) string {
+	prefix = strings.TrimRight(prefix, "/")
+	if prefix == "" {
+		return DefaultPprofPrefix
+	}
+
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+
+	return prefix
+}
+
+// pprofHandler dispatches requests below prefix to the matching net/http/pprof
+// handler. pprof.Index only resolves profile names below /debug/pprof/, so named
+// profiles are looked up here. Only GET and HEAD are allowed, plus POST for symbol.
+func pprofHandler(prefix string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, prefix+"/")
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead &&
+			(name != "symbol" || r.Method != http.MethodPost) {
+			methodNotAllowed([]string{http.MethodGet}).ServeHTTP(w, r)
+
+			return
+		}
+
+		switch name {
+		case "":
+			pprof.Index(w, r)
+		case "cmdline":
+			pprof.Cmdline(w, r)
+		case "profile":
+			pprof.Profile(w, r)
+		case "trace":
+			pprof.Trace(w, r)
+		case "symbol":
+			pprof.Symbol(w, r)
+		default:
+			pprof.Handler(name).ServeHTTP(w, r)
+		}
+	})
+}
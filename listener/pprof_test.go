@@ -0,0 +1,113 @@
+package listener
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestNewModule_WithPprof(t *testing.T) {
+	t.Parallel()
+
+	addr := freePort(t)
+
+	app := fxtest.New(t, NewModule("debug", WithAddress(addr), WithPprof("")))
+
+	app.RequireStart()
+
+	get := func(path string) (*http.Response, string) {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr+path, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+		require.NoError(t, err)
+
+		defer func() { _ = resp.Body.Close() }()
+
+		body, _ := io.ReadAll(resp.Body)
+
+		return resp, string(body)
+	}
+
+	resp, body := get("/debug/pprof/")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+	assert.Contains(t, body, `href='goroutine?debug=1'`)
+	assert.Contains(t, body, `href='heap?debug=1'`)
+	assert.Contains(t, body, `href='profile?debug=1'`)
+
+	resp, body = get("/debug/pprof/goroutine?debug=1")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "goroutine profile:")
+
+	resp, _ = get("/debug/pprof/missing")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	app.RequireStop()
+}
+
+func TestWithPprof_CustomPrefix(t *testing.T) {
+	t.Parallel()
+
+	handler, err := buildRouteHandler(textHandler("app"), newOptions(WithPprof("internal/pprof/")).routes)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/internal/pprof/heap?debug=1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "heap profile:")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	assert.Equal(t, "app", rec.Body.String(), "default prefix must not be served")
+}
+
+func TestWithPprof_NotRegisteredByDefault(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, newOptions(WithAddress(":0")).routes)
+}
+
+func TestWithPprof_Symbol(t *testing.T) {
+	t.Parallel()
+
+	handler, err := buildRouteHandler(nil, newOptions(WithPprof("")).routes)
+	require.NoError(t, err)
+
+	pc := fmt.Sprintf("%#x", reflect.ValueOf(pprofHandler).Pointer())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/symbol", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "num_symbols: 1\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/symbol?"+pc, nil))
+	assert.Contains(t, rec.Body.String(), pc+" github.com/0xalexb/hjarta-di/listener.pprofHandler\n")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/pprof/symbol", strings.NewReader("0+"+pc)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), pc+" github.com/0xalexb/hjarta-di/listener.pprofHandler\n", "go tool pprof posts the addresses")
+}
+
+func TestWithPprof_MethodNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	handler, err := buildRouteHandler(textHandler("app"), newOptions(WithPprof("")).routes)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/pprof/heap", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+}
@@ -0,0 +1,123 @@
+package listener
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+)
+
+type routePatternKeyType struct{}
+
+var routePatternKey = routePatternKeyType{} //nolint:gochecknoglobals
+
+// routePattern receives the mux pattern of the route that served a request.
+type routePattern struct {
+	value string
+}
+
+// WithRequestCounter calls fn once for every completed request with its method,
+// normalized path and response status. The path is the pattern of the WithRoutes
+// route that served the request, without its method (e.g. "/users/{id}"), so metric
+// labels stay bounded; requests served by the fallback handler, or rejected before
+// reaching a route, report their cleaned URL path instead. The status includes
+// responses written by WithNamedMiddleware middlewares, e.g. a 429 from a rate limiter.
+// Requests whose handler panics are not counted. fn is called concurrently and must
+// be safe for concurrent use. A nil fn is ignored.
+//
+// There is no built-in Prometheus adapter, as this module does not depend on the
+// Prometheus client; a *prometheus.CounterVec is adapted in a few lines:
+//
+//	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+//		Name: "http_requests_total",
+//		Help: "HTTP requests by method, path and status.",
+//	}, []string{"method", "path", "status"})
+//	prometheus.MustRegister(requests)
+//
+//	listener.WithRequestCounter(func(method, path string, status int) {
+//		requests.WithLabelValues(method, path, strconv.Itoa(status)).Inc()
+//	})
+func WithRequestCounter(fn func(method, path string, status int)) Option {
+	return func(o *options) {
+		o.requestCounter = fn
+	}
+}
+
+// countRequests wraps next so fn is called once per completed request.
+func countRequests(next http.Handler, fn func(method, path string, status int)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := &routePattern{}
+		cw := &countingWriter{ResponseWriter: w}
+
+		next.ServeHTTP(cw, r.WithContext(context.WithValue(r.Context(), routePatternKey, route)))
+
+		status := cw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		fn(r.Method, requestPath(r, route.value), status)
+	})
+}
+
+// recordRoutePattern wraps a route handler so the pattern it was matched by is
+// reported to countRequests.
+func recordRoutePattern(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route, ok := r.Context().Value(routePatternKey).(*routePattern); ok {
+			route.value = r.Pattern
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestPath returns pattern without its method, or the cleaned URL path when
+// no route pattern was recorded.
+func requestPath(r *http.Request, pattern string) string {
+	if pattern == "" {
+		return path.Clean("/" + r.URL.Path)
+	}
+
+	if _, rest, found := strings.Cut(pattern, " "); found {
+		return strings.TrimLeft(rest, " \t")
+	}
+
+	return pattern
+}
+
+// countingWriter records the response status.
+type countingWriter struct {
+	http.ResponseWriter
+
+	status int
+}
+
+func (w *countingWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush forwards to the underlying writer if it supports flushing.
+func (w *countingWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *countingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
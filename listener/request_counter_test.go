@@ -0,0 +1,132 @@
+package listener
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countedRequest is one WithRequestCounter callback invocation.
+type countedRequest struct {
+	method string
+	path   string
+	status int
+}
+
+// requestLog collects WithRequestCounter callbacks.
+type requestLog struct {
+	mu       sync.Mutex
+	requests []countedRequest
+}
+
+func (l *requestLog) count(method, path string, status int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.requests = append(l.requests, countedRequest{method: method, path: path, status: status})
+}
+
+func (l *requestLog) all() []countedRequest {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]countedRequest(nil), l.requests...)
+}
+
+func TestWithRequestCounter(t *testing.T) {
+	t.Parallel()
+
+	var log requestLog
+
+	created := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	limited := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/limited" {
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	srv, err := NewServer("api", textHandler("fallback"), Config{}, nil,
+		WithRoutes(
+			Route{Method: http.MethodGet, Pattern: "/users/{id}", Handler: textHandler("user")},
+			Route{Method: http.MethodPost, Pattern: "/users", Handler: created},
+		),
+		WithNamedMiddleware("limiter", limited),
+		WithRequestCounter(log.count),
+	)
+	require.NoError(t, err)
+
+	requests := []struct {
+		method string
+		target string
+	}{
+		{method: http.MethodGet, target: "/users/42"},
+		{method: http.MethodGet, target: "/users/7?full=1"},
+		{method: http.MethodPost, target: "/users"},
+		{method: http.MethodDelete, target: "/users"},
+		{method: http.MethodGet, target: "/docs/../about"},
+		{method: http.MethodGet, target: "/limited"},
+	}
+
+	for _, req := range requests {
+		srv.server.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.target, nil))
+	}
+
+	assert.Equal(t, []countedRequest{
+		{method: http.MethodGet, path: "/users/{id}", status: http.StatusOK},
+		{method: http.MethodGet, path: "/users/{id}", status: http.StatusOK},
+		{method: http.MethodPost, path: "/users", status: http.StatusCreated},
+		{method: http.MethodDelete, path: "/users", status: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/about", status: http.StatusTemporaryRedirect},
+		{method: http.MethodGet, path: "/limited", status: http.StatusTooManyRequests},
+	}, log.all())
+}
+
+func TestWithRequestCounter_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const requests = 200
+
+	var log requestLog
+
+	srv, err := NewServer("api", textHandler("ok"), Config{}, nil, WithRequestCounter(log.count))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for range requests {
+		wg.Go(func() {
+			srv.server.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+		})
+	}
+
+	wg.Wait()
+
+	counted := log.all()
+	require.Len(t, counted, requests)
+
+	for _, req := range counted {
+		assert.Equal(t, countedRequest{method: http.MethodGet, path: "/ping", status: http.StatusOK}, req)
+	}
+}
+
+func TestWithRequestCounter_InvalidRouteStillReported(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewServer("api", nil, Config{}, nil,
+		WithRoutes(Route{Pattern: "/nil"}),
+		WithRequestCounter(func(string, string, int) {}),
+	)
+	require.ErrorIs(t, err, ErrInvalidRoute)
+}
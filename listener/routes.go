@@ -0,0 +1,112 @@
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidRoute is returned when a route cannot be registered on the listener's mux,
+// e.g. because its handler is nil or its pattern is malformed or conflicts with another route.
+var ErrInvalidRoute = errors.New("invalid route")
+
+// Route declares a handler for an http.ServeMux pattern.
+// If Method is set, the route only matches requests with that method
+// (GET also matches HEAD, as with http.ServeMux method patterns).
+type Route struct {
+	Method  string
+	Pattern string
+	Handler http.Handler
+}
+
+// WithRoutes registers routes on an http.ServeMux that serves the listener.
+// When a named http.Handler is also provided via DI, it becomes the fallback for
+// requests that match no route; otherwise the handler is optional.
+// Requests that match a route's pattern but not its method receive 405 Method Not Allowed.
+// Call multiple times to append routes.
+func WithRoutes(routes ...Route) Option {
+	return func(o *options) {
+		o.routes = append(o.routes, routes...)
+	}
+}
+
+// buildRouteHandler returns a mux serving routes with fallback as the catch-all handler.
+// It returns fallback unchanged when there are no routes.
+func buildRouteHandler(fallback http.Handler, routes []Route) (handler http.Handler, err error) {
+	if len(routes) == 0 {
+		return fallback, nil
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			handler = nil
+			err = fmt.Errorf("%w: %v", ErrInvalidRoute, rec)
+		}
+	}()
+
+	mux := http.NewServeMux()
+
+	// Patterns registered without a method already match every method.
+	anyMethod := make(map[string]bool)
+	methods := make(map[string][]string)
+
+	for _, route := range routes {
+		if route.Handler == nil {
+			return nil, fmt.Errorf("%w: nil handler for pattern %q", ErrInvalidRoute, route.Pattern)
+		}
+
+		if route.Method == "" {
+			anyMethod[route.Pattern] = true
+
+			mux.Handle(route.Pattern, route.Handler)
+
+			continue
+		}
+
+		if !slices.Contains(methods[route.Pattern], route.Method) {
+			methods[route.Pattern] = append(methods[route.Pattern], route.Method)
+		}
+
+		mux.Handle(route.Method+" "+route.Pattern, route.Handler)
+	}
+
+	if fallback == nil {
+		// Without a fallback, http.ServeMux already answers 404 and 405 itself.
+		return mux, nil
+	}
+
+	// With a catch-all fallback registered, method mismatches on exact patterns would
+	// otherwise fall through to it, so register explicit 405 responders for them.
+	// Subtree patterns (ending in "/") fall through to the fallback on method mismatch.
+	for pattern, allowed := range methods {
+		if anyMethod[pattern] || strings.HasSuffix(pattern, "/") {
+			continue
+		}
+
+		mux.Handle(pattern, methodNotAllowed(allowed))
+	}
+
+	if !anyMethod["/"] {
+		mux.Handle("/", fallback)
+	}
+
+	return mux, nil
+}
+
+func methodNotAllowed(allowed []string) http.Handler {
+	allow := slices.Clone(allowed)
+	if slices.Contains(allow, http.MethodGet) && !slices.Contains(allow, http.MethodHead) {
+		allow = append(allow, http.MethodHead)
+	}
+
+	slices.Sort(allow)
+
+	header := strings.Join(allow, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Allow", header)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	})
+}
@@ -0,0 +1,197 @@
+package listener
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func textHandler(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, body)
+	})
+}
+
+func TestBuildRouteHandler(t *testing.T) {
+	t.Parallel()
+
+	handler, err := buildRouteHandler(textHandler("fallback"), []Route{
+		{Method: http.MethodGet, Pattern: "/users", Handler: textHandler("list users")},
+		{Method: http.MethodPost, Pattern: "/users", Handler: textHandler("create user")},
+		{Method: http.MethodGet, Pattern: "/users/{id}", Handler: textHandler("get user")},
+		{Pattern: "/any", Handler: textHandler("any method")},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+		wantAllow  string
+	}{
+		{"GET route", http.MethodGet, "/users", http.StatusOK, "list users", ""},
+		{"POST route", http.MethodPost, "/users", http.StatusOK, "create user", ""},
+		{"wildcard route", http.MethodGet, "/users/42", http.StatusOK, "get user", ""},
+		{"methodless route", http.MethodDelete, "/any", http.StatusOK, "any method", ""},
+		{"unmatched falls through", http.MethodGet, "/other", http.StatusOK, "fallback", ""},
+		{"method mismatch", http.MethodDelete, "/users", http.StatusMethodNotAllowed, "", "GET, HEAD, POST"},
+		{"wildcard method mismatch", http.MethodPut, "/users/42", http.StatusMethodNotAllowed, "", "GET, HEAD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+
+			assert.Equal(t, tt.wantAllow, rec.Header().Get("Allow"))
+		})
+	}
+}
+
+func TestBuildRouteHandler_NoFallback(t *testing.T) {
+	t.Parallel()
+
+	handler, err := buildRouteHandler(nil, []Route{
+		{Method: http.MethodGet, Pattern: "/users", Handler: textHandler("list users")},
+	})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestBuildRouteHandler_NoRoutesReturnsFallback(t *testing.T) {
+	t.Parallel()
+
+	fallback := textHandler("fallback")
+
+	handler, err := buildRouteHandler(fallback, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, handler)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "fallback", rec.Body.String())
+}
+
+func TestBuildRouteHandler_InvalidRoutes(t *testing.T) {
+	t.Parallel()
+
+	_, err := buildRouteHandler(nil, []Route{{Method: http.MethodGet, Pattern: "/x"}})
+	require.ErrorIs(t, err, ErrInvalidRoute)
+
+	_, err = buildRouteHandler(nil, []Route{
+		{Method: http.MethodGet, Pattern: "/x", Handler: textHandler("a")},
+		{Method: http.MethodGet, Pattern: "/x", Handler: textHandler("b")},
+	})
+	require.ErrorIs(t, err, ErrInvalidRoute, "conflicting patterns should be reported as an error")
+}
+
+func TestNewServer_RoutesWithoutHandler(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewServer("api", nil, Config{}, nil, WithRoutes(Route{Pattern: "/", Handler: textHandler("ok")}))
+	require.NoError(t, err)
+	assert.NotNil(t, srv)
+}
+
+func TestNewModule_WithRoutes(t *testing.T) {
+	t.Parallel()
+
+	addr := freePort(t)
+
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(textHandler("fallback"), fx.As(new(http.Handler)), fx.ResultTags(`name:"api"`))),
+		NewModule("api",
+			WithAddress(addr),
+			WithRoutes(
+				Route{Method: http.MethodGet, Pattern: "/hello", Handler: textHandler("hello")},
+				Route{Method: http.MethodPost, Pattern: "/items", Handler: textHandler("created")},
+			),
+		),
+	)
+
+	app.RequireStart()
+
+	do := func(method, path string) (int, string) {
+		req, err := http.NewRequestWithContext(context.Background(), method, "http://"+addr+path, nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+		require.NoError(t, err)
+
+		defer func() { _ = resp.Body.Close() }()
+
+		body, _ := io.ReadAll(resp.Body)
+
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := do(http.MethodGet, "/hello")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "hello", body)
+
+	status, body = do(http.MethodPost, "/items")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "created", body)
+
+	status, body = do(http.MethodGet, "/unknown")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "fallback", body)
+
+	status, _ = do(http.MethodGet, "/items")
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+
+	app.RequireStop()
+}
+
+func TestNewModule_WithRoutesOnly(t *testing.T) {
+	t.Parallel()
+
+	addr := freePort(t)
+
+	app := fxtest.New(t,
+		NewModule("routes",
+			WithAddress(addr),
+			WithRoutes(Route{Method: http.MethodGet, Pattern: "/hello", Handler: textHandler("hello")}),
+		),
+	)
+
+	app.RequireStart()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr+"/hello", nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "hello", string(body))
+
+	app.RequireStop()
+}
@@ -7,10 +7,17 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/0xalexb/hjarta-di/listener/middleware"
 )
 
-// ReadHeaderTimeout is the default timeout for reading request headers.
+// ReadHeaderTimeout is the default timeout for reading request headers,
+// used when Config.ReadHeaderTimeout is zero.
 const ReadHeaderTimeout = 10 * time.Second
 
 // Server manages an HTTP server lifecycle.
@@ -18,19 +25,55 @@ type Server struct {
 	name       string
 	config     Config
 	server     *http.Server
+	mu         sync.Mutex
 	listener   net.Listener
-	onServeErr func()
+	onServeErr func(error)
+	logger     *slog.Logger
+	middleware []string
+	probe      bool
+	draining   *atomic.Bool
 }
 
 // NewServer creates a new Server with the given name, handler, and config.
 // It sets config defaults, validates the config, and creates the underlying http.Server.
-// The onServeErr callback, if non-nil, is called when the background Serve goroutine encounters a fatal error.
-func NewServer(name string, handler http.Handler, cfg Config, onServeErr func()) (*Server, error) {
+// The onServeErr callback, if non-nil, is called when the background Serve goroutine encounters a fatal error,
+// after the WithServeErrorHandler callback. The onServeErr parameter is deprecated and kept for backward
+// compatibility; pass nil and use WithServeErrorHandler, which also receives the error.
+// Routes declared via WithRoutes are served from an http.ServeMux with handler as the fallback;
+// handler may be nil only when routes are declared. The result is wrapped with the
+// WithDefaultMiddleware stack, the middlewares registered via WithNamedMiddleware, the WithRequestCounter callback, and
+// middleware.InjectListenerName so handlers can read the listener name from the request context.
+// Config options such as WithAddress and WithHandlerProvider apply to NewModule only;
+// passing them returns ErrModuleOnlyOption. Logs go to the logger set via WithLogger,
+// defaulting to slog.Default().
+func NewServer(name string, handler http.Handler, cfg Config, onServeErr func(), opts ...Option) (*Server, error) {
 	if name == "" {
 		return nil, ErrEmptyName
 	}
 
-	if handler == nil {
+	o := newOptions(opts...)
+
+	err := o.moduleOnlyOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return newServer(name, handler, cfg, onServeErr, o)
+}
+
+// newServer builds the Server from collected options; NewModule calls it directly so
+// that its config and handler provider options are not rejected.
+func newServer(name string, handler http.Handler, cfg Config, onServeErr func(), o options) (*Server, error) {
+	o.routes = slices.Clone(o.routes)
+	stack := o.middlewareStack()
+	names := middlewareNames(stack)
+	draining := new(atomic.Bool)
+
+	if o.healthPath != "" {
+		o.routes = append(o.routes, Route{Method: http.MethodGet, Pattern: o.healthPath, Handler: healthHandler(names, draining)})
+	}
+
+	if handler == nil && len(o.routes) == 0 {
 		return nil, ErrNilHandler
 	}
 
@@ -41,58 +84,231 @@ func NewServer(name string, handler http.Handler, cfg Config, onServeErr func())
 		return nil, err
 	}
 
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if o.requestCounter != nil {
+		for i := range o.routes {
+			if o.routes[i].Handler != nil {
+				o.routes[i].Handler = recordRoutePattern(o.routes[i].Handler)
+			}
+		}
+	}
+
+	handler, err = buildRouteHandler(handler, o.routes)
+	if err != nil {
+		return nil, err
+	}
+
+	handler = wrapMiddleware(handler, stack)
+	if o.requestCounter != nil {
+		handler = countRequests(handler, o.requestCounter)
+	}
+
+	logger := o.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	return &Server{
 		name:   name,
 		config: cfg,
 		server: &http.Server{
 			Addr:              cfg.Address,
-			Handler:           handler,
-			ReadHeaderTimeout: ReadHeaderTimeout,
+			Handler:           middleware.InjectListenerName(name)(handler),
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			ReadTimeout:       cfg.ReadTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+			TLSConfig:         tlsConfig,
+			BaseContext:       o.baseContext,
 		},
 		listener:   nil,
-		onServeErr: onServeErr,
+		onServeErr: serveErrorCallback(o.serveErrors, onServeErr),
+		logger:     logger,
+		middleware: names,
+		probe:      o.startupProbe,
+		draining:   draining,
 	}, nil
 }
 
-// Start begins listening on TCP and serves HTTP requests in a background goroutine.
+// Start begins listening on TCP and serves HTTP requests, or HTTPS when Config.TLSEnabled, in a background goroutine.
+// With WithStartupProbe, it returns only once that goroutine is accepting connections.
 func (s *Server) Start(ctx context.Context) error {
 	listenCfg := net.ListenConfig{}
 
 	listener, err := listenCfg.Listen(ctx, "tcp", s.server.Addr)
 	if err != nil {
-		slog.Error("failed to listen", "name", s.name, "address", s.server.Addr, "error", err)
+		s.logger.Error("failed to listen", "name", s.name, "address", s.server.Addr, "error", err)
 
 		return fmt.Errorf("%w: %w", ErrListenFailed, err)
 	}
 
+	s.mu.Lock()
 	s.listener = listener
+	s.mu.Unlock()
+
+	s.logger.Info("starting HTTP listener", "name", s.name, "address", s.server.Addr, "tls", s.config.TLSEnabled())
 
-	slog.Info("starting HTTP listener", "name", s.name, "address", s.server.Addr)
+	var (
+		serveListener = listener
+		ready         chan struct{}
+		serveExited   = make(chan struct{})
+	)
+
+	if s.probe {
+		probe := newReadyListener(listener)
+		serveListener, ready = probe, probe.ready
+	}
 
 	go func() {
-		serveErr := s.server.Serve(listener)
+		defer close(serveExited)
+
+		notified := false
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				s.handleServePanic(rec, notified)
+			}
+		}()
+
+		var serveErr error
+		if s.config.TLSEnabled() {
+			serveErr = s.server.ServeTLS(serveListener, "", "")
+		} else {
+			serveErr = s.server.Serve(serveListener)
+		}
+
 		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
-			slog.Error("HTTP listener error", "name", s.name, "error", serveErr)
+			s.logger.Error("HTTP listener error", "name", s.name, "error", serveErr)
 
 			if s.onServeErr != nil {
-				s.onServeErr()
+				notified = true
+
+				s.onServeErr(serveErr)
 			}
 		}
 	}()
 
-	return nil
+	if ready == nil {
+		return nil
+	}
+
+	return s.awaitServing(ctx, ready, serveExited)
+}
+
+// awaitServing waits until ready is closed by the first Accept. If Serve exits first,
+// or ctx ends, it closes the server and returns ErrStartupProbeFailed.
+func (s *Server) awaitServing(ctx context.Context, ready, serveExited <-chan struct{}) error {
+	select {
+	case <-ready:
+		return nil
+	case <-serveExited:
+		return fmt.Errorf("%w: %s", ErrStartupProbeFailed, s.name)
+	case <-ctx.Done():
+		_ = s.server.Close()
+
+		return fmt.Errorf("%w: %s: %w", ErrStartupProbeFailed, s.name, ctx.Err())
+	}
 }
 
-// Stop gracefully shuts down the HTTP server.
+// Addr returns the address the server is bound to, or nil before Start.
+// With an OS-assigned port (e.g. Address ":0") this reports the actual port.
+// After Stop it keeps returning the last bound address.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
+// handleServePanic logs a panic recovered from the Serve goroutine and invokes
+// onServeErr so the app can shut down instead of the process crashing.
+// If the panic originated from onServeErr itself, it is not invoked again.
+func (s *Server) handleServePanic(rec any, notified bool) {
+	s.logger.Error("HTTP listener panic recovered",
+		"name", s.name,
+		"panic", fmt.Sprintf("%v", rec),
+		"stack", string(debug.Stack()),
+	)
+
+	if notified || s.onServeErr == nil {
+		return
+	}
+
+	defer func() {
+		if nested := recover(); nested != nil {
+			s.logger.Error("onServeErr panicked", "name", s.name, "panic", fmt.Sprintf("%v", nested))
+		}
+	}()
+
+	s.onServeErr(fmt.Errorf("%w: %v", ErrServePanic, rec))
+}
+
+// serveErrorCallback combines the WithServeErrorHandler callback and the deprecated
+// onServeErr parameter into one function, or returns nil if neither is set.
+func serveErrorCallback(handler func(error), legacy func()) func(error) {
+	if handler == nil && legacy == nil {
+		return nil
+	}
+
+	return func(err error) {
+		if handler != nil {
+			handler(err)
+		}
+
+		if legacy != nil {
+			legacy()
+		}
+	}
+}
+
+// Stop gracefully shuts down the HTTP server. With Config.PreShutdownDelay set, it first
+// keeps serving for that long, with the WithHealthEndpoint route reporting 503; if ctx
+// ends during the delay, shutdown starts right away.
 func (s *Server) Stop(ctx context.Context) error {
-	slog.Info("stopping HTTP listener", "name", s.name)
+	if delay := s.config.PreShutdownDelay; delay > 0 {
+		s.logger.Info("delaying HTTP listener shutdown", "name", s.name, "delay", delay)
+		s.draining.Store(true)
+		waitPreShutdown(ctx, delay)
+	}
+
+	s.logger.Info("stopping HTTP listener", "name", s.name)
 
 	err := s.server.Shutdown(ctx)
 	if err != nil {
-		slog.Error("shutdown failed", "name", s.name, "error", err)
+		s.logger.Error("shutdown failed", "name", s.name, "error", err)
 
 		return fmt.Errorf("%w: %w", ErrShutdownFailed, err)
 	}
 
+	// Shutdown only closes listeners Serve has already registered; when Stop
+	// races a just-started Serve goroutine, close the listener here so the
+	// port is released by the time Stop returns.
+	s.mu.Lock()
+	listener := s.listener
+	s.mu.Unlock()
+
+	if listener != nil {
+		_ = listener.Close()
+	}
+
 	return nil
 }
+
+// waitPreShutdown blocks for delay or until ctx is done, whichever comes first.
+func waitPreShutdown(ctx context.Context, delay time.Duration) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -1,11 +1,24 @@
 package listener
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"log/slog"
+	"math/big"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -14,17 +27,24 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// freePort returns an address with an OS-assigned port, reported by a throwaway Server via Addr.
+// Prefer starting the server under test on "127.0.0.1:0" and reading Addr when the Server is reachable.
 func freePort(t *testing.T) string {
 	t.Helper()
 
-	listenCfg := net.ListenConfig{}
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
 
-	ln, err := listenCfg.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	srv, err := NewServer("free-port", handler, Config{Address: "127.0.0.1:0"}, nil,
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
 	require.NoError(t, err)
 
-	defer func() { _ = ln.Close() }()
+	require.NoError(t, srv.Start(context.Background()))
 
-	return ln.Addr().String()
+	addr := srv.Addr().String()
+
+	require.NoError(t, srv.Stop(context.Background()))
+
+	return addr
 }
 
 func TestNewServer_SetsDefaults(t *testing.T) {
@@ -41,19 +61,20 @@ func TestNewServer_SetsDefaults(t *testing.T) {
 func TestServer_StartStop(t *testing.T) {
 	t.Parallel()
 
-	addr := freePort(t)
 	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 
 		_, _ = fmt.Fprint(w, "hello")
 	})
 
-	srv, err := NewServer("api", handler, Config{Address: addr}, nil)
+	srv, err := NewServer("api", handler, Config{Address: "127.0.0.1:0"}, nil)
 	require.NoError(t, err)
 
 	err = srv.Start(context.Background())
 	require.NoError(t, err)
 
+	addr := srv.Addr().String()
+
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr, nil)
 	require.NoError(t, err)
 
@@ -80,6 +101,38 @@ func TestServer_StartStop(t *testing.T) {
 	assert.Error(t, dialErr, "should not be able to connect after stop")
 }
 
+func TestServer_Addr(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	srv, err := NewServer("api", handler, Config{Address: "127.0.0.1:0"}, nil)
+	require.NoError(t, err)
+
+	assert.Nil(t, srv.Addr(), "Addr should be nil before Start")
+
+	require.NoError(t, srv.Start(context.Background()))
+
+	addr := srv.Addr()
+	require.NotNil(t, addr)
+
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	require.True(t, ok)
+	assert.True(t, tcpAddr.IP.IsLoopback())
+	assert.NotZero(t, tcpAddr.Port, "Addr should report the OS-assigned port")
+
+	dialer := net.Dialer{Timeout: time.Second}
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", addr.String())
+	require.NoError(t, err)
+
+	_ = conn.Close()
+
+	require.NoError(t, srv.Stop(context.Background()))
+
+	assert.Equal(t, addr.String(), srv.Addr().String(), "Addr should keep the last bound address after Stop")
+}
+
 func TestServer_StartFailure(t *testing.T) {
 	t.Parallel()
 
@@ -127,6 +180,94 @@ func TestServer_ServeErrorCallsOnServeErr(t *testing.T) {
 	)
 }
 
+func TestServer_WithServeErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	addr := freePort(t)
+
+	errs := make(chan error, 1)
+
+	var legacyCalled atomic.Bool
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+	srv, srvErr := NewServer("test", handler, Config{Address: addr}, func() {
+		legacyCalled.Store(true)
+	}, WithServeErrorHandler(func(err error) {
+		errs <- err
+	}))
+	require.NoError(t, srvErr)
+
+	err := srv.Start(context.Background())
+	require.NoError(t, err)
+
+	// Close the underlying listener directly to force a non-ErrServerClosed error.
+	_ = srv.listener.Close()
+
+	select {
+	case serveErr := <-errs:
+		require.ErrorIs(t, serveErr, net.ErrClosed, "handler should receive the underlying serve error")
+	case <-time.After(time.Second):
+		t.Fatal("serve error handler was not called")
+	}
+
+	assert.Eventually(t, legacyCalled.Load, time.Second, 10*time.Millisecond,
+		"deprecated onServeErr should still be called")
+}
+
+// lockedBuffer is a goroutine-safe bytes.Buffer for capturing logs written from the Serve goroutine.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p) //nolint:wrapcheck
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestServer_OnServeErrPanicIsRecovered(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	var logs lockedBuffer
+
+	original := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	t.Cleanup(func() { slog.SetDefault(original) })
+
+	addr := freePort(t)
+
+	var calls atomic.Int32
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+	srv, srvErr := NewServer("test", handler, Config{Address: addr}, func() {
+		calls.Add(1)
+
+		panic("callback exploded")
+	})
+	require.NoError(t, srvErr)
+
+	err := srv.Start(context.Background())
+	require.NoError(t, err)
+
+	// Force a serve error so onServeErr is invoked and panics inside the Serve goroutine.
+	_ = srv.listener.Close()
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), "callback exploded")
+	}, time.Second, 10*time.Millisecond, "panic should be logged")
+
+	assert.Contains(t, logs.String(), "HTTP listener panic recovered")
+	assert.Equal(t, int32(1), calls.Load(), "panicking onServeErr should not be invoked again")
+}
+
 func TestNewServer_NilHandler(t *testing.T) {
 	t.Parallel()
 
@@ -177,11 +318,45 @@ func TestServer_ServeError_NilCallback(t *testing.T) {
 func TestWithAddress_Empty(t *testing.T) {
 	t.Parallel()
 
-	var cfg Config
+	var opts options
+
+	WithAddress("")(&opts)
+
+	assert.Empty(t, opts.config.Address, "WithAddress should set address even when empty")
+	assert.True(t, opts.hasConfig, "WithAddress should mark config as provided via options")
+}
+
+func TestServer_WithLogger(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
 
-	WithAddress("")(&cfg)
+	srv, err := NewServer("api", handler, Config{Address: freePort(t)}, nil, WithLogger(logger))
+	require.NoError(t, err)
 
-	assert.Empty(t, cfg.Address, "WithAddress should set address even when empty")
+	require.NoError(t, srv.Start(context.Background()))
+	require.NoError(t, srv.Stop(context.Background()))
+
+	assert.Contains(t, logs.String(), "starting HTTP listener")
+	assert.Contains(t, logs.String(), "stopping HTTP listener")
+	assert.Contains(t, logs.String(), `"name":"api"`)
+}
+
+func TestNewServer_DefaultsToSlogDefault(t *testing.T) { //nolint:paralleltest // modifies global slog default
+	original := slog.Default()
+	replacement := slog.New(slog.NewJSONHandler(io.Discard, nil))
+
+	slog.SetDefault(replacement)
+	t.Cleanup(func() { slog.SetDefault(original) })
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	srv, err := NewServer("api", handler, Config{}, nil)
+	require.NoError(t, err)
+	assert.Same(t, replacement, srv.logger)
 }
 
 func TestServer_StopWithCancelledContext(t *testing.T) {
@@ -229,3 +404,268 @@ func TestServer_StopWithCancelledContext(t *testing.T) {
 	require.Error(t, err)
 	assert.ErrorIs(t, err, ErrShutdownFailed, "error should wrap ErrShutdownFailed")
 }
+
+func TestNewServer_AppliesTimeouts(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	srv, err := NewServer("api", handler, Config{
+		ReadTimeout:  20 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  time.Minute,
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, ReadHeaderTimeout, srv.server.ReadHeaderTimeout)
+	assert.Equal(t, 20*time.Second, srv.server.ReadTimeout)
+	assert.Equal(t, 30*time.Second, srv.server.WriteTimeout)
+	assert.Equal(t, time.Minute, srv.server.IdleTimeout)
+	require.NotNil(t, srv.server.TLSConfig)
+	assert.Equal(t, uint16(tls.VersionTLS12), srv.server.TLSConfig.MinVersion)
+}
+
+func TestNewServer_InvalidTimeouts(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	srv, err := NewServer("api", handler, Config{ReadHeaderTimeout: 2 * time.Second, ReadTimeout: time.Second}, nil)
+	require.ErrorIs(t, err, ErrReadTimeoutShorterThanHeader)
+	assert.Nil(t, srv)
+}
+
+func TestServer_StopWaitsPreShutdownDelay(t *testing.T) {
+	t.Parallel()
+
+	const delay = 100 * time.Millisecond
+
+	srv, err := NewServer("api", textHandler("still serving"), Config{
+		Address:          "127.0.0.1:0",
+		PreShutdownDelay: delay,
+	}, nil)
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+
+	addr := srv.Addr().String()
+	start := time.Now()
+	stopped := make(chan error, 1)
+
+	go func() { stopped <- srv.Stop(context.Background()) }()
+
+	time.Sleep(delay / 4)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+addr, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+	require.NoError(t, err, "server should keep serving during the delay")
+
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "still serving", string(body))
+
+	require.NoError(t, <-stopped)
+	assert.GreaterOrEqual(t, time.Since(start), delay, "Stop should wait for the delay before shutting down")
+
+	dialer := net.Dialer{Timeout: 100 * time.Millisecond}
+
+	conn, dialErr := dialer.DialContext(context.Background(), "tcp", addr)
+	if dialErr == nil {
+		_ = conn.Close()
+	}
+
+	assert.Error(t, dialErr, "should not be able to connect after stop")
+}
+
+func TestServer_HealthEndpointDrainsDuringPreShutdownDelay(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewServer("api", textHandler("ok"), Config{
+		Address:          "127.0.0.1:0",
+		PreShutdownDelay: time.Minute,
+	}, nil, WithHealthEndpoint("/healthz"))
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+
+	healthStatus := func() int {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+srv.Addr().String()+"/healthz", nil)
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+		require.NoError(t, err)
+
+		_ = resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, healthStatus())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+
+	go func() { stopped <- srv.Stop(ctx) }()
+
+	require.Eventually(t, func() bool { return healthStatus() == http.StatusServiceUnavailable },
+		time.Second, 5*time.Millisecond, "health endpoint should report 503 during the delay")
+
+	cancel()
+	require.NoError(t, <-stopped)
+}
+
+func TestServer_StopPreShutdownDelayRespectsContext(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewServer("api", textHandler("ok"), Config{
+		Address:          "127.0.0.1:0",
+		PreShutdownDelay: time.Minute,
+	}, nil)
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	require.NoError(t, srv.Stop(ctx))
+	assert.Less(t, time.Since(start), time.Second, "a done context should cut the delay short")
+}
+
+func TestServer_WithBaseContext(t *testing.T) {
+	t.Parallel()
+
+	type regionKey struct{}
+
+	var baseListener net.Listener
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		region, _ := r.Context().Value(regionKey{}).(string)
+		_, _ = io.WriteString(w, region)
+	})
+
+	srv, err := NewServer("api", handler, Config{Address: "127.0.0.1:0"}, nil,
+		WithBaseContext(func(l net.Listener) context.Context {
+			baseListener = l
+
+			return context.WithValue(context.Background(), regionKey{}, "eu-west-1")
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+
+	defer func() { _ = srv.Stop(context.Background()) }()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+srv.Addr().String(), nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "eu-west-1", string(body))
+	require.NotNil(t, baseListener)
+	assert.Equal(t, srv.Addr().String(), baseListener.Addr().String())
+}
+
+func TestNewServer_RejectsModuleOnlyOptions(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
+
+	for name, opt := range map[string]Option{
+		"address":          WithAddress("127.0.0.1:0"),
+		"config option":    ConfigOption(func(cfg *Config) { cfg.IdleTimeout = time.Second }),
+		"handler provider": WithHandlerProvider(func() http.Handler { return handler }),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srv, err := NewServer("test", handler, Config{Address: "127.0.0.1:0"}, nil, opt)
+			require.ErrorIs(t, err, ErrModuleOnlyOption)
+			assert.Nil(t, srv)
+		})
+	}
+}
+
+// writeTestKeyPair writes a self-signed certificate for 127.0.0.1 and its key to a
+// temporary directory and returns the file paths.
+func writeTestKeyPair(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestServer_ServesTLS(t *testing.T) {
+	t.Parallel()
+
+	certFile, keyFile := writeTestKeyPair(t)
+
+	srv, err := NewServer("api", textHandler("secure"), Config{
+		Address:      "127.0.0.1:0",
+		TLSCertFile:  certFile,
+		TLSKeyFile:   keyFile,
+		CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+	}, nil)
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+
+	t.Cleanup(func() { _ = srv.Stop(context.Background()) })
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec // test server uses a self-signed certificate
+		MaxVersion:         tls.VersionTLS12,
+	}}}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://"+srv.Addr().String(), nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req) //nolint:gosec // G704: test code, URL from test server
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "secure", string(body))
+	require.NotNil(t, resp.TLS)
+	assert.Equal(t, tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, resp.TLS.CipherSuite, "configured suite should be negotiated")
+
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+srv.Addr().String(), nil)
+	require.NoError(t, err)
+
+	resp, err = http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+	require.NoError(t, err)
+
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "plain HTTP should not be served")
+}
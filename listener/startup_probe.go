@@ -0,0 +1,39 @@
+package listener
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+// ErrStartupProbeFailed is returned by Start when WithStartupProbe is set and the
+// server stops or the start context ends before it begins accepting connections.
+var ErrStartupProbeFailed = errors.New("listener did not start serving")
+
+// WithStartupProbe makes Start wait until the background Serve goroutine is accepting
+// connections before returning, so a request sent right after Start is served without
+// racing the goroutine. Readiness is signalled by Serve's first Accept call on the
+// listener rather than by dialing, so no request reaches the handler.
+func WithStartupProbe() Option {
+	return func(o *options) {
+		o.startupProbe = true
+	}
+}
+
+// readyListener closes ready the first time Accept is called.
+type readyListener struct {
+	net.Listener
+
+	once  sync.Once
+	ready chan struct{}
+}
+
+func newReadyListener(listener net.Listener) *readyListener {
+	return &readyListener{Listener: listener, ready: make(chan struct{})}
+}
+
+func (l *readyListener) Accept() (net.Conn, error) {
+	l.once.Do(func() { close(l.ready) })
+
+	return l.Listener.Accept() //nolint:wrapcheck
+}
@@ -0,0 +1,68 @@
+package listener
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_WithStartupProbe_ImmediateRequest(t *testing.T) {
+	t.Parallel()
+
+	srv, err := NewServer("probe", textHandler("ready"), Config{Address: "127.0.0.1:0"}, nil, WithStartupProbe())
+	require.NoError(t, err)
+
+	require.NoError(t, srv.Start(context.Background()))
+
+	t.Cleanup(func() { _ = srv.Stop(context.Background()) })
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+srv.Addr().String(), nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: test code, URL from test server
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ready", string(body))
+}
+
+func TestServer_AwaitServing(t *testing.T) {
+	t.Parallel()
+
+	closed := make(chan struct{})
+	close(closed)
+
+	srv, err := NewServer("probe", textHandler("ready"), Config{}, nil)
+	require.NoError(t, err)
+
+	t.Run("ready", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, srv.awaitServing(context.Background(), closed, make(chan struct{})))
+	})
+
+	t.Run("serve exited first", func(t *testing.T) {
+		t.Parallel()
+
+		err := srv.awaitServing(context.Background(), make(chan struct{}), closed)
+		require.ErrorIs(t, err, ErrStartupProbeFailed)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := srv.awaitServing(ctx, make(chan struct{}), make(chan struct{}))
+		require.ErrorIs(t, err, ErrStartupProbeFailed)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -13,28 +13,70 @@ type LoggerConfig struct {
 
 // NewLogger creates a new slog.Logger with JSON handler and the specified output.
 // The level is parsed from the config; defaults to INFO if invalid or empty.
-func NewLogger(config LoggerConfig, w io.Writer) *slog.Logger {
-	level := parseLevel(config.Level)
-	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+// An invalid non-empty level is reported once with a warning written to w.
+// Records are also passed to the additional handlers, filtered by the same level,
+// through a MultiHandler.
+func NewLogger(config LoggerConfig, w io.Writer, additional ...slog.Handler) *slog.Logger {
+	level, ok := parseLevel(config.Level)
+
+	var handler slog.Handler = slog.NewJSONHandler(w, &slog.HandlerOptions{
 		AddSource:   false,
 		Level:       level,
 		ReplaceAttr: nil,
 	})
 
-	return slog.New(handler)
+	if len(additional) > 0 {
+		handlers := []slog.Handler{handler}
+
+		for _, h := range additional {
+			if h != nil {
+				handlers = append(handlers, &levelHandler{level: level, handler: h})
+			}
+		}
+
+		handler = NewMultiHandler(handlers...)
+	}
+
+	logger := slog.New(handler)
+
+	if !ok {
+		logger.Warn("logging: unknown log level, using default",
+			slog.String("provided", config.Level), slog.String("default", level.String()))
+	}
+
+	return logger
+}
+
+// NewMultiWriterLogger creates a logger like NewLogger whose JSON output is copied to
+// every writer (e.g. stdout, a file and a network socket) through io.MultiWriter.
+// Each record is formatted and level-filtered once, so all writers receive identical
+// lines. Nil writers are skipped; with no writers the output is discarded. A write
+// error on one writer stops the record from reaching the writers after it.
+func NewMultiWriterLogger(config LoggerConfig, writers ...io.Writer) *slog.Logger {
+	nonNil := make([]io.Writer, 0, len(writers))
+
+	for _, w := range writers {
+		if w != nil {
+			nonNil = append(nonNil, w)
+		}
+	}
+
+	return NewLogger(config, io.MultiWriter(nonNil...))
 }
 
-func parseLevel(level string) slog.Level {
+// parseLevel converts a case-insensitive level name to a slog.Level.
+// It reports false for a non-empty unknown name; empty and unknown names yield INFO.
+func parseLevel(level string) (slog.Level, bool) {
 	switch strings.ToUpper(level) {
 	case "DEBUG":
-		return slog.LevelDebug
-	case "INFO":
-		return slog.LevelInfo
+		return slog.LevelDebug, true
+	case "INFO", "":
+		return slog.LevelInfo, true
 	case "WARN", "WARNING":
-		return slog.LevelWarn
+		return slog.LevelWarn, true
 	case "ERROR":
-		return slog.LevelError
+		return slog.LevelError, true
 	default:
-		return slog.LevelInfo
+		return slog.LevelInfo, false
 	}
 }
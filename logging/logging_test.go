@@ -121,6 +121,8 @@ func TestNewLogger_Levels(t *testing.T) {
 
 			config := logging.LoggerConfig{Level: testCase.configLevel}
 			logger := logging.NewLogger(config, &buf)
+			// Drop the unknown level warning; it is covered by TestNewLogger_WarnsOnInvalidLevel.
+			buf.Reset()
 
 			logger.Log(context.Background(), testCase.logLevel, "test message")
 
@@ -139,6 +141,46 @@ func TestNewLogger_Levels(t *testing.T) {
 	}
 }
 
+func TestNewLogger_WarnsOnInvalidLevel(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		level    string
+		wantWarn bool
+	}{
+		{name: "invalid level", level: "INVALID", wantWarn: true},
+		{name: "typo level", level: "debg", wantWarn: true},
+		{name: "empty level", level: "", wantWarn: false},
+		{name: "valid level", level: "info", wantWarn: false},
+		{name: "valid error level", level: "ERROR", wantWarn: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			logging.NewLogger(logging.LoggerConfig{Level: testCase.level}, &buf)
+
+			if !testCase.wantWarn {
+				require.Empty(t, buf.String(), "no warning should be written")
+
+				return
+			}
+
+			var logEntry map[string]any
+
+			err := json.Unmarshal(buf.Bytes(), &logEntry)
+			require.NoError(t, err, "warning should be a single JSON entry")
+			require.Equal(t, "WARN", logEntry["level"])
+			require.Equal(t, testCase.level, logEntry["provided"])
+			require.Equal(t, "INFO", logEntry["default"])
+		})
+	}
+}
+
 func TestLoggerConfig_ZeroValue(t *testing.T) {
 	t.Parallel()
 
@@ -155,3 +197,35 @@ func TestLoggerConfig_ZeroValue(t *testing.T) {
 	require.NoError(t, err, "output should be valid JSON")
 	require.Equal(t, "INFO", logEntry["level"], "default level should be INFO")
 }
+
+func TestNewMultiWriterLogger_FansOut(t *testing.T) {
+	t.Parallel()
+
+	var stdout, file bytes.Buffer
+
+	logger := logging.NewMultiWriterLogger(logging.LoggerConfig{Level: "WARN"}, &stdout, nil, &file)
+
+	logger.Info("below level")
+	require.Empty(t, stdout.String(), "records below the level should reach no writer")
+	require.Empty(t, file.String(), "records below the level should reach no writer")
+
+	logger.Warn("at level", slog.String("key", "value"))
+
+	require.NotEmpty(t, stdout.String())
+	require.Equal(t, stdout.String(), file.String(), "every writer should receive the same line")
+
+	var logEntry map[string]any
+
+	err := json.Unmarshal(file.Bytes(), &logEntry)
+	require.NoError(t, err, "output should be valid JSON")
+	require.Equal(t, "at level", logEntry["msg"])
+	require.Equal(t, "value", logEntry["key"])
+}
+
+func TestNewMultiWriterLogger_NoWriters(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewMultiWriterLogger(logging.LoggerConfig{})
+
+	require.NotPanics(t, func() { logger.Info("discarded") })
+}
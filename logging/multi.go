@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// MultiHandler is a slog.Handler that fans records out to several handlers.
+// A record is passed to every handler enabled for its level; errors returned
+// by the handlers are joined.
+type MultiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler creates a MultiHandler writing to handlers. Nil handlers are skipped.
+func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
+	nonNil := make([]slog.Handler, 0, len(handlers))
+
+	for _, h := range handlers {
+		if h != nil {
+			nonNil = append(nonNil, h)
+		}
+	}
+
+	return &MultiHandler{handlers: nonNil}
+}
+
+// Enabled reports whether any of the handlers is enabled for level.
+func (m *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handle passes a copy of record to every handler enabled for its level.
+func (m *MultiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+
+	for _, h := range m.handlers {
+		if !h.Enabled(ctx, record.Level) {
+			continue
+		}
+
+		err := h.Handle(ctx, record.Clone())
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a MultiHandler whose handlers all have attrs added.
+//
+//nolint:ireturn // slog.Handler interface requires this return type
+func (m *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+
+	return &MultiHandler{handlers: handlers}
+}
+
+// WithGroup returns a MultiHandler whose handlers all start the group name.
+//
+//nolint:ireturn // slog.Handler interface requires this return type
+func (m *MultiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		handlers[i] = h.WithGroup(name)
+	}
+
+	return &MultiHandler{handlers: handlers}
+}
+
+// levelHandler restricts a handler to records at or above a minimum level.
+type levelHandler struct {
+	level   slog.Leveler
+	handler slog.Handler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler.Handle(ctx, record) //nolint:wrapcheck
+}
+
+//nolint:ireturn // slog.Handler interface requires this return type
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: h.level, handler: h.handler.WithAttrs(attrs)}
+}
+
+//nolint:ireturn // slog.Handler interface requires this return type
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: h.level, handler: h.handler.WithGroup(name)}
+}
@@ -0,0 +1,125 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/0xalexb/hjarta-di/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureHandler records every handled record with its attributes flattened by key.
+type captureHandler struct {
+	mu      *sync.Mutex
+	records *[]map[string]any
+	attrs   []slog.Attr
+	err     error
+}
+
+func newCaptureHandler() *captureHandler {
+	return &captureHandler{mu: &sync.Mutex{}, records: &[]map[string]any{}}
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	entry := map[string]any{"msg": r.Message, "level": r.Level.String()}
+
+	for _, a := range h.attrs {
+		entry[a.Key] = a.Value.Any()
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		entry[a.Key] = a.Value.Any()
+
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	*h.records = append(*h.records, entry)
+
+	return h.err
+}
+
+//nolint:ireturn // slog.Handler interface requires this return type
+func (h *captureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+
+	return &clone
+}
+
+//nolint:ireturn // slog.Handler interface requires this return type
+func (h *captureHandler) WithGroup(string) slog.Handler { return h }
+
+func (h *captureHandler) entries() []map[string]any {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]map[string]any(nil), *h.records...)
+}
+
+func TestMultiHandler_FansOut(t *testing.T) {
+	t.Parallel()
+
+	first, second := newCaptureHandler(), newCaptureHandler()
+	logger := slog.New(logging.NewMultiHandler(first, nil, second)).With(slog.String("component", "test"))
+
+	logger.Info("hello", slog.Int("n", 1))
+	logger.Error("failed")
+
+	for _, h := range []*captureHandler{first, second} {
+		entries := h.entries()
+		require.Len(t, entries, 2)
+		assert.Equal(t, "hello", entries[0]["msg"])
+		assert.Equal(t, int64(1), entries[0]["n"])
+		assert.Equal(t, "test", entries[0]["component"])
+		assert.Equal(t, "failed", entries[1]["msg"])
+	}
+}
+
+func TestMultiHandler_JoinsErrorsAndContinues(t *testing.T) {
+	t.Parallel()
+
+	errFirst := errors.New("first failed")
+	failing, ok := newCaptureHandler(), newCaptureHandler()
+	failing.err = errFirst
+
+	err := logging.NewMultiHandler(failing, ok).Handle(context.Background(), slog.Record{Message: "x"})
+
+	require.ErrorIs(t, err, errFirst)
+	assert.Len(t, ok.entries(), 1, "later handlers should still receive the record")
+}
+
+func TestNewLogger_AdditionalHandlersShareLevel(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	first, second := newCaptureHandler(), newCaptureHandler()
+	logger := logging.NewLogger(logging.LoggerConfig{Level: "warn"}, &buf, first, second)
+
+	logger.Info("filtered out")
+	logger.Warn("kept", slog.String("key", "value"))
+
+	var logEntry map[string]any
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logEntry), "JSON output should hold one entry")
+	assert.Equal(t, "kept", logEntry["msg"])
+
+	for _, h := range []*captureHandler{first, second} {
+		entries := h.entries()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "kept", entries[0]["msg"])
+		assert.Equal(t, "value", entries[0]["key"])
+	}
+}
@@ -1,15 +1,42 @@
 package di
 
 import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+
 	"github.com/0xalexb/hjarta-di/listener"
 
 	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
 )
 
+// DefaultEnvVar is the environment variable holding the current environment
+// name, compared by WithEnvOptions unless WithEnvVar names another one.
+const DefaultEnvVar = "APP_ENV"
+
 // Options holds configuration settings for the application.
 type Options struct {
 	Modules  []fx.Option
 	LogLevel string
+	// LogHandlers receive every record of the application logger in addition to
+	// the JSON output, filtered by LogLevel.
+	LogHandlers []slog.Handler
+	// FxEventLogLevels overrides the level of non-error Fx event logs per event type,
+	// keyed by the pointer type of the event (e.g. *fxevent.Supplied).
+	FxEventLogLevels map[reflect.Type]slog.Level
+	// EnvVar is the environment variable read by WithEnvOptions; DefaultEnvVar when empty.
+	EnvVar string
+
+	envOptions []envOptions
+}
+
+// envOptions holds options applied only in the named environment.
+type envOptions struct {
+	env  string
+	opts []Option
 }
 
 // Option defines a function type for applying configuration options.
@@ -32,6 +59,19 @@ func WithHTTPListener(name string, opts ...listener.Option) Option {
 	}
 }
 
+// WithFeatureFlags registers provider in the DI container as a FeatureFlagProvider,
+// so modules can inject it to query feature flags. Use it at most once per App.
+// A nil provider is ignored.
+func WithFeatureFlags(provider FeatureFlagProvider) Option {
+	return func(o *Options) {
+		if provider == nil {
+			return
+		}
+
+		o.Modules = append(o.Modules, fx.Supply(fx.Annotate(provider, fx.As(new(FeatureFlagProvider)))))
+	}
+}
+
 // WithLogLevel sets the log level for the application.
 // Valid levels are: "debug", "info", "warn", "error".
 // If not set or invalid, defaults to "info".
@@ -40,3 +80,132 @@ func WithLogLevel(level string) Option {
 		opts.LogLevel = level
 	}
 }
+
+// WithAdditionalLogHandler adds a handler receiving every record of the application
+// logger alongside the default JSON output on stderr, e.g. to forward errors to an
+// alerting service. The handler only receives records at or above the configured
+// log level (see WithLogLevel); it may filter further via its Enabled method.
+// Call multiple times to add several handlers. Nil handlers are ignored.
+func WithAdditionalLogHandler(h slog.Handler) Option {
+	return func(opts *Options) {
+		if h != nil {
+			opts.LogHandlers = append(opts.LogHandlers, h)
+		}
+	}
+}
+
+// WithFxSupplyLogLevel sets the level at which Fx logs events of the same type as event,
+// e.g. WithFxSupplyLogLevel(&fxevent.Supplied{}, slog.LevelDebug). Other events keep the
+// default info level, and failed events are still logged at error level.
+func WithFxSupplyLogLevel(event fxevent.Event, level slog.Level) Option {
+	return func(opts *Options) {
+		if event == nil {
+			return
+		}
+
+		if opts.FxEventLogLevels == nil {
+			opts.FxEventLogLevels = make(map[reflect.Type]slog.Level)
+		}
+
+		opts.FxEventLogLevels[reflect.TypeOf(event)] = level
+	}
+}
+
+// WithSilentSupply logs the Fx "supplied" event of every fx.Supply at debug level
+// instead of info, so supplying many values does not flood the default log output.
+func WithSilentSupply() Option {
+	return WithFxSupplyLogLevel(&fxevent.Supplied{}, slog.LevelDebug)
+}
+
+// WithEnvVar sets the environment variable holding the current environment name
+// for WithEnvOptions (default DefaultEnvVar, "APP_ENV").
+func WithEnvVar(name string) Option {
+	return func(opts *Options) {
+		opts.EnvVar = name
+	}
+}
+
+// WithEnvOptions applies opts only when the current environment, read from the
+// APP_ENV environment variable (or the one set via WithEnvVar), equals env, e.g.
+// WithEnvOptions("production", WithLogLevel("warn")). The environment is checked
+// once in NewApp after all other options, so opts take precedence over options
+// given outside WithEnvOptions regardless of their order.
+func WithEnvOptions(env string, opts ...Option) Option {
+	return func(o *Options) {
+		o.envOptions = append(o.envOptions, envOptions{env: env, opts: opts})
+	}
+}
+
+// applyEnvOptions applies the WithEnvOptions entries matching the current environment,
+// including entries nested in them.
+func (o *Options) applyEnvOptions() {
+	name := o.EnvVar
+	if name == "" {
+		name = DefaultEnvVar
+	}
+
+	current := os.Getenv(name)
+
+	for len(o.envOptions) > 0 {
+		pending := o.envOptions
+		o.envOptions = nil
+
+		for _, entry := range pending {
+			if entry.env != current {
+				continue
+			}
+
+			for _, apply := range entry.opts {
+				if apply != nil {
+					apply(o)
+				}
+			}
+		}
+	}
+}
+
+// optionsJSON is the JSON form of Options produced by MarshalJSON.
+type optionsJSON struct {
+	LogLevel    string   `json:"log_level"`
+	ModuleCount int      `json:"module_count"`
+	Modules     []string `json:"modules"`
+}
+
+// MarshalJSON serializes the options for debugging, e.g. to attach to a bug report:
+// the log level, the number of modules, and a name per module. Fx options are not
+// serializable, so a module is named by its fx.Module name when it has one (found by
+// reflection) and by its Go type otherwise.
+func (o Options) MarshalJSON() ([]byte, error) {
+	modules := make([]string, 0, len(o.Modules))
+	for _, module := range o.Modules {
+		modules = append(modules, moduleName(module))
+	}
+
+	data, err := json.Marshal(optionsJSON{
+		LogLevel:    o.LogLevel,
+		ModuleCount: len(o.Modules),
+		Modules:     modules,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling options: %w", err)
+	}
+
+	return data, nil
+}
+
+// moduleName returns the name of an fx.Module option, read from its unexported name
+// field, or the Go type of other options.
+func moduleName(module fx.Option) string {
+	value := reflect.ValueOf(module)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Struct {
+		if name := value.FieldByName("name"); name.Kind() == reflect.String && name.String() != "" {
+			return name.String()
+		}
+	}
+
+	return fmt.Sprintf("%T", module)
+}
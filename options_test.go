@@ -2,6 +2,7 @@ package di_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -10,6 +11,7 @@ import (
 
 	di "github.com/0xalexb/hjarta-di"
 	"github.com/0xalexb/hjarta-di/listener"
+	"github.com/0xalexb/hjarta-di/logging"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -247,3 +249,100 @@ func TestWithHTTPListener_MultipleListeners(t *testing.T) {
 
 	require.NoError(t, app.Stop())
 }
+
+// appLogLevel returns the log level the app built with opts supplies to DI.
+func appLogLevel(t *testing.T, opts ...di.Option) string {
+	t.Helper()
+
+	var cfg logging.LoggerConfig
+
+	require.NoError(t, di.NewApp(opts...).Populate(&cfg))
+
+	return cfg.Level
+}
+
+func TestWithEnvOptions(t *testing.T) { //nolint:paralleltest // modifies environment variables
+	testCases := []struct {
+		name     string
+		appEnv   string
+		expected string
+	}{
+		{name: "matching environment", appEnv: "production", expected: "warn"},
+		{name: "other environment", appEnv: "development", expected: "info"},
+		{name: "unset environment", appEnv: "", expected: "info"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Setenv(di.DefaultEnvVar, testCase.appEnv)
+
+			level := appLogLevel(t,
+				di.WithEnvOptions("production", di.WithLogLevel("warn")),
+				di.WithLogLevel("info"),
+			)
+
+			assert.Equal(t, testCase.expected, level)
+		})
+	}
+}
+
+func TestWithEnvOptions_CustomEnvVar(t *testing.T) { //nolint:paralleltest // modifies environment variables
+	t.Setenv(di.DefaultEnvVar, "development")
+	t.Setenv("DEPLOY_ENV", "production")
+
+	level := appLogLevel(t,
+		di.WithEnvOptions("production", di.WithLogLevel("warn")),
+		di.WithEnvVar("DEPLOY_ENV"),
+	)
+
+	assert.Equal(t, "warn", level)
+}
+
+func TestWithEnvOptions_Nested(t *testing.T) { //nolint:paralleltest // modifies environment variables
+	t.Setenv(di.DefaultEnvVar, "production")
+
+	level := appLogLevel(t,
+		di.WithEnvOptions("production",
+			di.WithEnvOptions("production", di.WithLogLevel("error")),
+		),
+	)
+
+	assert.Equal(t, "error", level)
+}
+
+func TestOptions_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var opts di.Options
+
+	di.WithLogLevel("debug")(&opts)
+	di.WithHTTPListener("api")(&opts)
+	di.WithModules(fx.Module("billing"), fx.Supply(42))(&opts)
+
+	data, err := json.Marshal(opts)
+	require.NoError(t, err)
+
+	var decoded struct {
+		LogLevel    string   `json:"log_level"`
+		ModuleCount int      `json:"module_count"`
+		Modules     []string `json:"modules"`
+	}
+
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "debug", decoded.LogLevel)
+	assert.Equal(t, 3, decoded.ModuleCount)
+	require.Len(t, decoded.Modules, 3)
+	assert.Equal(t, "api", decoded.Modules[0])
+	assert.Equal(t, "billing", decoded.Modules[1])
+	assert.NotEmpty(t, decoded.Modules[2], "unnamed options are reported by type")
+}
+
+func TestApp_OptionsJSON(t *testing.T) {
+	t.Parallel()
+
+	app := di.NewApp(di.WithLogLevel("warn"), di.WithModules(fx.Module("billing")))
+
+	data, err := app.OptionsJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"log_level":"warn","module_count":1,"modules":["billing"]}`, string(data))
+}